@@ -165,6 +165,7 @@ func TestBackgroundReposync(t *testing.T) {
 
 func TestBackgroundMkLoaders(t *testing.T) {
 	c := createStubHTTPClientSingle(t, "background-mkloaders")
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
 
 	res, err := c.BackgroundMkLoaders()
 	FailOnError(t, err)
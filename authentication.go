@@ -1,66 +1,66 @@
 package cobblerclient
 
+import "time"
+
 // CheckAccessNoFail validates if a certain resource can be accessed with the current token. "arg1" and "arg2" have
 // different meanings depending on the authorization provider configured server side.
 func (c *Client) CheckAccessNoFail(resource, arg1, arg2 string) (bool, error) {
-	result, err := c.Call("check_access_no_fail", c.Token, resource, arg1, arg2)
+	result, err := c.Call("check_access_no_fail", c.Token(), resource, arg1, arg2)
 	if err != nil {
 		return false, err
-	} else {
-		convertedInteger, err := convertToInt(result)
-		if err != nil {
-			return convertIntBool(convertedInteger)
-		}
+	}
+	convertedInteger, err := asInt("check_access_no_fail", result)
+	if err != nil {
 		return false, err
 	}
+	return convertIntBool(convertedInteger)
 }
 
 // CheckAccess performs the same check as [Client.CheckAccessNoFail] but returning the error message with the
 // reason instead of a boolean.
 func (c *Client) CheckAccess(resource, arg1, arg2 string) (int, error) {
-	result, err := c.Call("check_access", c.Token, resource, arg1, arg2)
+	result, err := c.Call("check_access", c.Token(), resource, arg1, arg2)
 	if err != nil {
 		return -1, err
-	} else {
-		convertedInteger, err := convertToInt(result)
-		if err != nil {
-			return -1, err
-		}
-		return convertedInteger, err
 	}
+	return asInt("check_access", result)
 }
 
 // GetAuthnModuleName retrieves the currently configured authentication module name.
 func (c *Client) GetAuthnModuleName() (string, error) {
-	res, err := c.Call("get_authn_module_name", c.Token)
-	return returnString(res, err)
+	res, err := c.Call("get_authn_module_name", c.Token())
+	return returnString("get_authn_module_name", res, err)
 }
 
 // Login performs a login request to Cobbler using the credentials provided in the configuration in the initializer.
 func (c *Client) Login() (bool, error) {
 	result, err := c.Call("login", c.config.Username, c.config.Password)
+	token, err := returnString("login", result, err)
 	if err != nil {
 		return false, err
 	}
 
-	c.Token = result.(string)
+	if c.session == nil {
+		c.session = &session{}
+	}
+	c.session.setToken(token, time.Now())
 	return true, nil
 }
 
 // Logout performs a logout from the Cobbler server.
 func (c *Client) Logout() (bool, error) {
-	res, err := c.Call("logout", c.Token)
-	return returnBool(res, err)
+	res, err := c.Call("logout", c.Token())
+	return returnBool("logout", res, err)
 }
 
 // TokenCheck returns if a given token is still valid or not.
 func (c *Client) TokenCheck(token string) (bool, error) {
 	res, err := c.Call("token_check", token)
-	return returnBool(res, err)
+	return returnBool("token_check", res, err)
 }
 
 // GetUserFromToken checks what user a given token is belonging to.
 func (c *Client) GetUserFromToken(token string) (string, error) {
 	res, err := c.Call("get_user_from_token", token)
-	return returnString(res, err)
+	return returnString("get_user_from_token", res, err)
 }
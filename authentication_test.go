@@ -45,8 +45,8 @@ func TestLogin(t *testing.T) {
 	}
 
 	expected := "sa/1EWr40BWU+Pq3VEOOpD4cQtxkeMuFUw=="
-	if c.Token != expected {
-		t.Errorf(`"%s" expected; got "%s"`, expected, c.Token)
+	if c.Token() != expected {
+		t.Errorf(`"%s" expected; got "%s"`, expected, c.Token())
 	}
 }
 
@@ -0,0 +1,138 @@
+package cobblerclient
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/kolo/xmlrpc"
+)
+
+// FieldError describes a single attribute that could not be modified during a batched update.
+type FieldError struct {
+	Field string
+	Value interface{}
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// BatchUpdateError is returned when one or more modifications of a batched update failed. In contrast to sequential
+// updates, a batch is not aborted at the first failure, thus all failed attributes are reported.
+type BatchUpdateError struct {
+	// What is the item type, e.g. "system".
+	What string
+	// Handle is the object id the modifications were applied to.
+	Handle   string
+	Failures []*FieldError
+}
+
+func (e *BatchUpdateError) Error() string {
+	messages := make([]string, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		messages = append(messages, failure.Error())
+	}
+	return fmt.Sprintf("%d modification(s) of %s %s failed: %s", len(e.Failures), e.What, e.Handle,
+		strings.Join(messages, "; "))
+}
+
+// Is reports whether any of the failed modifications matches target.
+func (e *BatchUpdateError) Is(target error) bool {
+	for _, failure := range e.Failures {
+		if errors.Is(failure, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first failed modification that matches target, e.g. a [*DecodeError] for a malformed result.
+func (e *BatchUpdateError) As(target interface{}) bool {
+	for _, failure := range e.Failures {
+		if errors.As(failure, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// multicallSupported returns false once the server rejected a "system.multicall" request.
+func (c *Client) multicallSupported() bool {
+	return c.session == nil || atomic.LoadInt32(&c.session.multicallUnsupported) == 0
+}
+
+// setMulticallUnsupported remembers for all copies of the client that the server doesn't support multicalls.
+func (c *Client) setMulticallUnsupported() {
+	if c.session != nil {
+		atomic.StoreInt32(&c.session.multicallUnsupported, 1)
+	}
+}
+
+// isMulticallUnsupported checks if the server rejected the "system.multicall" method itself.
+func isMulticallUnsupported(err error) bool {
+	var fault *CobblerFault
+	return errors.As(err, &fault) && strings.Contains(fault.Message, "system.multicall")
+}
+
+// multicallFieldUpdates sends all modifications for a single object handle with one "system.multicall" request. The
+// server executes them in order and reports the result of each call individually.
+func (c *Client) multicallFieldUpdates(what, id string, updates []fieldUpdate) error {
+	method := fmt.Sprintf("modify_%s", what)
+	calls := make([]interface{}, 0, len(updates))
+	for _, update := range updates {
+		calls = append(calls, map[string]interface{}{
+			"methodName": method,
+			"params":     []interface{}{id, update.field, update.value, c.Token()},
+		})
+	}
+
+	result, err := c.Call("system.multicall", calls)
+	if err != nil {
+		return err
+	}
+	results, ok := result.([]interface{})
+	if !ok || len(results) != len(updates) {
+		return &DecodeError{Method: "system.multicall", Expected: fmt.Sprintf("array of %d results", len(updates)),
+			Value: result}
+	}
+
+	batchErr := &BatchUpdateError{What: what, Handle: id}
+	for i, update := range updates {
+		value, err := unwrapMulticallResult(method, results[i])
+		if err == nil {
+			err = checkFieldUpdateResult(what, id, update, value)
+		}
+		if err != nil {
+			batchErr.Failures = append(batchErr.Failures, &FieldError{Field: update.field, Value: update.value, Err: err})
+		}
+	}
+	if len(batchErr.Failures) > 0 {
+		return batchErr
+	}
+	return nil
+}
+
+// unwrapMulticallResult extracts the result of a single call inside a multicall response. Successful calls are
+// wrapped in an array with one element, failed calls are represented by a fault struct.
+func unwrapMulticallResult(method string, result interface{}) (interface{}, error) {
+	switch typedResult := result.(type) {
+	case []interface{}:
+		if len(typedResult) == 1 {
+			return typedResult[0], nil
+		}
+	case map[string]interface{}:
+		faultString, okString := typedResult["faultString"].(string)
+		faultCode, errCode := convertToInt(typedResult["faultCode"])
+		if okString && errCode == nil {
+			return nil, newCobblerFault(method, xmlrpc.FaultError{Code: faultCode, String: faultString})
+		}
+	}
+	return nil, &DecodeError{Method: "system.multicall", Expected: "array with one result or fault struct", Value: result}
+}
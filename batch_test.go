@@ -0,0 +1,196 @@
+package cobblerclient
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cobbler/cobblerclient/cobblertest"
+)
+
+// funcHTTPClient answers every request with the XML returned by the function.
+type funcHTTPClient func(call *cobblertest.Call) string
+
+func (f funcHTTPClient) Post(uri, bodyType string, req io.Reader) (*http.Response, error) {
+	call, err := cobblertest.DecodeCall(req)
+	if err != nil {
+		return nil, err
+	}
+	body := fmt.Sprintf("<?xml version='1.0'?>\n<methodResponse>%s</methodResponse>", f(call))
+	return &http.Response{Body: io.NopCloser(bytes.NewBufferString(body))}, nil
+}
+
+func xmlrpcParams(value string) string {
+	return fmt.Sprintf("<params><param><value>%s</value></param></params>", value)
+}
+
+func xmlrpcFaultStruct(message string) string {
+	return fmt.Sprintf("<struct><member><name>faultCode</name><value><int>1</int></value></member>"+
+		"<member><name>faultString</name><value><string>%s</string></value></member></struct>", message)
+}
+
+func xmlrpcFault(message string) string {
+	return "<fault><value>" + xmlrpcFaultStruct(message) + "</value></fault>"
+}
+
+func reflectValue(item interface{}) reflect.Value {
+	return reflect.ValueOf(item).Elem()
+}
+
+// multicallFields returns the attribute names of all modifications inside a multicall request.
+func multicallFields(call *cobblertest.Call) []string {
+	var fields []string
+	for _, nested := range call.Params[0].([]interface{}) {
+		params := nested.(map[string]interface{})["params"].([]interface{})
+		fields = append(fields, params[1].(string))
+	}
+	return fields
+}
+
+func TestUpdateFieldsBatched(t *testing.T) {
+	// Arrange
+	var methods []string
+	var batched []string
+	hc := funcHTTPClient(func(call *cobblertest.Call) string {
+		methods = append(methods, call.Method)
+		batched = multicallFields(call)
+		results := strings.Repeat("<value><array><data><value><boolean>1</boolean></value></data></array></value>", len(batched))
+		return xmlrpcParams("<array><data>" + results + "</data></array>")
+	})
+	cfg := config
+	cfg.BatchUpdates = true
+	c := NewClient(hc, cfg)
+	c.SetToken("securetoken99")
+	sys := NewSystem()
+	sys.Name = "test"
+	sys.Profile = "testprof"
+	sys.Interfaces["eth0"] = NewInterface()
+
+	// Act
+	err := c.updateCobblerFields("system", reflectValue(&sys), "system::1")
+
+	// Assert
+	FailOnError(t, err)
+	if len(methods) != 1 || methods[0] != "system.multicall" {
+		t.Fatalf("expected a single multicall, got %v", methods)
+	}
+	expected := len(collectFieldUpdates("system", reflectValue(&sys)))
+	if len(batched) != expected {
+		t.Fatalf("expected %d batched modifications, got %d", expected, len(batched))
+	}
+	if !stringInSlice("modify_interface", batched) || !stringInSlice("hostname", batched) {
+		t.Fatalf("expected interface and hostname modifications in the batch, got %v", batched)
+	}
+}
+
+func TestUpdateFieldsBatchedFailures(t *testing.T) {
+	// Arrange
+	hc := funcHTTPClient(func(call *cobblertest.Call) string {
+		var results string
+		for _, field := range multicallFields(call) {
+			switch field {
+			case "hostname":
+				results += "<value>" + xmlrpcFaultStruct("&lt;class 'ValueError'&gt;:invalid hostname") + "</value>"
+			case "gateway":
+				results += "<value><array><data><value><boolean>0</boolean></value></data></array></value>"
+			default:
+				results += "<value><array><data><value><boolean>1</boolean></value></data></array></value>"
+			}
+		}
+		return xmlrpcParams("<array><data>" + results + "</data></array>")
+	})
+	cfg := config
+	cfg.BatchUpdates = true
+	c := NewClient(hc, cfg)
+	sys := NewSystem()
+	sys.Name = "test"
+	sys.Profile = "testprof"
+
+	// Act
+	err := c.updateCobblerFields("system", reflectValue(&sys), "system::1")
+
+	// Assert
+	var batchErr *BatchUpdateError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected a BatchUpdateError, got %v", err)
+	}
+	if len(batchErr.Failures) != 2 || batchErr.Failures[0].Field != "gateway" || batchErr.Failures[1].Field != "hostname" {
+		t.Fatalf("expected gateway and hostname to fail, got %v", batchErr)
+	}
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected the batch error to match ErrValidation")
+	}
+}
+
+func TestUpdateFieldsBatchedMalformedResult(t *testing.T) {
+	tests := map[string]func(fields []string) string{
+		"missing results": func(fields []string) string {
+			return "<value><array><data><value><boolean>1</boolean></value></data></array></value>"
+		},
+		"invalid result": func(fields []string) string {
+			return strings.Repeat("<value><string>ok</string></value>", len(fields))
+		},
+	}
+	for name, results := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			hc := funcHTTPClient(func(call *cobblertest.Call) string {
+				return xmlrpcParams("<array><data>" + results(multicallFields(call)) + "</data></array>")
+			})
+			cfg := config
+			cfg.BatchUpdates = true
+			c := NewClient(hc, cfg)
+			sys := NewSystem()
+			sys.Name = "test"
+
+			// Act
+			err := c.updateCobblerFields("system", reflectValue(&sys), "system::1")
+
+			// Assert
+			var decodeErr *DecodeError
+			if !errors.As(err, &decodeErr) || decodeErr.Method != "system.multicall" {
+				t.Fatalf("expected a DecodeError of system.multicall, got %v", err)
+			}
+			if !errors.Is(err, ErrMalformedResponse) {
+				t.Errorf("expected the error to match ErrMalformedResponse")
+			}
+		})
+	}
+}
+
+func TestUpdateFieldsBatchedFallback(t *testing.T) {
+	// Arrange
+	var methods []string
+	hc := funcHTTPClient(func(call *cobblertest.Call) string {
+		methods = append(methods, call.Method)
+		if call.Method == "system.multicall" {
+			return xmlrpcFault(`&lt;class 'Exception'&gt;:method "system.multicall" is not supported`)
+		}
+		return xmlrpcParams("<boolean>1</boolean>")
+	})
+	cfg := config
+	cfg.BatchUpdates = true
+	c := NewClient(hc, cfg)
+	distro := NewDistro()
+	distro.Name = "test"
+	updates := len(collectFieldUpdates("distro", reflectValue(&distro)))
+
+	// Act
+	err := c.updateCobblerFields("distro", reflectValue(&distro), "distro::1")
+	FailOnError(t, err)
+	err = c.updateCobblerFields("distro", reflectValue(&distro), "distro::1")
+	FailOnError(t, err)
+
+	// Assert
+	if len(methods) != 1+2*updates {
+		t.Fatalf("expected one multicall and %d sequential calls, got %d calls", 2*updates, len(methods))
+	}
+	if methods[1] != "modify_distro" {
+		t.Fatalf("expected a sequential modify_distro call after the fallback, got %s", methods[1])
+	}
+}
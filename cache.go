@@ -0,0 +1,264 @@
+package cobblerclient
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// readCache holds the items read by a client with [ClientConfig.ReadCache] enabled. It is shared between all copies
+// of the client.
+type readCache struct {
+	mu          sync.Mutex
+	collections map[string]*cachedCollection
+}
+
+// cachedCollection holds the cached items of a single item type.
+type cachedCollection struct {
+	mu sync.Mutex
+	// mtime is the "last_modified_time" of the server when items were synchronized.
+	mtime float64
+	// items is the complete collection in the order of the server. It is nil until the collection was read once.
+	items []interface{}
+	// index maps the names of the items to their position in items.
+	index map[string]int
+	// singles holds the items that were read one by one while the collection isn't loaded. They are valid as long as
+	// the "last_modified_time" of the server equals singlesMtime.
+	singles      map[string]interface{}
+	singlesMtime float64
+}
+
+func newReadCache() *readCache {
+	return &readCache{collections: make(map[string]*cachedCollection)}
+}
+
+// collection returns the cache for the given item type.
+func (r *readCache) collection(what string) *cachedCollection {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	collection, ok := r.collections[what]
+	if !ok {
+		collection = &cachedCollection{}
+		r.collections[what] = collection
+	}
+	return collection
+}
+
+// clear drops all cached items. Synchronizations that are in progress fill orphaned collections.
+func (r *readCache) clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collections = make(map[string]*cachedCollection)
+}
+
+// invalidatesCache checks if a call of the given XML-RPC method may modify items on the server.
+func invalidatesCache(method string) bool {
+	return isMutatingMethod(method)
+}
+
+// itemCache describes how the items of one type are read from the server when they aren't cached.
+type itemCache[T any] struct {
+	client *Client
+	// what is the item type as used by "get_item_names", e.g. "system".
+	what string
+	// all reads the complete collection.
+	all func() ([]*T, error)
+	// since reads all items that were modified after the given time.
+	since func(time.Time) ([]*T, error)
+	// one reads a single item.
+	one func(name string, flattened, resolved bool) (*T, error)
+}
+
+// list returns the complete collection. Without a cache, the collection is read from the server.
+func (t itemCache[T]) list() ([]*T, error) {
+	if t.client.cache == nil {
+		return t.all()
+	}
+	collection := t.client.cache.collection(t.what)
+	collection.mu.Lock()
+	defer collection.mu.Unlock()
+
+	if err := t.synchronize(collection); err != nil {
+		return nil, err
+	}
+	items := make([]*T, 0, len(collection.items))
+	for _, item := range collection.items {
+		items = append(items, copyItem(item.(*T)))
+	}
+	return items, nil
+}
+
+// get returns a single item. Flattened and resolved items are never cached. If the complete collection is cached,
+// the item is taken from it, otherwise the item is cached on its own.
+func (t itemCache[T]) get(name string, flattened, resolved bool) (*T, error) {
+	if t.client.cache == nil || flattened || resolved {
+		return t.one(name, flattened, resolved)
+	}
+	collection := t.client.cache.collection(t.what)
+	collection.mu.Lock()
+	defer collection.mu.Unlock()
+
+	if collection.items != nil {
+		if err := t.synchronize(collection); err != nil {
+			return nil, err
+		}
+		i, ok := collection.index[name]
+		if !ok {
+			return nil, notFoundError(t.what, name)
+		}
+		return copyItem(collection.items[i].(*T)), nil
+	}
+
+	mtime, err := t.client.LastModifiedTime()
+	if err != nil {
+		return nil, err
+	}
+	if collection.singles == nil || mtime != collection.singlesMtime {
+		collection.singles = make(map[string]interface{})
+		collection.singlesMtime = mtime
+	}
+	if item, ok := collection.singles[name]; ok {
+		return copyItem(item.(*T)), nil
+	}
+	item, err := t.one(name, false, false)
+	if err != nil {
+		return nil, err
+	}
+	collection.singles[name] = item
+	return copyItem(item), nil
+}
+
+// synchronize brings the collection up to date in case the "last_modified_time" of the server moved forward. A
+// collection that was read before is only updated with the items modified in the meantime. Since deleted items aren't
+// reported as modified, the names of all items are compared as well.
+func (t itemCache[T]) synchronize(collection *cachedCollection) error {
+	// The time is read first, thus items modified during the synchronization are fetched again next time.
+	mtime, err := t.client.LastModifiedTime()
+	if err != nil {
+		return err
+	}
+	if collection.items != nil && mtime <= collection.mtime {
+		return nil
+	}
+
+	if collection.items == nil {
+		items, err := t.all()
+		if err != nil {
+			return err
+		}
+		collection.items = make([]interface{}, 0, len(items))
+		for _, item := range items {
+			collection.items = append(collection.items, item)
+		}
+	} else {
+		modified, err := t.since(time.Unix(int64(collection.mtime), 0))
+		if err != nil {
+			return err
+		}
+		names, err := t.client.GetItemNames(t.what)
+		if err != nil {
+			return err
+		}
+		collection.items = mergeItems(collection.items, modified, names)
+	}
+
+	collection.index = make(map[string]int, len(collection.items))
+	for i, item := range collection.items {
+		collection.index[itemName(item)] = i
+	}
+	collection.mtime = mtime
+	collection.singles = nil
+	return nil
+}
+
+// mergeItems replaces the cached items with their modified versions and removes all items whose name isn't part of
+// names anymore. New items are appended in the order in which they were returned.
+func mergeItems[T any](cached []interface{}, modified []*T, names []string) []interface{} {
+	existing := make(map[string]bool, len(names))
+	for _, name := range names {
+		existing[name] = true
+	}
+	modifiedByName := make(map[string]*T, len(modified))
+	for _, item := range modified {
+		modifiedByName[itemName(item)] = item
+	}
+
+	merged := make([]interface{}, 0, len(names))
+	for _, item := range cached {
+		name := itemName(item)
+		if !existing[name] {
+			continue
+		}
+		if replacement, ok := modifiedByName[name]; ok {
+			item = replacement
+			delete(modifiedByName, name)
+		}
+		merged = append(merged, item)
+	}
+	for _, item := range modified {
+		name := itemName(item)
+		if _, ok := modifiedByName[name]; ok && existing[name] {
+			merged = append(merged, item)
+		}
+	}
+	return merged
+}
+
+// itemName returns the name of a pointer to an item struct.
+func itemName(item interface{}) string {
+	return reflect.ValueOf(item).Elem().FieldByName("Name").String()
+}
+
+// copyItem returns a deep copy of item, thus callers can't modify the cached item.
+func copyItem[T any](item *T) *T {
+	return deepCopy(reflect.ValueOf(item)).Interface().(*T)
+}
+
+// deepCopy copies pointers, interfaces, maps, slices and the exported fields of structs recursively.
+func deepCopy(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.New(value.Type().Elem())
+		copied.Elem().Set(deepCopy(value.Elem()))
+		return copied
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(deepCopy(value.Elem()))
+		return copied
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return copied
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(deepCopy(value.Index(i)))
+		}
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(value)
+		for i := 0; i < value.NumField(); i++ {
+			if field := copied.Field(i); field.CanSet() {
+				field.Set(deepCopy(value.Field(i)))
+			}
+		}
+		return copied
+	}
+	return value
+}
@@ -0,0 +1,220 @@
+package cobblerclient
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/cobbler/cobblerclient/cobblertest"
+)
+
+// cacheTestServer simulates the parts of the Cobbler API needed to synchronize the cached systems.
+type cacheTestServer struct {
+	mu    sync.Mutex
+	mtime float64
+	// systems are the names of all systems in the order of the server.
+	systems []string
+	// modified are the systems returned by "get_systems_since".
+	modified []string
+	hostname string
+	calls    map[string]int
+}
+
+func newCacheTestServer(systems ...string) *cacheTestServer {
+	return &cacheTestServer{mtime: 1000, systems: systems, hostname: "original", calls: make(map[string]int)}
+}
+
+func (s *cacheTestServer) client(t *testing.T) Client {
+	cfg := config
+	cfg.ReadCache = true
+	c := NewClient(funcHTTPClient(func(call *cobblertest.Call) string {
+		return s.answer(t, call)
+	}), cfg)
+	c.SetToken("securetoken99")
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
+	return c
+}
+
+func (s *cacheTestServer) answer(t *testing.T, call *cobblertest.Call) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[call.Method]++
+	switch call.Method {
+	case "last_modified_time":
+		return xmlrpcParams(fmt.Sprintf("<double>%f</double>", s.mtime))
+	case "get_systems":
+		return xmlrpcParams(xmlrpcValue(t, s.rawSystems(t, s.systems...)))
+	case "get_systems_since":
+		return xmlrpcParams(xmlrpcValue(t, s.rawSystems(t, s.modified...)))
+	case "get_item_names":
+		return xmlrpcParams(xmlrpcValue(t, s.systems))
+	case "get_system":
+		return xmlrpcParams(xmlrpcValue(t, s.rawSystems(t, call.Params[0].(string))[0]))
+	case "remove_system":
+		return xmlrpcParams("<boolean>1</boolean>")
+	}
+	t.Fatalf("unexpected call of %s", call.Method)
+	return ""
+}
+
+func (s *cacheTestServer) rawSystems(t *testing.T, names ...string) []interface{} {
+	systems := rawSystems(t, names...)
+	for _, system := range systems {
+		system.(map[string]interface{})["hostname"] = s.hostname
+	}
+	return systems
+}
+
+// modify simulates a change of the given systems by another client.
+func (s *cacheTestServer) modify(systems []string, modified []string, hostname string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mtime++
+	s.systems = systems
+	s.modified = modified
+	s.hostname = hostname
+}
+
+func (s *cacheTestServer) count(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+func systemNames(systems []*System) []string {
+	names := make([]string, 0, len(systems))
+	for _, system := range systems {
+		names = append(names, system.Name)
+	}
+	return names
+}
+
+func TestReadCacheUnchanged(t *testing.T) {
+	// Arrange
+	server := newCacheTestServer("a", "b")
+	c := server.client(t)
+	_, err := c.GetSystems()
+	FailOnError(t, err)
+
+	// Act
+	systems, err := c.GetSystems()
+
+	// Assert
+	FailOnError(t, err)
+	if !reflect.DeepEqual(systemNames(systems), []string{"a", "b"}) {
+		t.Errorf("[a b] expected; got %v", systemNames(systems))
+	}
+	if server.count("get_systems") != 1 || server.count("last_modified_time") != 2 {
+		t.Errorf("expected a single read of the systems, got %v", server.calls)
+	}
+}
+
+func TestReadCacheIncremental(t *testing.T) {
+	// Arrange
+	server := newCacheTestServer("a", "b", "c")
+	c := server.client(t)
+	_, err := c.GetSystems()
+	FailOnError(t, err)
+	server.modify([]string{"a", "c", "d"}, []string{"d", "c"}, "modified")
+
+	// Act
+	systems, err := c.GetSystems()
+
+	// Assert
+	FailOnError(t, err)
+	if !reflect.DeepEqual(systemNames(systems), []string{"a", "c", "d"}) {
+		t.Fatalf("[a c d] expected; got %v", systemNames(systems))
+	}
+	if systems[0].Hostname != "original" || systems[1].Hostname != "modified" || systems[2].Hostname != "modified" {
+		t.Errorf("expected only the modified systems to be updated")
+	}
+	if server.count("get_systems") != 1 || server.count("get_systems_since") != 1 {
+		t.Errorf("expected an incremental refresh, got %v", server.calls)
+	}
+}
+
+func TestReadCacheInvalidatedByWrite(t *testing.T) {
+	// Arrange
+	server := newCacheTestServer("a", "b")
+	c := server.client(t)
+	_, err := c.GetSystems()
+	FailOnError(t, err)
+
+	// Act
+	FailOnError(t, c.WithContext(c.Context()).DeleteSystem("a"))
+	_, err = c.GetSystems()
+
+	// Assert
+	FailOnError(t, err)
+	if server.count("get_systems") != 2 {
+		t.Errorf("expected the systems to be read again after a write, got %v", server.calls)
+	}
+}
+
+func TestReadCacheSingleItem(t *testing.T) {
+	// Arrange
+	server := newCacheTestServer("a", "b")
+	c := server.client(t)
+
+	// Act
+	_, firstErr := c.GetSystem("a", false, false)
+	_, cachedErr := c.GetSystem("a", false, false)
+	_, flattenedErr := c.GetSystem("a", true, false)
+	server.modify([]string{"a", "b"}, []string{"a"}, "modified")
+	system, modifiedErr := c.GetSystem("a", false, false)
+
+	// Assert
+	FailOnError(t, firstErr)
+	FailOnError(t, cachedErr)
+	FailOnError(t, flattenedErr)
+	FailOnError(t, modifiedErr)
+	if server.count("get_system") != 3 {
+		t.Errorf("expected 3 reads of the system, got %v", server.calls)
+	}
+	if system.Hostname != "modified" {
+		t.Errorf("expected the modified system, got %s", system.Hostname)
+	}
+}
+
+func TestReadCacheItemFromCollection(t *testing.T) {
+	// Arrange
+	server := newCacheTestServer("a", "b")
+	c := server.client(t)
+	_, err := c.GetSystems()
+	FailOnError(t, err)
+
+	// Act
+	system, err := c.GetSystem("b", false, false)
+	_, notFoundErr := c.GetSystem("c", false, false)
+
+	// Assert
+	FailOnError(t, err)
+	if system.Name != "b" || server.count("get_system") != 0 {
+		t.Errorf("expected the system to be taken from the cached collection, got %v", server.calls)
+	}
+	if !errors.Is(notFoundErr, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", notFoundErr)
+	}
+}
+
+func TestReadCacheReturnsCopies(t *testing.T) {
+	// Arrange
+	server := newCacheTestServer("a")
+	c := server.client(t)
+	systems, err := c.GetSystems()
+	FailOnError(t, err)
+
+	// Act
+	systems[0].Hostname = "changed"
+	systems[0].Interfaces["eth9"] = NewInterface()
+	systems[0].NameServers = append(systems[0].NameServers, "192.0.2.1")
+	cached, err := c.GetSystems()
+
+	// Assert
+	FailOnError(t, err)
+	if cached[0].Hostname != "original" || len(cached[0].Interfaces) != 0 || len(cached[0].NameServers) != 0 {
+		t.Errorf("expected the cached system to be unchanged, got %+v", cached[0])
+	}
+}
@@ -0,0 +1,144 @@
+package cobblerclient
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Capability is a part of the Cobbler API that isn't available on all server versions.
+type Capability string
+
+const (
+	// CapabilityMenus is the "menu" item type and all of its methods.
+	CapabilityMenus Capability = "menus"
+	// CapabilityMkLoaders is the "mkloaders" action, which copies the bootloaders to the TFTP root.
+	CapabilityMkLoaders Capability = "mkloaders"
+	// CapabilityResolvedItems is the "resolved" argument of the get_<item> methods.
+	CapabilityResolvedItems Capability = "resolved-items"
+	// CapabilityRepoProxy is the "proxy" attribute of repositories.
+	CapabilityRepoProxy Capability = "repo-proxy"
+)
+
+// capabilityVersions is the first version of Cobbler that has a capability.
+var capabilityVersions = map[Capability]CobblerVersion{
+	CapabilityMenus:         {3, 3, 0},
+	CapabilityMkLoaders:     {3, 3, 0},
+	CapabilityResolvedItems: {3, 3, 4},
+	CapabilityRepoProxy:     {3, 3, 0},
+}
+
+// methodCapabilities are the XML-RPC methods that need a capability.
+var methodCapabilities = map[string]Capability{
+	"background_mkloaders": CapabilityMkLoaders,
+}
+
+// fieldCapabilities are the attributes, prefixed by their item type, that need a capability. Updates of these
+// attributes are skipped for servers without the capability.
+var fieldCapabilities = map[string]Capability{
+	"repo.proxy": CapabilityRepoProxy,
+}
+
+func init() {
+	for _, method := range itemMethods("menu") {
+		methodCapabilities[method] = CapabilityMenus
+	}
+}
+
+// itemMethods returns the names of all XML-RPC methods that are specific to the given item type.
+func itemMethods(what string) []string {
+	methods := make([]string, 0, 12)
+	for _, format := range []string{"get_%s", "get_%ss", "get_%ss_since", "get_%s_handle", "get_%s_as_rendered",
+		"find_%s", "new_%s", "modify_%s", "save_%s", "copy_%s", "rename_%s", "remove_%s"} {
+		methods = append(methods, fmt.Sprintf(format, what))
+	}
+	return methods
+}
+
+// Supports checks if a server of this version has the given capability. Unknown capabilities are never supported.
+func (cv *CobblerVersion) Supports(capability Capability) bool {
+	required, ok := capabilityVersions[capability]
+	return ok && !cv.LessThan(&required)
+}
+
+// Supports checks if the server has the given capability. The version of the server is fetched if it isn't cached
+// yet.
+func (c *Client) Supports(capability Capability) (bool, error) {
+	if err := c.ensureCachedVersion(); err != nil {
+		return false, err
+	}
+	version := c.CachedVersion()
+	return version.Supports(capability), nil
+}
+
+// requireCapability returns an [UnsupportedError] for method if the server lacks the given capability.
+func (c *Client) requireCapability(method string, capability Capability) error {
+	supported, err := c.Supports(capability)
+	if err != nil || supported {
+		return err
+	}
+	return &UnsupportedError{
+		Method:     method,
+		Capability: capability,
+		Required:   capabilityVersions[capability],
+		Server:     c.CachedVersion(),
+	}
+}
+
+// checkMethodCapability is consulted before every call. Methods that don't need a capability are always allowed,
+// otherwise the version of the server is checked.
+func (c *Client) checkMethodCapability(method string) error {
+	capability, ok := methodCapabilities[method]
+	if !ok {
+		return nil
+	}
+	return c.requireCapability(method, capability)
+}
+
+// supportedFieldUpdates removes the modifications of attributes the server doesn't know.
+func (c *Client) supportedFieldUpdates(what string, updates []fieldUpdate) ([]fieldUpdate, error) {
+	supported := make([]fieldUpdate, 0, len(updates))
+	for _, update := range updates {
+		if capability, ok := fieldCapabilities[what+"."+update.field]; ok {
+			hasCapability, err := c.Supports(capability)
+			if err != nil {
+				return nil, err
+			}
+			if !hasCapability {
+				continue
+			}
+		}
+		supported = append(supported, update)
+	}
+	return supported, nil
+}
+
+// UnsupportedError is returned without contacting the server if a call needs a capability the server doesn't have.
+// It matches [ErrUnsupportedByServer].
+type UnsupportedError struct {
+	// Method is the XML-RPC method that was called.
+	Method     string
+	Capability Capability
+	// Required is the first version of Cobbler that has the capability.
+	Required CobblerVersion
+	// Server is the version of the server.
+	Server CobblerVersion
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s: %s needs %s, which requires Cobbler %s or newer but the server runs %s",
+		ErrUnsupportedByServer, e.Method, e.Capability, versionString(e.Required), versionString(e.Server))
+}
+
+func (e *UnsupportedError) Is(target error) bool {
+	return target == ErrUnsupportedByServer
+}
+
+func versionString(version CobblerVersion) string {
+	return fmt.Sprintf("%d.%d.%d", version.Major, version.Minor, version.Patch)
+}
+
+// isUnsupportedMethodFault checks if the message of a fault is the one Python uses for unknown XML-RPC methods.
+// Example: method "get_menus" is not supported
+func isUnsupportedMethodFault(message string) bool {
+	return strings.HasPrefix(message, "method ") && strings.HasSuffix(message, " is not supported")
+}
@@ -0,0 +1,163 @@
+package cobblerclient
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/cobbler/cobblerclient/cobblertest"
+)
+
+var (
+	cobbler32 = CobblerVersion{3, 2, 2}
+	cobbler33 = CobblerVersion{3, 3, 3}
+	cobbler34 = CobblerVersion{3, 4, 0}
+)
+
+// versionedClient returns a client for a server of the given version. All calls are recorded and answered by answer.
+func versionedClient(t *testing.T, version CobblerVersion, calls *[]*cobblertest.Call,
+	answer func(call *cobblertest.Call) string) Client {
+	c := NewClient(funcHTTPClient(func(call *cobblertest.Call) string {
+		*calls = append(*calls, call)
+		if call.Method == "extended_version" {
+			return xmlrpcParams(xmlrpcValue(t, map[string]interface{}{
+				"gitdate":       "Mon Jun 13 16:13:33 2022 +0200",
+				"gitstamp":      "0e20f01b",
+				"builddate":     "Mon Jun 27 06:34:23 2022",
+				"version":       versionString(version),
+				"version_tuple": []interface{}{version.Major, version.Minor, version.Patch},
+			}))
+		}
+		return answer(call)
+	}), config)
+	c.SetToken("securetoken99")
+	return c
+}
+
+func TestCapabilityMatrix(t *testing.T) {
+	expected := map[Capability][]bool{
+		CapabilityMenus:         {false, true, true},
+		CapabilityMkLoaders:     {false, true, true},
+		CapabilityResolvedItems: {false, false, true},
+		CapabilityRepoProxy:     {false, true, true},
+		Capability("unknown"):   {false, false, false},
+	}
+	for capability, supported := range expected {
+		for i, version := range []CobblerVersion{cobbler32, cobbler33, cobbler34} {
+			// Arrange
+			var calls []*cobblertest.Call
+			c := versionedClient(t, version, &calls, nil)
+
+			// Act
+			result, err := c.Supports(capability)
+
+			// Assert
+			FailOnError(t, err)
+			if result != supported[i] {
+				t.Errorf("%s on %s: %t expected; got %t", capability, versionString(version), supported[i], result)
+			}
+		}
+	}
+}
+
+func TestCapabilityItemArguments(t *testing.T) {
+	expected := map[CobblerVersion][]interface{}{
+		cobbler32: {"a", false, "securetoken99"},
+		cobbler33: {"a", false, "securetoken99"},
+		cobbler34: {"a", false, false, "securetoken99"},
+	}
+	for version, expectedArgs := range expected {
+		t.Run(versionString(version), func(t *testing.T) {
+			// Arrange
+			var calls []*cobblertest.Call
+			c := versionedClient(t, version, &calls, func(call *cobblertest.Call) string {
+				return xmlrpcParams(xmlrpcValue(t, rawSystems(t, "a")[0]))
+			})
+
+			// Act
+			_, err := c.GetSystem("a", false, false)
+
+			// Assert
+			FailOnError(t, err)
+			args := calls[len(calls)-1].Params
+			if !reflect.DeepEqual(args, expectedArgs) {
+				t.Errorf("%v expected; got %v", expectedArgs, args)
+			}
+		})
+	}
+}
+
+func TestCapabilityUnsupportedMethod(t *testing.T) {
+	// Arrange
+	var calls []*cobblertest.Call
+	c := versionedClient(t, cobbler32, &calls, func(call *cobblertest.Call) string {
+		t.Errorf("unexpected call of %s", call.Method)
+		return ""
+	})
+
+	// Act
+	_, menusErr := c.GetMenus()
+	_, mkloadersErr := c.BackgroundMkLoaders()
+
+	// Assert
+	var unsupportedErr *UnsupportedError
+	if !errors.As(menusErr, &unsupportedErr) || unsupportedErr.Capability != CapabilityMenus ||
+		unsupportedErr.Server != cobbler32 {
+		t.Errorf("expected an UnsupportedError for menus, got %v", menusErr)
+	}
+	if !errors.Is(mkloadersErr, ErrUnsupportedByServer) {
+		t.Errorf("expected ErrUnsupportedByServer, got %v", mkloadersErr)
+	}
+	if len(calls) != 1 {
+		t.Errorf("expected only the version to be fetched, got %d calls", len(calls))
+	}
+}
+
+func TestCapabilityUnsupportedMethodFault(t *testing.T) {
+	// Arrange
+	var calls []*cobblertest.Call
+	c := versionedClient(t, cobbler34, &calls, func(call *cobblertest.Call) string {
+		return xmlrpcFault(fmt.Sprintf(`&lt;class 'Exception'&gt;:method "%s" is not supported`, call.Method))
+	})
+
+	// Act
+	_, err := c.GetMenus()
+
+	// Assert
+	if !errors.Is(err, ErrUnsupportedByServer) {
+		t.Errorf("expected ErrUnsupportedByServer, got %v", err)
+	}
+}
+
+func TestCapabilityUnsupportedField(t *testing.T) {
+	for _, version := range []CobblerVersion{cobbler32, cobbler33, cobbler34} {
+		t.Run(versionString(version), func(t *testing.T) {
+			// Arrange
+			var calls []*cobblertest.Call
+			c := versionedClient(t, version, &calls, func(call *cobblertest.Call) string {
+				if call.Method == "get_item_handle" {
+					return xmlrpcParams("<string>repo::testrepo</string>")
+				}
+				return xmlrpcParams("<boolean>1</boolean>")
+			})
+			repo := NewRepo()
+			repo.Name = "testrepo"
+
+			// Act
+			err := c.UpdateRepo(&repo)
+
+			// Assert
+			FailOnError(t, err)
+			sentProxy := false
+			for _, call := range calls {
+				if call.Method == "modify_repo" && call.Params[1] == "proxy" {
+					sentProxy = true
+				}
+			}
+			if sentProxy != version.Supports(CapabilityRepoProxy) {
+				t.Errorf("proxy sent: %t, supported: %t", sentProxy, version.Supports(CapabilityRepoProxy))
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Package cassette records the XML-RPC exchanges of a cobblerclient.Client with a live Cobbler server and replays them
+// in tests. A recording session looks like this:
+//
+//	recorder := cassette.NewRecorder(http.DefaultClient, cassette.DefaultOptions())
+//	c := cobblerclient.NewClient(recorder, config)
+//	// ... exercise the client ...
+//	err := recorder.Cassette().Save("fixtures/cassettes/create-system.yaml")
+//
+// The tests then use a [Player] instead of the recorder:
+//
+//	recorded, err := cassette.Load("fixtures/cassettes/create-system.yaml")
+//	c := cobblerclient.NewClient(cassette.NewPlayer(recorded, cassette.DefaultOptions()), config)
+//
+// Tokens and passwords are redacted and volatile values like "mtime" and "uid" are normalized while recording (see
+// [Options]), thus cassettes can be committed and compared.
+package cassette
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvRecord is the environment variable that switches [Open] to recording if it is set to a non-empty value.
+const EnvRecord = "COBBLER_RECORD"
+
+// Cassette is an ordered list of recorded exchanges.
+type Cassette struct {
+	Interactions []Interaction `yaml:"interactions"`
+}
+
+// Interaction is a single request and the response of the server. Both bodies are normalized.
+type Interaction struct {
+	// Method is the XML-RPC method of the request. It is only informational.
+	Method     string `yaml:"method"`
+	Request    string `yaml:"request"`
+	StatusCode int    `yaml:"status_code"`
+	Response   string `yaml:"response"`
+}
+
+// Load reads a cassette that was written by [Cassette.Save].
+func Load(path string) (*Cassette, error) {
+	content, err := os.ReadFile(path) // #nosec G304 -- the path is provided by the test
+	if err != nil {
+		return nil, err
+	}
+	var cassette Cassette
+	if err = yaml.Unmarshal(content, &cassette); err != nil {
+		return nil, fmt.Errorf("cassette: parsing %s: %w", path, err)
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette as YAML. Missing parent directories are created.
+func (c *Cassette) Save(path string) error {
+	content, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0o600)
+}
+
+// WriteFixtures writes every interaction as a pair of fixture files as used by the tests of cobblerclient, i.e.
+// "<name>-req.xml" and "<name>-res.xml" in dir. If the cassette holds more than one interaction, the names are
+// numbered starting with "<name>-1". The names of the fixtures are returned in the order of the interactions.
+func (c *Cassette) WriteFixtures(dir, name string) ([]string, error) {
+	names := make([]string, 0, len(c.Interactions))
+	for i, interaction := range c.Interactions {
+		fixture := name
+		if len(c.Interactions) > 1 {
+			fixture = fmt.Sprintf("%s-%d", name, i+1)
+		}
+		if err := os.WriteFile(filepath.Join(dir, fixture+"-req.xml"), []byte(interaction.Request), 0o600); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(filepath.Join(dir, fixture+"-res.xml"), []byte(interaction.Response), 0o600); err != nil {
+			return nil, err
+		}
+		names = append(names, fixture)
+	}
+	return names, nil
+}
@@ -0,0 +1,55 @@
+package cassette
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "cassettes", "ping.yaml")
+	cassette := &Cassette{Interactions: []Interaction{{
+		Method:     "ping",
+		Request:    "<methodCall>\n  <methodName>ping</methodName>\n</methodCall>\n",
+		StatusCode: 200,
+		Response:   "<methodResponse>\n  <params></params>\n</methodResponse>\n",
+	}}}
+
+	// Act
+	saveErr := cassette.Save(path)
+	loaded, loadErr := Load(path)
+
+	// Assert
+	if saveErr != nil || loadErr != nil {
+		t.Fatal(saveErr, loadErr)
+	}
+	if !reflect.DeepEqual(cassette, loaded) {
+		t.Errorf("expected the loaded cassette to equal the saved one, got %+v", loaded)
+	}
+}
+
+func TestWriteFixtures(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	cassette := &Cassette{Interactions: []Interaction{
+		{Method: "login", Request: "<login-request/>", Response: "<login-response/>"},
+		{Method: "ping", Request: "<ping-request/>", Response: "<ping-response/>"},
+	}}
+
+	// Act
+	names, err := cassette.WriteFixtures(dir, "ping")
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(names, []string{"ping-1", "ping-2"}) {
+		t.Errorf("expected numbered fixtures, got %v", names)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "ping-2-res.xml"))
+	if err != nil || string(content) != "<ping-response/>" {
+		t.Errorf("expected the response of the second interaction, got %q (%v)", content, err)
+	}
+}
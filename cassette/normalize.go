@@ -0,0 +1,304 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Options control how the bodies of requests and responses are normalized.
+type Options struct {
+	// Token replaces every token returned by "login".
+	Token string
+	// Password replaces the password sent with "login".
+	Password string
+	// Redacted replaces the values of SensitiveFields.
+	Redacted string
+	// Secrets are additional strings that are replaced by Redacted wherever they occur, e.g. a token that was obtained
+	// outside of the recording.
+	Secrets []string
+	// SensitiveFields are the names of item attributes whose values are redacted, both in the structs returned by the
+	// server and in "modify_*" calls.
+	SensitiveFields []string
+	// VolatileFields maps the names of item attributes to the constant value they are replaced with. The result of
+	// "last_modified_time" is replaced with the value of "mtime".
+	VolatileFields map[string]string
+}
+
+// DefaultOptions returns the options that produce fixtures that match the test configuration of cobblerclient: the
+// token is "securetoken99" and the password "cobbler".
+func DefaultOptions() Options {
+	return Options{
+		Token:           "securetoken99",
+		Password:        "cobbler",
+		Redacted:        "REDACTED",
+		SensitiveFields: []string{"power_pass", "default_password_crypted", "password"},
+		VolatileFields: map[string]string{
+			"ctime": "1700000000.0",
+			"mtime": "1700000000.0",
+			"uid":   "00000000000000000000000000000000",
+		},
+	}
+}
+
+// normalizer rewrites XML-RPC bodies according to the [Options]. Values that are only known while recording, like the
+// token returned by "login" or the handles of new items, are learned from the exchanges and replaced in all following
+// bodies.
+type normalizer struct {
+	options Options
+
+	mu sync.Mutex
+	// learned maps the original values to their replacements.
+	learned map[string]string
+	// replacer replaces the learned values wherever they occur. It is nil until it is needed after a value was learned.
+	replacer *strings.Replacer
+	// handles counts the learned handles and event ids.
+	handles int
+}
+
+func newNormalizer(options Options) *normalizer {
+	return &normalizer{options: options, learned: make(map[string]string)}
+}
+
+// request normalizes the body of a method call and returns the name of the method.
+func (n *normalizer) request(body []byte) (string, string, error) {
+	var method string
+	normalized, err := n.rewrite(body, func(scalar scalarContext) string {
+		if scalar.element == "methodName" {
+			method = scalar.text
+			return scalar.text
+		}
+		switch {
+		case method == "login" && scalar.topLevel && scalar.param == 1:
+			return n.learn(scalar.text, n.options.Password)
+		case strings.HasPrefix(method, "modify_") && scalar.topLevel && scalar.param == 2 &&
+			n.isSensitive(scalar.params[1]):
+			return n.options.Redacted
+		}
+		return n.replace(scalar)
+	})
+	return method, normalized, err
+}
+
+// response normalizes the body of the response to a call of method.
+func (n *normalizer) response(method string, body []byte) (string, error) {
+	return n.rewrite(body, func(scalar scalarContext) string {
+		if scalar.topLevel && scalar.param == 0 && !scalar.fault {
+			switch {
+			case method == "login":
+				return n.learn(scalar.text, n.options.Token)
+			case method == "last_modified_time" && n.options.VolatileFields["mtime"] != "":
+				return n.options.VolatileFields["mtime"]
+			case strings.HasPrefix(method, "new_"):
+				return n.learnHandle(scalar.text, "handle")
+			case strings.HasPrefix(method, "background_"):
+				return n.learnHandle(scalar.text, "event")
+			}
+		}
+		return n.replace(scalar)
+	})
+}
+
+// replace applies the rules that don't depend on the method.
+func (n *normalizer) replace(scalar scalarContext) string {
+	if scalar.member != "" {
+		if n.isSensitive(scalar.member) {
+			return n.options.Redacted
+		}
+		if value, ok := n.options.VolatileFields[scalar.member]; ok {
+			return value
+		}
+	}
+	text := n.learnedReplacer().Replace(scalar.text)
+	for _, secret := range n.options.Secrets {
+		if secret != "" {
+			text = strings.ReplaceAll(text, secret, n.options.Redacted)
+		}
+	}
+	return text
+}
+
+// learnedReplacer returns the replacer of the learned values. Longer values are replaced first, so a value that
+// contains another one is replaced as a whole.
+func (n *normalizer) learnedReplacer() *strings.Replacer {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.replacer == nil {
+		values := make([]string, 0, len(n.learned))
+		for value := range n.learned {
+			if value != "" {
+				values = append(values, value)
+			}
+		}
+		sort.Slice(values, func(i, j int) bool {
+			if len(values[i]) != len(values[j]) {
+				return len(values[i]) > len(values[j])
+			}
+			return values[i] < values[j]
+		})
+		pairs := make([]string, 0, 2*len(values))
+		for _, value := range values {
+			pairs = append(pairs, value, n.learned[value])
+		}
+		n.replacer = strings.NewReplacer(pairs...)
+	}
+	return n.replacer
+}
+
+func (n *normalizer) isSensitive(field string) bool {
+	for _, sensitive := range n.options.SensitiveFields {
+		if field == sensitive {
+			return true
+		}
+	}
+	return false
+}
+
+// learn remembers that value is replaced with replacement from now on.
+func (n *normalizer) learn(value, replacement string) string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if value != "" && value != replacement {
+		n.learned[value] = replacement
+		n.replacer = nil
+	}
+	return replacement
+}
+
+// learnHandle replaces a handle or event id with a numbered one. Handles like "___NEW___system::abc123==" keep their
+// prefix.
+func (n *normalizer) learnHandle(value, kind string) string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if replacement, ok := n.learned[value]; ok {
+		return replacement
+	}
+	n.handles++
+	replacement := fmt.Sprintf("%s-%d", kind, n.handles)
+	if i := strings.LastIndex(value, "::"); i >= 0 {
+		replacement = fmt.Sprintf("%s::%d", value[:i], n.handles)
+	}
+	n.learned[value] = replacement
+	n.replacer = nil
+	return replacement
+}
+
+// scalarContext describes the text of a scalar value and where it occurs in the body.
+type scalarContext struct {
+	text string
+	// element is the name of the element that contains the text, e.g. "string" or "methodName".
+	element string
+	// member is the name of the innermost struct member the value belongs to.
+	member string
+	// topLevel is set if the value is a parameter itself and not nested inside an array or struct.
+	topLevel bool
+	// param is the index of the parameter the value belongs to. It is -1 outside of the parameters.
+	param int
+	// params are the texts of the top level parameters up to the current one.
+	params []string
+	// fault is set for the value of a fault response.
+	fault bool
+}
+
+// scalarElements are the elements whose text is passed to the rewrite function. <value> only counts if it has no
+// type element, i.e. for untyped strings.
+var scalarElements = map[string]bool{
+	"string": true, "int": true, "i4": true, "i8": true, "boolean": true, "double": true, "base64": true,
+	"dateTime.iso8601": true, "methodName": true, "value": true, "name": true,
+}
+
+// rewrite passes the text of every scalar value of body to replace and returns the indented result. Whitespace between
+// elements is dropped, thus equivalent bodies are normalized to the same text.
+func (n *normalizer) rewrite(body []byte, replace func(scalarContext) string) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	var out bytes.Buffer
+	encoder := xml.NewEncoder(&out)
+	encoder.Indent("", "  ")
+
+	// members holds the name of the current member for every open <struct>.
+	var members []string
+	var params []string
+	nesting, fault := 0, false
+	// text collects the character data of the innermost open scalar element.
+	var text *bytes.Buffer
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "param":
+				params = append(params, "")
+			case "fault":
+				fault = true
+			case "struct":
+				members = append(members, "")
+				nesting++
+			case "array":
+				nesting++
+			}
+			text = nil
+			if scalarElements[t.Name.Local] {
+				text = &bytes.Buffer{}
+			}
+			err = encoder.EncodeToken(t.Copy())
+		case xml.CharData:
+			if text != nil {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			if text != nil {
+				value := text.String()
+				text = nil
+				switch {
+				case t.Name.Local == "name" && len(members) > 0:
+					members[len(members)-1] = value
+				case t.Name.Local != "name":
+					scalar := scalarContext{text: value, element: t.Name.Local, topLevel: nesting == 0,
+						param: len(params) - 1, params: params, fault: fault}
+					if len(members) > 0 {
+						scalar.member = members[len(members)-1]
+					}
+					value = replace(scalar)
+					if scalar.topLevel && scalar.param >= 0 {
+						params[scalar.param] = value
+					}
+				}
+				if err = encoder.EncodeToken(xml.CharData(value)); err != nil {
+					return "", err
+				}
+			}
+			switch t.Name.Local {
+			case "struct":
+				members = members[:len(members)-1]
+				nesting--
+			case "array":
+				nesting--
+			}
+			err = encoder.EncodeToken(t)
+		case xml.ProcInst:
+			if err = encoder.EncodeToken(t.Copy()); err == nil {
+				err = encoder.EncodeToken(xml.CharData("\n"))
+			}
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	if err := encoder.Flush(); err != nil {
+		return "", err
+	}
+	out.WriteString("\n")
+	return out.String(), nil
+}
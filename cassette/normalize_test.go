@@ -0,0 +1,102 @@
+package cassette
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeRequestLogin(t *testing.T) {
+	// Arrange
+	n := newNormalizer(DefaultOptions())
+	body := `<?xml version="1.0"?><methodCall><methodName>login</methodName><params>` +
+		`<param><value><string>admin</string></value></param>` +
+		`<param><value><string>s3cret</string></value></param></params></methodCall>`
+
+	// Act
+	method, normalized, err := n.request([]byte(body))
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method != "login" {
+		t.Errorf("expected the method login, got %s", method)
+	}
+	if strings.Contains(normalized, "s3cret") || !strings.Contains(normalized, "<string>cobbler</string>") {
+		t.Errorf("expected the password to be replaced:\n%s", normalized)
+	}
+	if !strings.Contains(normalized, "<string>admin</string>") {
+		t.Errorf("expected the username to be kept:\n%s", normalized)
+	}
+}
+
+func TestNormalizeLearnedValues(t *testing.T) {
+	// Arrange
+	n := newNormalizer(DefaultOptions())
+	_, err := n.response("login", []byte(`<methodResponse><params><param><value><string>sa/1EWr40BWU==</string>`+
+		`</value></param></params></methodResponse>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	handle, err := n.response("new_system", []byte(`<methodResponse><params><param><value>`+
+		`<string>___NEW___system::abc123==</string></value></param></params></methodResponse>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	_, modify, err := n.request([]byte(`<methodCall><methodName>modify_system</methodName><params>` +
+		`<param><value><string>___NEW___system::abc123==</string></value></param>` +
+		`<param><value><string>power_pass</string></value></param>` +
+		`<param><value><string>ipmi-secret</string></value></param>` +
+		`<param><value><string>sa/1EWr40BWU==</string></value></param></params></methodCall>`))
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(handle, "___NEW___system::1") {
+		t.Errorf("expected a numbered handle:\n%s", handle)
+	}
+	for _, secret := range []string{"abc123", "ipmi-secret", "sa/1EWr40BWU=="} {
+		if strings.Contains(modify, secret) {
+			t.Errorf("expected %q to be replaced:\n%s", secret, modify)
+		}
+	}
+	for _, replacement := range []string{"___NEW___system::1", "REDACTED", "securetoken99"} {
+		if !strings.Contains(modify, replacement) {
+			t.Errorf("expected %q in the request:\n%s", replacement, modify)
+		}
+	}
+}
+
+func TestNormalizeVolatileFields(t *testing.T) {
+	// Arrange
+	n := newNormalizer(DefaultOptions())
+	compact := `<methodResponse><params><param><value><struct>` +
+		`<member><name>mtime</name><value><double>1451856819.487791</double></value></member>` +
+		`<member><name>uid</name><value><string>MTQ1MTg1NjgxOS40OTE4ODYyODQuNzAxMTY</string></value></member>` +
+		`<member><name>power_pass</name><value>secret</value></member>` +
+		`<member><name>name</name><value><string>test</string></value></member>` +
+		`</struct></value></param></params></methodResponse>`
+	pretty := strings.NewReplacer("<member>", "\n    <member>", "<value>", "\n      <value>", "</struct>",
+		"\n  </struct>").Replace(compact)
+
+	// Act
+	normalizedCompact, compactErr := n.response("get_system", []byte(compact))
+	normalizedPretty, prettyErr := n.response("get_system", []byte(pretty))
+
+	// Assert
+	if compactErr != nil || prettyErr != nil {
+		t.Fatal(compactErr, prettyErr)
+	}
+	if normalizedCompact != normalizedPretty {
+		t.Errorf("expected whitespace between elements to be ignored:\n%s\n%s", normalizedCompact, normalizedPretty)
+	}
+	for _, expected := range []string{"<double>1700000000.0</double>", "<string>00000000000000000000000000000000</string>",
+		"<value>REDACTED</value>", "<string>test</string>"} {
+		if !strings.Contains(normalizedCompact, expected) {
+			t.Errorf("expected %q in the response:\n%s", expected, normalizedCompact)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package cassette
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+)
+
+const bodyTypeXML = "text/xml"
+
+// HTTPClient is the transport interface of cobblerclient.HTTPClient. It is repeated here, thus the tests of
+// cobblerclient can use this package.
+type HTTPClient interface {
+	Post(string, string, io.Reader) (*http.Response, error)
+}
+
+// Transport is implemented by [Recorder] and [Player]. Both satisfy cobblerclient.HTTPClient and
+// cobblerclient.HTTPDoer.
+type Transport interface {
+	HTTPClient
+	Do(*http.Request) (*http.Response, error)
+	// Cassette returns the recorded or replayed exchanges.
+	Cassette() *Cassette
+}
+
+// Open returns a [Player] for the cassette at path. If [EnvRecord] is set, a [Recorder] that sends all requests with
+// [http.DefaultClient] is returned instead. The recorded cassette is saved to path when the test finished. The client
+// has to be configured for a live server in that case, e.g. with cobblerclient.LoadConfig.
+func Open(t testing.TB, path string, options Options) Transport {
+	t.Helper()
+	if os.Getenv(EnvRecord) != "" {
+		recorder := NewRecorder(http.DefaultClient, options)
+		t.Cleanup(func() {
+			if err := recorder.Cassette().Save(path); err != nil {
+				t.Errorf("saving cassette: %s", err)
+			}
+		})
+		return recorder
+	}
+	cassette, err := Load(path)
+	if err != nil {
+		t.Fatalf("loading cassette (set %s=1 to record it): %s", EnvRecord, err)
+	}
+	player := NewPlayer(cassette, options)
+	t.Cleanup(func() {
+		if unused := player.Unused(); len(unused) > 0 {
+			t.Errorf("%d recorded interaction(s) were not replayed, the first one is %s", len(unused),
+				unused[0].Method)
+		}
+	})
+	return player
+}
+
+// Recorder is an [HTTPClient] that sends all requests with the wrapped client and records the normalized exchanges.
+type Recorder struct {
+	next       HTTPClient
+	normalizer *normalizer
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder creates a recorder that sends the requests with next. If next also has a Do method like
+// [http.Client], it is used to send the requests.
+func NewRecorder(next HTTPClient, options Options) *Recorder {
+	return &Recorder{next: next, normalizer: newNormalizer(options)}
+}
+
+// Post sends the request and records the exchange.
+func (r *Recorder) Post(url, bodyType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", bodyType)
+	return r.Do(req)
+}
+
+// Do sends the request and records the exchange. Requests that fail without a response aren't recorded.
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(reqBody))
+
+	var res *http.Response
+	if doer, ok := r.next.(interface {
+		Do(*http.Request) (*http.Response, error)
+	}); ok {
+		res, err = doer.Do(req)
+	} else {
+		res, err = r.next.Post(req.URL.String(), req.Header.Get("Content-Type"), bytes.NewReader(reqBody))
+	}
+	if err != nil {
+		return nil, err
+	}
+	resBody, err := readBody(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	method, request, err := r.normalizer.request(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: normalizing the request: %w", err)
+	}
+	response := string(resBody)
+	if res.StatusCode < http.StatusBadRequest {
+		if response, err = r.normalizer.response(method, resBody); err != nil {
+			return nil, fmt.Errorf("cassette: normalizing the response of %s: %w", method, err)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Method:     method,
+		Request:    request,
+		StatusCode: res.StatusCode,
+		Response:   response,
+	})
+	return res, nil
+}
+
+// Cassette returns a copy of the exchanges recorded so far.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Cassette{Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+}
+
+// Player is an [HTTPClient] that answers requests with the responses of a cassette. A request is answered by the
+// first interaction that wasn't replayed yet and whose normalized request equals the normalized request, thus
+// concurrent requests may arrive in a different order than they were recorded.
+type Player struct {
+	cassette   *Cassette
+	normalizer *normalizer
+
+	mu       sync.Mutex
+	replayed []bool
+}
+
+// NewPlayer creates a player for the given cassette. The options have to match the ones used for recording.
+func NewPlayer(cassette *Cassette, options Options) *Player {
+	return &Player{
+		cassette:   cassette,
+		normalizer: newNormalizer(options),
+		replayed:   make([]bool, len(cassette.Interactions)),
+	}
+}
+
+// Post answers the request with the recorded response.
+func (p *Player) Post(url, bodyType string, body io.Reader) (*http.Response, error) {
+	reqBody, err := readBody(io.NopCloser(body))
+	if err != nil {
+		return nil, err
+	}
+	method, request, err := p.normalizer.request(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: normalizing the request: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, interaction := range p.cassette.Interactions {
+		if p.replayed[i] || interaction.Request != request {
+			continue
+		}
+		p.replayed[i] = true
+		statusCode := interaction.StatusCode
+		if statusCode == 0 {
+			statusCode = http.StatusOK
+		}
+		return &http.Response{
+			StatusCode: statusCode,
+			Status:     fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+			Header:     http.Header{"Content-Type": []string{bodyTypeXML}},
+			Body:       io.NopCloser(bytes.NewBufferString(interaction.Response)),
+		}, nil
+	}
+	return nil, fmt.Errorf("cassette: no recorded interaction matches the request of %s:\n%s", method, request)
+}
+
+// Do answers the request with the recorded response.
+func (p *Player) Do(req *http.Request) (*http.Response, error) {
+	return p.Post(req.URL.String(), req.Header.Get("Content-Type"), req.Body)
+}
+
+// Cassette returns the cassette that is replayed.
+func (p *Player) Cassette() *Cassette {
+	return p.cassette
+}
+
+// Unused returns the interactions that weren't replayed yet.
+func (p *Player) Unused() []Interaction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var unused []Interaction
+	for i, interaction := range p.cassette.Interactions {
+		if !p.replayed[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+// readBody reads and closes a body that may be nil.
+func readBody(body io.ReadCloser) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
@@ -0,0 +1,154 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cobbler "github.com/cobbler/cobblerclient"
+)
+
+// liveServer fakes a Cobbler server with a real token and random handles.
+type liveServer struct{}
+
+func (liveServer) Post(url, bodyType string, body io.Reader) (*http.Response, error) {
+	var call struct {
+		MethodName string `xml:"methodName"`
+	}
+	if err := xml.NewDecoder(body).Decode(&call); err != nil {
+		return nil, err
+	}
+	var value string
+	switch call.MethodName {
+	case "login":
+		value = "<string>sa/1EWr40BWU+Pq3VEOOpD4cQtxkeMuFUw==</string>"
+	case "new_system":
+		value = "<string>___NEW___system::c2VjcmV0LWhhbmRsZQ==</string>"
+	case "last_modified_time":
+		value = "<double>1712345678.123</double>"
+	default:
+		value = "<boolean>1</boolean>"
+	}
+	response := fmt.Sprintf("<?xml version='1.0'?>\n<methodResponse>\n<params>\n<param>\n<value>%s</value>\n"+
+		"</param>\n</params>\n</methodResponse>\n", value)
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(response))}, nil
+}
+
+// exercise runs the calls that are recorded and replayed.
+func exercise(t *testing.T, c cobbler.Client) {
+	_, err := c.Login()
+	if err != nil {
+		t.Fatal(err)
+	}
+	handle, err := c.Call("new_system", c.Token())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.Call("modify_system", handle, "power_pass", "ipmi-secret", c.Token()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.LastModifiedTime(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "cassette.yaml")
+	recorder := NewRecorder(liveServer{}, DefaultOptions())
+	exercise(t, cobbler.NewClient(recorder, cobbler.ClientConfig{URL: "http://cobbler", Username: "cobbler",
+		Password: "s3cret"}))
+	if err := recorder.Cassette().Save(path); err != nil {
+		t.Fatal(err)
+	}
+	recorded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := NewPlayer(recorded, DefaultOptions())
+
+	// Act
+	exercise(t, cobbler.NewClient(player, cobbler.ClientConfig{URL: "http://localhost", Username: "cobbler",
+		Password: "another-secret"}))
+
+	// Assert
+	if unused := player.Unused(); len(unused) != 0 {
+		t.Errorf("expected all interactions to be replayed, got %d unused", len(unused))
+	}
+	var content strings.Builder
+	for _, interaction := range recorded.Interactions {
+		content.WriteString(interaction.Request + interaction.Response)
+	}
+	for _, secret := range []string{"s3cret", "sa/1EWr40BWU", "c2VjcmV0LWhhbmRsZQ", "ipmi-secret", "1712345678"} {
+		if strings.Contains(content.String(), secret) {
+			t.Errorf("expected %q to be removed from the cassette", secret)
+		}
+	}
+}
+
+// expiringServer fakes a Cobbler server whose token expires right after the login. The fault repeats the token.
+type expiringServer struct{}
+
+func (expiringServer) Post(url, bodyType string, body io.Reader) (*http.Response, error) {
+	var call struct {
+		MethodName string `xml:"methodName"`
+	}
+	if err := xml.NewDecoder(body).Decode(&call); err != nil {
+		return nil, err
+	}
+	value := "<params>\n<param>\n<value><string>sa/1EWr40BWU+Pq3VEOOpD4cQtxkeMuFUw==</string></value>\n</param>\n" +
+		"</params>"
+	if call.MethodName != "login" {
+		value = "<fault>\n<value><struct>\n<member><name>faultCode</name><value><int>1</int></value></member>\n" +
+			"<member><name>faultString</name><value><string>&lt;class 'cobbler.cexceptions.CX'&gt;:" +
+			"'invalid token: sa/1EWr40BWU+Pq3VEOOpD4cQtxkeMuFUw=='</string></value></member>\n</struct></value>\n" +
+			"</fault>"
+	}
+	response := fmt.Sprintf("<?xml version='1.0'?>\n<methodResponse>\n%s\n</methodResponse>\n", value)
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(response))}, nil
+}
+
+func TestRecordFaultWithToken(t *testing.T) {
+	// Arrange
+	recorder := NewRecorder(expiringServer{}, DefaultOptions())
+	c := cobbler.NewClient(recorder, cobbler.ClientConfig{URL: "http://cobbler", Username: "cobbler",
+		Password: "s3cret"})
+	if _, err := c.Login(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	_, err := c.Call("get_systems", c.Token())
+
+	// Assert
+	if err == nil {
+		t.Fatal("expected the fault of the server")
+	}
+	interactions := recorder.Cassette().Interactions
+	response := interactions[len(interactions)-1].Response
+	if strings.Contains(response, "sa/1EWr40BWU") {
+		t.Errorf("expected the token to be removed from the fault:\n%s", response)
+	}
+	if !strings.Contains(response, "invalid token: securetoken99") {
+		t.Errorf("expected the replaced token in the fault:\n%s", response)
+	}
+}
+
+func TestReplayUnknownRequest(t *testing.T) {
+	// Arrange
+	player := NewPlayer(&Cassette{}, DefaultOptions())
+	c := cobbler.NewClient(player, cobbler.ClientConfig{URL: "http://localhost"})
+
+	// Act
+	_, err := c.Ping()
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Errorf("expected an error for a request that wasn't recorded, got %v", err)
+	}
+}
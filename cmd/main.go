@@ -2,25 +2,30 @@ package main
 
 import (
 	"fmt"
-	"net/http"
+	"os"
 
 	cobbler "github.com/cobbler/cobblerclient"
 )
 
-var config = cobbler.ClientConfig{
-	URL:      "http://localhost:8081/cobbler_api",
-	Username: "cobbler",
-	Password: "cobbler",
-}
-
+// The server is configured via COBBLER_URL, COBBLER_USERNAME and COBBLER_PASSWORD or the profiles in
+// ~/.config/cobbler/client.yaml.
 func main() {
-	c := cobbler.NewClient(http.DefaultClient, config)
-	_, err := c.Login()
+	config, err := cobbler.LoadConfig("")
+	if err != nil {
+		fmt.Printf("Error loading the configuration: %s\n", err)
+		os.Exit(1)
+	}
+	c, err := cobbler.NewClientFromConfig(config)
+	if err != nil {
+		fmt.Printf("Error creating the client: %s\n", err)
+		os.Exit(1)
+	}
+	_, err = c.Login()
 	if err != nil {
 		fmt.Printf("Error logging in: %s\n", err)
 	}
 
-	fmt.Printf("Token: %s\n", c.Token)
+	fmt.Printf("Token: %s\n", c.Token())
 
 	res, err := c.BackgroundSync(cobbler.BackgroundSyncOptions{Dhcp: true, Dns: true, Verbose: true})
 	if err != nil {
@@ -167,12 +172,12 @@ func main() {
 	}
 
 	fmt.Println("Adding NIC to System")
-	if err := newSystem.CreateInterface("eth0", eth0); err != nil {
+	if err := c.CreateInterface(newSystem, "eth0", eth0); err != nil {
 		fmt.Println(err)
 	}
 
 	fmt.Println("Adding second NIC to System")
-	if err := newSystem.CreateInterface("eth1", eth1); err != nil {
+	if err := c.CreateInterface(newSystem, "eth1", eth1); err != nil {
 		fmt.Println(err)
 	}
 	//
@@ -208,12 +213,12 @@ func main() {
 	fmt.Printf("eth1:\n%+v\n\n", iface)
 
 	fmt.Println("Deleting Interface")
-	err = s2.DeleteInterface("eth0")
+	err = c.DeleteInterface(s2, "eth0")
 	if err != nil {
 		fmt.Println(err)
 	}
 	fmt.Println("Deleting Interface")
-	err = s2.DeleteInterface("eth1")
+	err = c.DeleteInterface(s2, "eth1")
 	if err != nil {
 		fmt.Println(err)
 	}
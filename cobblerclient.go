@@ -18,6 +18,7 @@ package cobblerclient
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"github.com/go-viper/mapstructure/v2"
@@ -27,6 +28,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 )
 
 const bodyTypeXML = "text/xml"
@@ -37,46 +39,300 @@ type HTTPClient interface {
 	Post(string, string, io.Reader) (*http.Response, error)
 }
 
-// Client is the type which all API methods are attached to.
+// HTTPDoer is the context-aware counterpart of [HTTPClient]. If the [HTTPClient] passed to [NewClient] also implements
+// this interface (like [http.Client] does), every request is built with [http.NewRequestWithContext] and sent via Do.
+// This way cancellation and deadlines of the context reach the transport.
+type HTTPDoer interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Client is the type which all API methods are attached to. A Client is a lightweight handle: all copies of a client
+// created by the same [NewClient] call, like those returned by [Client.WithContext], share a single session through a
+// pointer. Thus a new token or server version is visible to all of them and the client can be used concurrently from
+// many goroutines.
 type Client struct {
 	httpClient HTTPClient
 	config     ClientConfig
-	// The longevity of this token is defined server side in the setting "auth_token_duration". Per default no token is
-	// retrieved. A token can be obtained via the [Client.Login] method.
-	Token string
-	// To allow for version dependant API calls in the client we cache the major, minor and patch version.
-	CachedVersion CobblerVersion
-}
-
-// ClientConfig is the URL of Cobbler plus login credentials.
+	// ctx is the context used for all calls made through this client. It is nil unless set with [Client.WithContext].
+	ctx context.Context
+	// session is shared between all copies of the client. It holds the token and the cached version and coordinates
+	// automatic logins.
+	session *session
+	// cache is shared between all copies of the client as well. It is nil unless [ClientConfig.ReadCache] is set.
+	cache *readCache
+	// endpoints are the primary server followed by its replicas. Their health is shared between all copies of the
+	// client.
+	endpoints []*endpoint
+	// plan records the calls that modify data. It is shared between all copies of the client and nil unless
+	// [ClientConfig.DryRun] is set.
+	plan *Plan
+	// primary pins all calls of the client to the primary server. It is set for the reads that are part of a write
+	// sequence, see [Client.onPrimary].
+	primary bool
+}
+
+// ClientConfig is the URL of Cobbler plus login credentials and the settings for the behavior and the transport of
+// the client.
 type ClientConfig struct {
+	// URL is the XML-RPC endpoint of the primary server.
 	URL      string
 	Username string
 	Password string
+	// Token is an already obtained token that is used for all calls until [Client.Login] retrieves a new one.
+	Token string
+	// AutoRelogin enables the session mode of the client. Calls that fail because the token is invalid trigger a new
+	// login with Username and Password and are then retried once with the new token.
+	AutoRelogin bool
+	// TokenCheckInterval is the interval in which the token is proactively validated with "token_check" when
+	// AutoRelogin is enabled. Zero means [DefaultTokenCheckInterval], a negative value disables the proactive check.
+	TokenCheckInterval time.Duration
+	// RetryPolicy controls how calls are retried after transient transport failures. Fields left at their zero value
+	// are taken from [DefaultRetryPolicy].
+	RetryPolicy RetryPolicy
+	// Interceptors are wrapped around every call of the client. The first interceptor is the outermost one.
+	Interceptors []Interceptor
+	// BatchUpdates groups all "modify_*" calls that are needed to create or update an item into a single
+	// "system.multicall" request. If the server doesn't support multicalls, the client falls back to one call per
+	// attribute.
+	BatchUpdates bool
+	// Timeout limits the duration of every call including its retries. Zero means no timeout. The timeout only
+	// interrupts a request if the [HTTPClient] also implements [HTTPDoer].
+	Timeout time.Duration
+	// UserAgent is sent as "User-Agent" header of every request. It is only applied if the [HTTPClient] also
+	// implements [HTTPDoer].
+	UserAgent string
+	// ReadCache keeps the items returned by the Get* methods in memory. Before cached items are returned, the
+	// "last_modified_time" of the server is checked. Only if it moved forward, the modified items are fetched with the
+	// Get*Since methods. Every call that may modify items clears the cache. Flattened and resolved items aren't cached.
+	ReadCache bool
+	// ReplicaURLs are the XML-RPC endpoints of read-only replicas of the primary, e.g. fed by
+	// [Client.BackgroundReplicate]. Read-only calls (see [IsReadOnlyMethod]) fail over to the first healthy replica in
+	// case the primary can't be reached. All other calls are always sent to the primary. The server that answered a
+	// call is recorded in [RPCCall.Endpoint].
+	ReplicaURLs []string
+	// HealthCheckInterval is the time an endpoint that failed is skipped. Afterwards it is checked with "ping" before
+	// the next call is sent to it. Zero means [DefaultHealthCheckInterval].
+	HealthCheckInterval time.Duration
+	// DryRun records all calls that may modify data in the [Plan] of the client instead of sending them. The recorded
+	// calls are answered with synthetic results, e.g. "new_*" calls return handles like "dry-run::system::1". Reads
+	// are still sent to the server, except for reads of items that were created in the plan. The dry run happens
+	// inside of all interceptors, thus they see the calls as if they were sent.
+	DryRun bool
+	// ConflictCheck makes updates and edit sessions re-read the modification time of an item before its modifications
+	// are sent. If it differs from the one the item had when it was read, a [ConflictError] is returned and nothing is
+	// sent. Use [RetryOnConflict] to repeat the read-modify-write cycle in that case. Items that were not read from the
+	// server aren't checked. After the item was saved, its modification time is read again for the next update. A
+	// modification that another client saves in between these two reads is not detected.
+	ConflictCheck bool
+
+	// The following settings configure the transport built by [NewClientFromConfig]. They are ignored by [NewClient].
+
+	// CACertFile is the path of a PEM bundle with certificate authorities that are trusted in addition to the ones of
+	// the system.
+	CACertFile string
+	// ClientCertFile and ClientKeyFile are the paths of a PEM encoded certificate and key that are presented to the
+	// server for mutual TLS authentication.
+	ClientCertFile string
+	ClientKeyFile  string
+	// InsecureSkipVerify disables the verification of the server certificate. This should only be used for lab
+	// servers.
+	InsecureSkipVerify bool
+	// ProxyURL is the URL of the HTTP proxy used for all requests. If it is empty, the proxy is taken from the
+	// environment variables HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+	ProxyURL string
 }
 
 // NewClient creates a [Client] struct which is ready for usage.
 func NewClient(httpClient HTTPClient, c ClientConfig) Client {
-	return Client{
-		httpClient:    httpClient,
-		config:        c,
-		CachedVersion: CobblerVersion{},
+	client := Client{
+		httpClient: httpClient,
+		config:     c,
+		session:    &session{token: c.Token},
+		endpoints:  newEndpoints(c),
+	}
+	if c.ReadCache {
+		client.cache = newReadCache()
+	}
+	if c.DryRun {
+		client.plan = newPlan()
+	}
+	return client
+}
+
+// Token returns the token of the session. The longevity of this token is defined server side in the setting
+// "auth_token_duration". Per default no token is retrieved. A token can be obtained via the [Client.Login] method.
+func (c *Client) Token() string {
+	if c.session == nil {
+		return ""
+	}
+	return c.session.getToken()
+}
+
+// SetToken replaces the token of the session, e.g. with one that was obtained outside of this client. The new token is
+// used by all copies of the client.
+func (c *Client) SetToken(token string) {
+	if c.session == nil {
+		c.session = &session{}
+	}
+	c.session.setToken(token, time.Time{})
+}
+
+// CachedVersion returns the version of the server that is cached to allow for version dependant API calls. The zero
+// value is returned in case the version wasn't fetched yet.
+func (c *Client) CachedVersion() CobblerVersion {
+	if c.session == nil {
+		return CobblerVersion{}
+	}
+	return c.session.getVersion()
+}
+
+// WithContext returns a shallow copy of the client whose calls are all bound to ctx. This makes every method of the
+// client context-aware without the need for a second variant of each method:
+//
+//	systems, err := c.WithContext(ctx).GetSystems()
+//
+// The provided ctx must be non-nil.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	if ctx == nil {
+		panic("cobblerclient: nil context")
+	}
+	c2 := *c
+	c2.ctx = ctx
+	return &c2
+}
+
+// Context returns the context that is used for all calls of the client. If no context was set via
+// [Client.WithContext], [context.Background] is returned.
+func (c *Client) Context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
 	}
+	return context.Background()
 }
 
 // Call is the generic method for calling an XML-RPC endpoint in Cobbler that has no dedicated method in the client.
 // Normally there should be no need to use this if you are just using the client. In case there is an error closing the
-// HTTP connection, it hides all errors that occur during the rest of the method.
+// HTTP connection, it hides all errors that occur during the rest of the method. The call is bound to the context
+// returned by [Client.Context].
 func (c *Client) Call(method string, args ...interface{}) (interface{}, error) {
-	var result interface{}
+	return c.CallContext(c.Context(), method, args...)
+}
+
+// CallContext is the same as [Client.Call] but the request is bound to the given context. The context is only passed
+// down to the transport in case the [HTTPClient] also implements [HTTPDoer]. The call passes through all interceptors
+// configured in [ClientConfig.Interceptors]. Structs with "mapstructure" tags, like the option structs of this package,
+// are sent with the names of their tags.
+func (c *Client) CallContext(ctx context.Context, method string, args ...interface{}) (interface{}, error) {
+	return c.callContext(ctx, method, args, nil)
+}
+
+// callContext implements [Client.CallContext]. If sink is not nil, the result must be an array whose elements are
+// passed to sink while the response is read. In that case the returned result is nil unless an interceptor answered
+// the call.
+func (c *Client) callContext(ctx context.Context, method string, args []interface{}, sink func(interface{}) error) (
+	interface{}, error) {
+	if c.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
+		defer cancel()
+	}
+	if err := c.checkMethodCapability(method); err != nil {
+		return nil, err
+	}
+	encodedArgs, err := encodeArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	call := &RPCCall{Method: method, Args: encodedArgs, sink: sink}
+	invoker := Invoker(c.callWithSession)
+	if c.plan != nil {
+		invoker = c.dryRun(invoker)
+	}
+	result, err := chainInterceptors(c.config.Interceptors, invoker)(ctx, call)
+	if c.cache != nil && invalidatesCache(method) {
+		c.cache.clear()
+	}
+	return result, err
+}
+
+// callWithSession is the innermost [Invoker] of the client. It takes care of the automatic session handling before
+// the call is sent to the server.
+func (c *Client) callWithSession(ctx context.Context, call *RPCCall) (interface{}, error) {
+	method, args := call.Method, call.Args
+	if !c.usesSession(method) {
+		return c.invokeCall(ctx, call, args)
+	}
+
+	sentToken := c.Token()
+	if err := c.ensureSession(ctx); err != nil {
+		return nil, err
+	}
+	if token := c.Token(); sentToken != token && sentToken != "" {
+		args = replaceToken(args, sentToken, token)
+		sentToken = token
+	}
+
+	result, err := c.invokeCall(ctx, call, args)
+	if err == nil || sentToken == "" || !errors.Is(err, ErrInvalidToken) || !containsToken(args, sentToken) {
+		return result, err
+	}
 
+	// The token expired server side, thus log in again and retry the call exactly once.
+	if loginErr := c.renewSession(ctx, sentToken); loginErr != nil {
+		return nil, loginErr
+	}
+	return c.invokeCall(ctx, call, replaceToken(args, sentToken, c.Token()))
+}
+
+// invokeCall invokes call with the given arguments and records the server that answered in the call.
+func (c *Client) invokeCall(ctx context.Context, call *RPCCall, args []interface{}) (interface{}, error) {
+	result, endpoint, err := c.invoke(ctx, call.Method, args, call.sink)
+	call.Endpoint = endpoint
+	return result, err
+}
+
+// invoke performs a single XML-RPC round trip without any session handling and returns the URL of the server that
+// answered. If sink is not nil, the response is streamed to it (see [Client.stream]).
+func (c *Client) invoke(ctx context.Context, method string, args []interface{}, sink func(interface{}) error) (
+	interface{}, string, error) {
 	reqBody, err := xmlrpc.EncodeMethodCall(method, args...)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	r := fmt.Sprintf("%s\n", string(reqBody))
-	res, err := c.httpClient.Post(c.config.URL, bodyTypeXML, bytes.NewReader([]byte(r)))
+	if sink != nil {
+		endpoint, err := c.invokeStream(ctx, method, []byte(r), sink)
+		return nil, endpoint, err
+	}
+	body, endpoint, err := c.roundTripWithRetry(ctx, method, []byte(r))
+	if err != nil {
+		return nil, endpoint, err
+	}
+	result, err := decodeResponse(method, body)
+	return result, endpoint, err
+}
+
+// decodeResponse unmarshals the body of an XML-RPC response. Faults are returned as [CobblerFault].
+func decodeResponse(method string, body []byte) (interface{}, error) {
+	var result interface{}
+	resp := xmlrpc.Response(body)
+	if err := resp.Unmarshal(&result); err != nil {
+		return nil, err
+	}
+
+	if err := resp.Err(); err != nil {
+		return nil, newCobblerFault(method, err)
+	}
+
+	return result, nil
+}
+
+// roundTrip performs a single HTTP exchange with the server and returns the raw response body. Responses with an HTTP
+// error status are returned as [HTTPStatusError]. In case there is an error closing the HTTP connection, it hides all
+// errors that occur while reading the body.
+func (c *Client) roundTrip(ctx context.Context, url string, reqBody []byte) (body []byte, err error) {
+	res, err := c.send(ctx, url, reqBody)
 	if err != nil {
 		return nil, err
 	}
@@ -86,26 +342,49 @@ func (c *Client) Call(method string, args ...interface{}) (interface{}, error) {
 			err = closeErr
 		}
 	}()
-	body, err := io.ReadAll(res.Body)
+	return io.ReadAll(res.Body)
+}
+
+// send posts the request to the server at url and returns the response if the server answered with a success status.
+// Responses with an HTTP error status are closed and returned as [HTTPStatusError].
+func (c *Client) send(ctx context.Context, url string, reqBody []byte) (*http.Response, error) {
+	res, err := c.post(ctx, url, reqBody)
 	if err != nil {
 		return nil, err
 	}
+	if res.StatusCode >= http.StatusBadRequest {
+		_ = res.Body.Close()
+		return nil, &HTTPStatusError{StatusCode: res.StatusCode, Status: res.Status}
+	}
+	return res, nil
+}
 
-	resp := xmlrpc.Response(body)
-	if err = resp.Unmarshal(&result); err != nil {
+// post sends the encoded XML-RPC request to the server at url. The context is honored by the transport if possible and
+// otherwise only checked before the request is sent.
+func (c *Client) post(ctx context.Context, url string, body []byte) (*http.Response, error) {
+	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
 
-	if err = resp.Err(); err != nil {
-		return nil, err
+	doer, ok := c.httpClient.(HTTPDoer)
+	if !ok {
+		return c.httpClient.Post(url, bodyTypeXML, bytes.NewReader(body))
 	}
 
-	// Return err because the deferred function may set it to non-nil
-	return result, err
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", bodyTypeXML)
+	if c.config.UserAgent != "" {
+		req.Header.Set("User-Agent", c.config.UserAgent)
+	}
+	return doer.Do(req)
 }
 
-func (c *Client) setCachedVersion() error {
-	if c.CachedVersion != (CobblerVersion{}) {
+// ensureCachedVersion fetches the version of the server in case it isn't cached yet.
+func (c *Client) ensureCachedVersion() error {
+	if c.CachedVersion() != (CobblerVersion{}) {
 		return nil
 	}
 	extendedVersion, err := c.ExtendedVersion()
@@ -115,26 +394,29 @@ func (c *Client) setCachedVersion() error {
 	if len(extendedVersion.VersionTuple) != 3 {
 		return errors.New("cobblerclient: invalid length of extended version tuple")
 	}
-	c.CachedVersion = CobblerVersion{
+	c.setCachedVersion(CobblerVersion{
 		Major: extendedVersion.VersionTuple[0],
 		Minor: extendedVersion.VersionTuple[1],
 		Patch: extendedVersion.VersionTuple[2],
-	}
+	})
 	return nil
 }
 
+func (c *Client) setCachedVersion(version CobblerVersion) {
+	if c.session == nil {
+		c.session = &session{}
+	}
+	c.session.setVersion(version)
+}
+
 func (c *Client) invalidateCachedVersion() {
-	c.CachedVersion = CobblerVersion{}
+	c.setCachedVersion(CobblerVersion{})
 }
 
 // GenerateAutoinstall generates the autoinstallation file for a given profile or system.
 func (c *Client) GenerateAutoinstall(profile string, system string) (string, error) {
 	result, err := c.Call("generate_autoinstall", profile, system)
-	if err != nil {
-		return "", err
-	} else {
-		return result.(string), err
-	}
+	return returnString("generate_autoinstall", result, err)
 }
 
 // LastModifiedTime retrieves the timestamp when any object in Cobbler was last modified.
@@ -142,43 +424,38 @@ func (c *Client) LastModifiedTime() (float64, error) {
 	result, err := c.Call("last_modified_time")
 	if err != nil {
 		return 0.0, err
-	} else {
-		return result.(float64), err
 	}
+	return asFloat("last_modified_time", result)
 }
 
 // Ping is a simple method to check if the XML-RPC API is available.
 func (c *Client) Ping() (bool, error) {
 	result, err := c.Call("ping")
-	if err != nil {
-		return false, err
-	} else {
-		return result.(bool), err
-	}
+	return returnBool("ping", result, err)
 }
 
 // AutoAddRepos automatically imports any repos server side that are known to the daemon. It is the responsitbility
 // of the caller to execute [Client.BackgroundReposync].
 func (c *Client) AutoAddRepos() error {
-	_, err := c.Call("auto_add_repos", c.Token)
+	_, err := c.Call("auto_add_repos", c.Token())
 	return err
 }
 
 // GetAutoinstallTemplates retrieves a list of all templates that are in use by Cobbler.
 func (c *Client) GetAutoinstallTemplates() error {
-	_, err := c.Call("get_autoinstall_templates", c.Token)
+	_, err := c.Call("get_autoinstall_templates", c.Token())
 	return err
 }
 
 // GetAutoinstallSnippets retrieves a list of all snippets that are in use by Cobbler.
 func (c *Client) GetAutoinstallSnippets() error {
-	_, err := c.Call("get_autoinstall_snippets", c.Token)
+	_, err := c.Call("get_autoinstall_snippets", c.Token())
 	return err
 }
 
 // IsAutoinstallInUse checks if a given system has reported that it is currently installing.
 func (c *Client) IsAutoinstallInUse(name string) error {
-	_, err := c.Call("is_autoinstall_in_use", name, c.Token)
+	_, err := c.Call("is_autoinstall_in_use", name, c.Token())
 	return err
 }
 
@@ -203,26 +480,29 @@ func (c *Client) GenerateScript(profile, system, name string) error {
 // GetBlendedData passes a profile or system through Cobblers inheritance chain and returns the result.
 func (c *Client) GetBlendedData(profile, system string) (map[string]interface{}, error) {
 	result, err := c.Call("get_blended_data", profile, system)
-	return result.(map[string]interface{}), err
+	if err != nil {
+		return nil, err
+	}
+	return asMap("get_blended_data", result)
 }
 
 // RegisterNewSystem registers a new system without a Cobbler token. This is normally called
 // during unattended installation by a script.
 func (c *Client) RegisterNewSystem(info map[string]interface{}) error {
-	_, err := c.Call("register_new_system", info, c.Token)
+	_, err := c.Call("register_new_system", info, c.Token())
 	return err
 }
 
 // RunInstallTriggers runs installation triggers for a given object. This is normally called during
 // unattended installation.
 func (c *Client) RunInstallTriggers(mode string, objtype string, name string, ip string) error {
-	_, err := c.Call("run_install_triggers", mode, objtype, name, ip, c.Token)
+	_, err := c.Call("run_install_triggers", mode, objtype, name, ip, c.Token())
 	return err
 }
 
 // GetReposCompatibleWithProfile returns all repositories that can be potentially assigned to a given profile.
 func (c *Client) GetReposCompatibleWithProfile(profile_name string) error {
-	_, err := c.Call("get_repos_compatible_with_profile", profile_name, c.Token)
+	_, err := c.Call("get_repos_compatible_with_profile", profile_name, c.Token())
 	return err
 }
 
@@ -247,7 +527,7 @@ const StatusText StatusOption = "text"
 // with two modes: "normal" or "text. In case this is called in mode "normal" you might want to use ParseStatus to
 // get a parsed version of the data. For mode "text" you can cast the interface to string.
 func (c *Client) GetStatus(mode StatusOption) (interface{}, error) {
-	return c.Call("get_status", mode, c.Token)
+	return c.Call("get_status", mode, c.Token())
 }
 
 // InstallationStatus represents the structured return value of GetStatus.
@@ -264,55 +544,49 @@ type InstallationStatus struct {
 // ParseStatus takes the interface returned by GetStatus and converts it into a list of well-defined structs.
 func (c *Client) ParseStatus(status interface{}) ([]InstallationStatus, error) {
 	result := make([]InstallationStatus, 0)
-	statusStruct, ok := status.(map[string]interface{})
-	if !ok {
-		return result, errors.New("cobblerclient: invalid status structure")
+	statusStruct, err := asMap("get_status", status)
+	if err != nil {
+		return result, err
 	}
 	for k, v := range statusStruct {
-		installation := InstallationStatus{}
-		installation.IP = k
-		statusArray, okArray := v.([]interface{})
-		if !okArray {
-			return result, errors.New("cobblerclient: invalid status structure")
-		}
-		mostRecentStart, err := convertToFloat(statusArray[0])
-		if err != nil {
-			return result, err
-		}
-		mostRecentStop, err := convertToFloat(statusArray[1])
-		if err != nil {
-			return result, err
-		}
-		mostRecentTarget, okTarget := statusArray[2].(string)
-		if !okTarget {
-			return result, errors.New("cobblerclient: invalid status structure")
-		}
-		seenStart, err := convertToInt(statusArray[3])
-		if err != nil {
-			return result, err
-		}
-		seenStop, err := convertToInt(statusArray[4])
+		installation, err := parseInstallationStatus(k, v)
 		if err != nil {
 			return result, err
 		}
-		state, stateOk := statusArray[5].(string)
-		if !stateOk {
-			return result, errors.New("cobblerclient: invalid status structure")
-		}
-		installation.MostRecentStart = mostRecentStart
-		installation.MostRecentStop = mostRecentStop
-		installation.MostRecentTarget = mostRecentTarget
-		installation.SeenStart = seenStart
-		installation.SeenStop = seenStop
-		installation.State = state
 		result = append(result, installation)
 	}
 	return result, nil
 }
 
+// parseInstallationStatus converts the status array of a single installation.
+func parseInstallationStatus(ip string, status interface{}) (InstallationStatus, error) {
+	installation := InstallationStatus{IP: ip}
+	invalidStatus := &DecodeError{
+		Method:   "get_status",
+		Expected: "array of [double, double, string, int, int, string]",
+		Value:    status,
+	}
+	statusArray, ok := status.([]interface{})
+	if !ok || len(statusArray) < 6 {
+		return installation, invalidStatus
+	}
+	var errStart, errStop, errSeenStart, errSeenStop error
+	var okTarget, okState bool
+	installation.MostRecentStart, errStart = convertToFloat(statusArray[0])
+	installation.MostRecentStop, errStop = convertToFloat(statusArray[1])
+	installation.MostRecentTarget, okTarget = statusArray[2].(string)
+	installation.SeenStart, errSeenStart = convertToInt(statusArray[3])
+	installation.SeenStop, errSeenStop = convertToInt(statusArray[4])
+	installation.State, okState = statusArray[5].(string)
+	if errStart != nil || errStop != nil || !okTarget || errSeenStart != nil || errSeenStop != nil || !okState {
+		return installation, invalidStatus
+	}
+	return installation, nil
+}
+
 // SyncDhcp updates the DHCP configuration synchronous.
 func (c *Client) SyncDhcp() error {
-	_, err := c.Call("sync_dhcp", c.Token)
+	_, err := c.Call("sync_dhcp", c.Token())
 	return err
 }
 
@@ -445,9 +719,7 @@ func cobblerDataHacks(fromType, targetType reflect.Kind, data interface{}) (inte
 
 // decodeCobblerItem is a custom mapstructure decoder to handler Cobbler's uniqueness.
 func decodeCobblerItem(raw interface{}, result interface{}) (interface{}, error) {
-	var metadata mapstructure.Metadata
 	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
-		Metadata:         &metadata,
 		Result:           result,
 		WeaklyTypedInput: true,
 		DecodeHook:       cobblerDataHacks,
@@ -464,9 +736,23 @@ func decodeCobblerItem(raw interface{}, result interface{}) (interface{}, error)
 	return result, nil
 }
 
+// fieldUpdate is a single "modify_*" call that sets one attribute of an item.
+type fieldUpdate struct {
+	field string
+	value interface{}
+	// nic is the name of the network interface for the "modify_interface" and "delete_interface" updates.
+	nic string
+}
+
 // updateCobblerFields updates all fields in a Cobbler Item structure.
 func (c *Client) updateCobblerFields(what string, item reflect.Value, id string) error {
-	method := fmt.Sprintf("modify_%s", what)
+	return c.applyFieldUpdates(what, id, collectFieldUpdates(what, item))
+}
+
+// collectFieldUpdates collects the modifications for all fields in a Cobbler Item structure in the order in which
+// they must be sent to the server.
+func collectFieldUpdates(what string, item reflect.Value) []fieldUpdate {
+	var updates []fieldUpdate
 	typeOfT := item.Type()
 
 	// Update embedded Item struct
@@ -474,70 +760,41 @@ func (c *Client) updateCobblerFields(what string, item reflect.Value, id string)
 		v := item.Field(i)
 		fieldType := v.Type().Name()
 
-		if fieldType == "Item" {
-			err := c.updateCobblerFields(what, reflect.ValueOf(v.Interface()), id)
-			if err != nil {
-				return err
-			}
-			break
-		}
-
-		if fieldType == "Resource" {
-			err := c.updateCobblerFields(what, reflect.ValueOf(v.Interface()), id)
-			if err != nil {
-				return err
-			}
+		if fieldType == "Item" || fieldType == "Resource" {
+			updates = append(updates, collectFieldUpdates(what, reflect.ValueOf(v.Interface()))...)
 			break
 		}
 	}
 
 	// Fields that can inherit from other items can only be set after the parent is set.
 	// Fields that inherit from settings can be modified without this constraint.
-	if method == "modify_profile" {
+	if what == "profile" {
 		// In Cobbler v3.3.0, if profile name isn't created first, an empty child gets written to the distro, which
 		// causes a ValueError: "calling find with no arguments"
 		nameField := item.FieldByName("Name")
-		_, err := c.Call(method, id, "name", nameField.String(), c.Token)
-		if err != nil {
-			return err
-		}
+		updates = append(updates, fieldUpdate{field: "name", value: nameField.String()})
 
 		parentField := item.FieldByName("Parent")
 		if parentField != (reflect.Value{}) {
-			err = c.updateSingleField(method, id, "parent", parentField.String(), "")
-			if err != nil {
-				return err
-			}
+			updates = append(updates, fieldUpdate{field: "parent", value: parentField.String()})
 		}
 		distroField := item.FieldByName("Distro")
 		if distroField != (reflect.Value{}) {
-			err = c.updateSingleField(method, id, "distro", distroField.String(), "")
-			if err != nil {
-				return err
-			}
+			updates = append(updates, fieldUpdate{field: "distro", value: distroField.String()})
 		}
 	}
-	if method == "modify_system" {
+	if what == "system" {
 		profileField := item.FieldByName("Profile")
 		if profileField != (reflect.Value{}) {
-			err := c.updateSingleField(method, id, "profile", profileField.String(), "")
-			if err != nil {
-				return err
-			}
+			updates = append(updates, fieldUpdate{field: "profile", value: profileField.String()})
 		}
 		imageField := item.FieldByName("Image")
 		if imageField != (reflect.Value{}) {
-			err := c.updateSingleField(method, id, "image", imageField.String(), "")
-			if err != nil {
-				return err
-			}
+			updates = append(updates, fieldUpdate{field: "image", value: imageField.String()})
 		}
 		interfaceField := item.FieldByName("Interfaces")
 		if interfaceField != (reflect.Value{}) {
-			err := c.updateInterfaces(id, interfaceField.Interface())
-			if err != nil {
-				return err
-			}
+			updates = append(updates, collectInterfaceUpdates(interfaceField.Interface().(Interfaces))...)
 		}
 	}
 
@@ -557,7 +814,7 @@ func (c *Client) updateCobblerFields(what string, item reflect.Value, id string)
 			continue
 		}
 
-		if method == "modify_profile" && field == "name" {
+		if what == "profile" && field == "name" {
 			// Field set above
 			continue
 		}
@@ -571,40 +828,78 @@ func (c *Client) updateCobblerFields(what string, item reflect.Value, id string)
 			}
 		}
 
-		err := c.updateSingleField(method, id, field, fieldValue, cobblerTag)
-		if err != nil {
-			return err
-		}
+		updates = append(updates, fieldUpdate{field: field, value: fieldValue})
 	}
-	return nil
+	return updates
+}
+
+// collectInterfaceUpdates creates the modifications for network interfaces. Since interfaces don't have unique
+// identifiers in Cobbler 3.3.x, no reliable tracking of operations can be done when interfaces are renamed. As such
+// this only handles modification and creation of interfaces.
+func collectInterfaceUpdates(interfaceMap Interfaces) []fieldUpdate {
+	names := make([]string, 0, len(interfaceMap))
+	for name := range interfaceMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	updates := make([]fieldUpdate, 0, len(names))
+	for _, name := range names {
+		updates = append(updates, fieldUpdate{
+			field: "modify_interface",
+			value: makeInterfaceOptionsMap(name, interfaceMap[name]),
+			nic:   name,
+		})
+	}
+	return updates
 }
 
-func (c *Client) updateSingleField(method, id, field string, fieldValue interface{}, cobblerTag string) error {
-	if result, err := c.Call(method, id, field, fieldValue, c.Token); err != nil {
+// applyFieldUpdates sends the modifications to the server. If [ClientConfig.BatchUpdates] is enabled they are sent
+// with a single "system.multicall" request, otherwise one call per attribute is made.
+func (c *Client) applyFieldUpdates(what, id string, updates []fieldUpdate) error {
+	updates, err := c.supportedFieldUpdates(what, updates)
+	if err != nil {
 		return err
-	} else {
-		if !result.(bool) && fieldValue != false {
-			// It's possible this is a new field that isn't available on older versions.
-			if cobblerTag == "newfield" {
-				return nil
-			}
-			return fmt.Errorf("error updating field \"%s\" to \"%s\"", field, fieldValue)
+	}
+	if c.config.BatchUpdates && len(updates) > 1 && c.multicallSupported() {
+		err := c.multicallFieldUpdates(what, id, updates)
+		if !isMulticallUnsupported(err) {
+			return err
 		}
+		c.setMulticallUnsupported()
 	}
-	return nil
-}
 
-// updateInterfaces takes care of pushing interface modifications. Since interfaces don't have unique identifiers in
-// Cobbler 3.3.x. As such no reliable tracking of operations can be done when interfaces are renamed. As such this only
-// handles modification and creation of interfaces.
-func (c *Client) updateInterfaces(systemId string, interfaceData interface{}) error {
-	interfaceMap := interfaceData.(Interfaces)
-	for name, iface := range interfaceMap {
-		res := makeInterfaceOptionsMap(name, iface)
-		err := c.ModifyInterface(systemId, res)
+	method := fmt.Sprintf("modify_%s", what)
+	for _, update := range updates {
+		result, err := c.Call(method, id, update.field, update.value, c.Token())
 		if err != nil {
 			return err
 		}
+		if err := checkFieldUpdateResult(what, id, update, result); err != nil {
+			return err
+		}
 	}
 	return nil
 }
+
+// checkFieldUpdateResult validates the value returned by the server for a single "modify_*" call.
+func checkFieldUpdateResult(what, id string, update fieldUpdate, result interface{}) error {
+	successful, err := asBool("modify_"+what, result)
+	if err != nil {
+		return err
+	}
+	if successful || update.value == false {
+		return nil
+	}
+	if update.field == "modify_interface" {
+		return &ValidationError{What: what, Field: "interfaces", Message: fmt.Sprintf("editing interface of system %s failed", id)}
+	}
+	if update.field == "delete_interface" {
+		return &ValidationError{What: what, Field: "interfaces", Message: fmt.Sprintf("deleting interface of system %s failed", id)}
+	}
+	return &ValidationError{
+		What:    what,
+		Field:   update.field,
+		Message: fmt.Sprintf("error updating field \"%s\" to \"%s\"", update.field, update.value),
+	}
+}
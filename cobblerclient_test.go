@@ -17,11 +17,55 @@ limitations under the License.
 package cobblerclient
 
 import (
+	"context"
+	"errors"
 	"github.com/go-test/deep"
+	"net/http"
+	"os"
 	"testing"
+
+	"github.com/cobbler/cobblerclient/cobblertest"
 )
 
-func TestSetCachedVersion(t *testing.T) {
+var config = ClientConfig{
+	URL:      cobblertest.URL,
+	Username: cobblertest.Username,
+	Password: cobblertest.Password,
+}
+
+// FailOnError ...
+func FailOnError(t *testing.T, err error) {
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Fixture implies that from the context where the test is being run a "fixtures" folder exists.
+func Fixture(fn string) ([]byte, error) {
+	// Disable semgrep (linter in Codacy) since this is testcode
+	return os.ReadFile("./fixtures/" + fn) // nosemgrep
+}
+
+// createStubHTTPClient returns a client whose calls must match the given fixtures in order. Empty names are skipped.
+func createStubHTTPClient(t *testing.T, fixtures []string) Client {
+	stub := cobblertest.NewStub(t)
+	for _, fixture := range fixtures {
+		if fixture != "" {
+			stub.ExpectFixture(fixture)
+		}
+	}
+
+	c := NewClient(stub, config)
+	c.SetToken(cobblertest.Token)
+	return c
+}
+
+// createStubHTTPClientSingle ...
+func createStubHTTPClientSingle(t *testing.T, fixture string) Client {
+	return createStubHTTPClient(t, []string{fixture})
+}
+
+func TestEnsureCachedVersion(t *testing.T) {
 	// Arrange
 	c := createStubHTTPClientSingle(t, "extended-version")
 	expectedVersion := CobblerVersion{
@@ -31,23 +75,23 @@ func TestSetCachedVersion(t *testing.T) {
 	}
 
 	// Act
-	err := c.setCachedVersion()
+	err := c.ensureCachedVersion()
 
 	// Assert
 	FailOnError(t, err)
-	deep.Equal(c.CachedVersion, expectedVersion)
+	deep.Equal(c.CachedVersion(), expectedVersion)
 }
 
 func TestInvalidateCachedVersion(t *testing.T) {
 	// Arrange
 	c := createStubHTTPClientSingle(t, "extended-version")
-	_ = c.setCachedVersion()
+	_ = c.ensureCachedVersion()
 
 	// Act
 	c.invalidateCachedVersion()
 
 	// Assert
-	deep.Equal(c.CachedVersion, CobblerVersion{})
+	deep.Equal(c.CachedVersion(), CobblerVersion{})
 }
 
 func TestGenerateAutoinstall(t *testing.T) {
@@ -256,12 +300,65 @@ func TestClient_IsValueInherit(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			hc := NewStubHTTPClient(t)
+			hc := cobblertest.NewStub(t)
 			c := NewClient(hc, config)
-			c.Token = "securetoken99"
+			c.SetToken("securetoken99")
 			if got := c.IsValueInherit(tt.args.value); got != tt.want {
 				t.Errorf("IsValueInherit() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+// doerStub is an HTTPClient that also implements HTTPDoer and remembers the last request it received.
+type doerStub struct {
+	*cobblertest.Stub
+	lastRequest *http.Request
+}
+
+func (d *doerStub) Do(req *http.Request) (*http.Response, error) {
+	d.lastRequest = req
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	return d.Stub.Post(req.URL.String(), req.Header.Get("Content-Type"), req.Body)
+}
+
+func TestCallContextCancelled(t *testing.T) {
+	// Arrange
+	c := createStubHTTPClient(t, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	_, err := c.CallContext(ctx, "ping")
+
+	// Assert
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	// Arrange
+	stub := createStubHTTPClientSingle(t, "ping")
+	hc := &doerStub{Stub: stub.httpClient.(*cobblertest.Stub)}
+	c := NewClient(hc, config)
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+	// Act
+	res, err := c.WithContext(ctx).Ping()
+
+	// Assert
+	FailOnError(t, err)
+	if !res {
+		t.Fatalf("Expected ping to return true")
+	}
+	if hc.lastRequest == nil || hc.lastRequest.Context().Value(ctxKey{}) != "value" {
+		t.Fatalf("Expected the request to carry the context of the client")
+	}
+	if c.Context() != context.Background() {
+		t.Fatalf("Expected the original client to be unchanged")
+	}
+}
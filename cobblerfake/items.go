@@ -0,0 +1,769 @@
+package cobblerfake
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/cobbler/cobblerclient/cobblertest"
+)
+
+// collections maps the plural names used by "get_*s" and "get_*s_since" to the item types.
+var collections = map[string]string{
+	"distros":     "distro",
+	"profiles":    "profile",
+	"systems":     "system",
+	"images":      "image",
+	"repos":       "repo",
+	"mgmtclasses": "mgmtclass",
+	"packages":    "package",
+	"files":       "file",
+	"menus":       "menu",
+}
+
+// reference is an attribute that holds the name of another item.
+type reference struct {
+	attribute string
+	what      string
+}
+
+// references lists the attributes of an item type that name a parent. The first reference that is set determines the
+// parent an attribute is inherited from.
+var references = map[string][]reference{
+	"profile": {{attribute: "parent", what: "profile"}, {attribute: "distro", what: "distro"}},
+	"system":  {{attribute: "profile", what: "profile"}, {attribute: "image", what: "image"}},
+	"menu":    {{attribute: "parent", what: "menu"}},
+}
+
+// mergedAttributes are the dictionaries that are merged with the ones of the parents when an item is resolved. All
+// other attributes are replaced.
+var mergedAttributes = map[string]bool{
+	"autoinstall_meta":    true,
+	"boot_files":          true,
+	"fetchable_files":     true,
+	"kernel_options":      true,
+	"kernel_options_post": true,
+	"mgmt_parameters":     true,
+	"template_files":      true,
+}
+
+// unflattened are the attributes that keep their structure in flattened items.
+var unflattened = map[string]bool{"children": true, "interfaces": true}
+
+// readOnlyAttributes can't be changed with "modify_*".
+var readOnlyAttributes = map[string]bool{"children": true, "ctime": true, "depth": true, "mtime": true, "uid": true}
+
+// newAttributes returns the attributes of an item created with "new_*".
+func newAttributes(what string) map[string]interface{} {
+	attributes := map[string]interface{}{
+		"name":    "",
+		"uid":     randomHex(16),
+		"comment": "",
+		"ctime":   0.0,
+		"mtime":   0.0,
+		"depth":   0,
+		"owners":  inherit,
+	}
+	for _, ref := range references[what] {
+		attributes[ref.attribute] = ""
+	}
+	if what == "system" {
+		attributes["interfaces"] = map[string]interface{}{}
+	}
+	return attributes
+}
+
+// AddItem stores an item as if it was created and saved by a client, e.g. to prepare a test. Attributes that are
+// missing are set like "new_*" does.
+func (s *Server) AddItem(what string, attributes map[string]interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !isItemType(what) {
+		return fmt.Errorf("unknown item type %s", what)
+	}
+	item := newAttributes(what)
+	for key, value := range attributes {
+		item[key] = deepCopy(value)
+	}
+	if err := s.store(what, item, true); err != nil {
+		return fmt.Errorf("%s", err.Message)
+	}
+	return nil
+}
+
+// callItemMethod dispatches the methods that operate on items, both the concrete ones like "get_system" and the
+// generic ones like "get_item". handled is false if method isn't an item method.
+func (s *Server) callItemMethod(method string, args []interface{}) (result interface{}, handled bool, err *fault) {
+	operation, what, args, generic := parseItemMethod(method, args)
+	if operation == "" {
+		return nil, false, nil
+	}
+	if !isItemType(what) {
+		if !generic {
+			return nil, false, nil
+		}
+		return nil, true, cx("internal error, collection name %s not supported", what)
+	}
+
+	switch operation {
+	case "new":
+		result, err = s.newItem(what, args)
+	case "handle":
+		result, err = s.itemHandle(what, stringArg(args, 0))
+	case "modify":
+		result, err = s.modifyItem(what, args)
+	case "save":
+		result, err = s.saveItem(what, args)
+	case "get":
+		result = s.getItem(what, stringArg(args, 0), boolArg(args, 1), boolArg(args, 2))
+	case "rendered":
+		result = s.renderItem(what, stringArg(args, 0))
+	case "list":
+		result = s.listItems(what, func(map[string]interface{}) bool { return true })
+	case "since":
+		since := floatArg(args, 0)
+		result = s.listItems(what, func(item map[string]interface{}) bool { return floatValue(item["mtime"]) > since })
+	case "find":
+		criteria, _ := mapArg(args, 0)
+		expand := boolArg(args, 1)
+		if generic {
+			// what, criteria, sort_field, expand
+			expand = boolArg(args, 2)
+		}
+		result = s.findItems(what, criteria, expand)
+	case "names":
+		result = sortedKeys(s.items[what])
+	case "has":
+		_, ok := s.items[what][stringArg(args, 0)]
+		result = ok
+	case "remove":
+		result, err = s.removeItem(what, args)
+	case "rename":
+		result, err = s.renameItem(what, args)
+	case "copy":
+		result, err = s.copyItem(what, args)
+	}
+	return result, true, err
+}
+
+// genericMethods maps the generic item methods, which take the item type as first argument, to their operation.
+var genericMethods = map[string]string{
+	"new_item":        "new",
+	"get_item_handle": "handle",
+	"modify_item":     "modify",
+	"save_item":       "save",
+	"get_item":        "get",
+	"find_items":      "find",
+	"get_item_names":  "names",
+	"has_item":        "has",
+	"remove_item":     "remove",
+	"rename_item":     "rename",
+	"copy_item":       "copy",
+}
+
+// parseItemMethod splits a method like "get_systems_since" into the operation and the item type. For the generic
+// methods the item type is taken from the arguments, thus the remaining arguments are the same for both variants.
+func parseItemMethod(method string, args []interface{}) (operation, what string, rest []interface{}, generic bool) {
+	if operation, ok := genericMethods[method]; ok {
+		if len(args) == 0 {
+			return operation, "", args, true
+		}
+		return operation, stringArg(args, 0), args[1:], true
+	}
+	for _, prefix := range []string{"new", "modify", "save", "find", "remove", "rename", "copy"} {
+		if what := strings.TrimPrefix(method, prefix+"_"); what != method {
+			return prefix, what, args, false
+		}
+	}
+	name := strings.TrimPrefix(method, "get_")
+	if name == method {
+		return "", "", args, false
+	}
+	if plural := strings.TrimSuffix(name, "_since"); plural != name {
+		return "since", collections[plural], args, false
+	}
+	if what, ok := collections[name]; ok {
+		return "list", what, args, false
+	}
+	if what := strings.TrimSuffix(name, "_handle"); what != name {
+		return "handle", what, args, false
+	}
+	if what := strings.TrimSuffix(name, "_as_rendered"); what != name {
+		return "rendered", what, args, false
+	}
+	return "get", name, args, false
+}
+
+func isItemType(what string) bool {
+	for _, itemType := range collections {
+		if itemType == what {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) newItem(what string, args []interface{}) (interface{}, *fault) {
+	if err := s.checkToken(args, 0); err != nil {
+		return nil, err
+	}
+	handle := fmt.Sprintf("___NEW___%s::%s", what, randomHex(8))
+	s.pending[handle] = &pendingItem{what: what, attributes: newAttributes(what)}
+	return handle, nil
+}
+
+// itemHandle returns the handle of a saved item. Saved items are addressed by their name.
+func (s *Server) itemHandle(what, name string) (interface{}, *fault) {
+	if _, ok := s.items[what][name]; !ok {
+		return nil, cx("internal error, unknown %s name %s", what, name)
+	}
+	return fmt.Sprintf("%s::%s", what, name), nil
+}
+
+// lookupHandle returns the attributes of the item with the given handle. saved is false for items that were created
+// with "new_*" and not saved yet.
+func (s *Server) lookupHandle(what, handle string) (attributes map[string]interface{}, saved bool, err *fault) {
+	if pending, ok := s.pending[handle]; ok && pending.what == what {
+		return pending.attributes, false, nil
+	}
+	name := strings.TrimPrefix(handle, what+"::")
+	if item, ok := s.items[what][name]; ok && name != handle {
+		return item, true, nil
+	}
+	return nil, false, cx("internal error, unknown %s name %s", what, name)
+}
+
+// modifyItem implements "modify_*". Changes to saved items are visible immediately, like on the server.
+func (s *Server) modifyItem(what string, args []interface{}) (interface{}, *fault) {
+	if err := s.checkToken(args, 3); err != nil {
+		return nil, err
+	}
+	item, saved, err := s.lookupHandle(what, stringArg(args, 0))
+	if err != nil {
+		return nil, err
+	}
+	attribute := stringArg(args, 1)
+	var value interface{}
+	if len(args) > 2 {
+		value = args[2]
+	}
+
+	switch {
+	case what == "system" && attribute == "modify_interface":
+		err = modifyInterface(item, value)
+	case what == "system" && attribute == "delete_interface":
+		delete(interfaces(item), fmt.Sprint(value))
+	case what == "system" && attribute == "rename_interface":
+		err = renameInterface(item, value)
+	case readOnlyAttributes[attribute]:
+		err = valueError("%s is read-only", attribute)
+	case attribute == "name" && saved && value != item["name"]:
+		err = cx("the name of %s %s can only be changed with rename_%s", what, item["name"], what)
+	default:
+		err = s.checkReference(what, attribute, value)
+		if err == nil {
+			item[attribute] = deepCopy(value)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if saved {
+		item["mtime"] = s.touch()
+	}
+	return true, nil
+}
+
+// checkReference fails if attribute names a parent that doesn't exist.
+func (s *Server) checkReference(what, attribute string, value interface{}) *fault {
+	for _, ref := range references[what] {
+		if ref.attribute != attribute {
+			continue
+		}
+		name, ok := value.(string)
+		if !ok {
+			return valueError("%s must be a string", attribute)
+		}
+		if name == "" || name == inherit || name == notFound {
+			return nil
+		}
+		if _, ok := s.items[ref.what][name]; !ok {
+			return cx("invalid %s name: %s", ref.what, name)
+		}
+	}
+	return nil
+}
+
+func interfaces(system map[string]interface{}) map[string]interface{} {
+	nics, ok := system["interfaces"].(map[string]interface{})
+	if !ok {
+		nics = make(map[string]interface{})
+		system["interfaces"] = nics
+	}
+	return nics
+}
+
+// modifyInterface applies the fields of "modify_interface". The keys have the format "<attribute>-<interface>".
+func modifyInterface(system map[string]interface{}, value interface{}) *fault {
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return valueError("modify_interface expects a struct")
+	}
+	nics := interfaces(system)
+	for key, fieldValue := range fields {
+		attribute, name, ok := strings.Cut(key, "-")
+		if !ok || name == "" {
+			return valueError("invalid interface field %s", key)
+		}
+		nic, ok := nics[name].(map[string]interface{})
+		if !ok {
+			nic = cobblertest.NetworkInterface(nil)
+			nics[name] = nic
+		}
+		current, ok := nic[attribute]
+		if !ok {
+			return valueError("unknown interface attribute %s", attribute)
+		}
+		if _, isList := current.([]interface{}); isList {
+			if text, isText := fieldValue.(string); isText {
+				fieldValue = splitList(text)
+			}
+		}
+		nic[attribute] = deepCopy(fieldValue)
+	}
+	return nil
+}
+
+// renameInterface applies "rename_interface", whose value holds the old name as "interface" and the new one as
+// "rename_interface".
+func renameInterface(system map[string]interface{}, value interface{}) *fault {
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return valueError("rename_interface expects a struct")
+	}
+	oldName, _ := fields["interface"].(string)
+	newName, _ := fields["rename_interface"].(string)
+	nics := interfaces(system)
+	nic, ok := nics[oldName]
+	if !ok {
+		return cx("interface %s does not exist", oldName)
+	}
+	if _, ok := nics[newName]; ok {
+		return cx("interface %s already exists", newName)
+	}
+	delete(nics, oldName)
+	nics[newName] = nic
+	return nil
+}
+
+// saveItem implements "save_*". New items are added to their collection, for saved items only the modification time
+// changes.
+func (s *Server) saveItem(what string, args []interface{}) (interface{}, *fault) {
+	if err := s.checkToken(args, 1); err != nil {
+		return nil, err
+	}
+	handle := stringArg(args, 0)
+	item, saved, err := s.lookupHandle(what, handle)
+	if err != nil {
+		return nil, err
+	}
+	if saved {
+		item["mtime"] = s.touch()
+		return true, nil
+	}
+	if err := s.store(what, item, stringArg(args, 2) == "new"); err != nil {
+		return nil, err
+	}
+	delete(s.pending, handle)
+	return true, nil
+}
+
+// store validates and adds a new item to its collection. If checkDuplicate is set, an item with the same name must not
+// exist, otherwise it is replaced.
+func (s *Server) store(what string, item map[string]interface{}, checkDuplicate bool) *fault {
+	name, _ := item["name"].(string)
+	if name == "" {
+		return valueError("the name of the %s must not be empty", what)
+	}
+	if _, exists := s.items[what][name]; exists && checkDuplicate {
+		return cx("An object already exists with that name. Try 'edit'?")
+	}
+	if refs := references[what]; len(refs) > 0 && what != "menu" {
+		var parent bool
+		for _, ref := range refs {
+			if err := s.checkReference(what, ref.attribute, item[ref.attribute]); err != nil {
+				return err
+			}
+			if value, _ := item[ref.attribute].(string); value != "" && value != inherit {
+				parent = true
+			}
+		}
+		if !parent {
+			return cx("Error with save_item(%s): a %s needs a %s or %s", what, what, refs[0].attribute, refs[1].attribute)
+		}
+	}
+	if s.items[what] == nil {
+		s.items[what] = make(map[string]map[string]interface{})
+	}
+	mtime := s.touch()
+	item["ctime"] = mtime
+	item["mtime"] = mtime
+	s.items[what][name] = item
+	return nil
+}
+
+// getItem implements "get_*". The result is "~" if the item doesn't exist.
+func (s *Server) getItem(what, name string, flatten, resolved bool) interface{} {
+	item, ok := s.items[what][name]
+	if !ok {
+		return notFound
+	}
+	return s.export(what, item, flatten, resolved)
+}
+
+// renderItem implements "get_*_as_rendered", which returns the resolved and flattened item. Like Cobbler, an empty
+// struct is returned for items that don't exist.
+func (s *Server) renderItem(what, name string) interface{} {
+	item, ok := s.items[what][name]
+	if !ok {
+		return map[string]interface{}{}
+	}
+	return s.export(what, item, true, true)
+}
+
+// export returns a copy of the item as it is sent to clients.
+func (s *Server) export(what string, item map[string]interface{}, flatten, resolved bool) map[string]interface{} {
+	exported := deepCopy(item).(map[string]interface{})
+	exported["children"] = s.children(what, item["name"])
+	if resolved {
+		chain := s.parents(what, item)
+		for key := range exported {
+			exported[key] = s.resolve(key, exported[key], chain)
+		}
+	}
+	if flatten {
+		for key, value := range exported {
+			if !unflattened[key] {
+				exported[key] = flattenValue(value)
+			}
+		}
+	}
+	return exported
+}
+
+// listItems returns the items of a type that match the filter, sorted by name.
+func (s *Server) listItems(what string, filter func(map[string]interface{}) bool) []interface{} {
+	items := make([]interface{}, 0)
+	for _, name := range sortedKeys(s.items[what]) {
+		if item := s.items[what][name]; filter(item) {
+			items = append(items, s.export(what, item, false, false))
+		}
+	}
+	return items
+}
+
+// findItems implements "find_*". The criteria are shell patterns that must match the attributes of an item. The
+// attributes of the interfaces of a system are matched as well.
+func (s *Server) findItems(what string, criteria map[string]interface{}, expand bool) []interface{} {
+	found := make([]interface{}, 0)
+	for _, name := range sortedKeys(s.items[what]) {
+		item := s.items[what][name]
+		if !matches(item, criteria) {
+			continue
+		}
+		if expand {
+			found = append(found, s.export(what, item, false, false))
+		} else {
+			found = append(found, name)
+		}
+	}
+	return found
+}
+
+func matches(item map[string]interface{}, criteria map[string]interface{}) bool {
+	for key, rawPattern := range criteria {
+		pattern := asText(flattenValue(rawPattern))
+		if value, ok := item[key]; ok {
+			if !matchesPattern(pattern, value) {
+				return false
+			}
+			continue
+		}
+		var nicMatches bool
+		nics, _ := item["interfaces"].(map[string]interface{})
+		for _, rawNic := range nics {
+			nic, _ := rawNic.(map[string]interface{})
+			if value, ok := nic[key]; ok && matchesPattern(pattern, value) {
+				nicMatches = true
+				break
+			}
+		}
+		if !nicMatches {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesPattern(pattern string, value interface{}) bool {
+	value = asText(flattenValue(value))
+	matched, err := path.Match(pattern, value.(string))
+	return value == pattern || (err == nil && matched)
+}
+
+// removeItem implements "remove_*". Items with children can only be removed recursively.
+func (s *Server) removeItem(what string, args []interface{}) (interface{}, *fault) {
+	if err := s.checkToken(args, 1); err != nil {
+		return nil, err
+	}
+	name := stringArg(args, 0)
+	if _, ok := s.items[what][name]; !ok {
+		return nil, cx("internal error, unknown %s name %s", what, name)
+	}
+	recursive := boolArg(args, 2)
+	if !recursive && len(s.children(what, name)) > 0 {
+		return nil, cx("removal would orphan %s %s, remove it recursively", what, name)
+	}
+	s.remove(what, name)
+	return true, nil
+}
+
+func (s *Server) remove(what, name string) {
+	for childWhat, refs := range references {
+		for _, ref := range refs {
+			if ref.what != what {
+				continue
+			}
+			for childName, child := range s.items[childWhat] {
+				if child[ref.attribute] == name {
+					s.remove(childWhat, childName)
+				}
+			}
+		}
+	}
+	delete(s.items[what], name)
+	s.touch()
+}
+
+// renameItem implements "rename_*". The references of the children are updated.
+func (s *Server) renameItem(what string, args []interface{}) (interface{}, *fault) {
+	if err := s.checkToken(args, 2); err != nil {
+		return nil, err
+	}
+	item, saved, err := s.lookupHandle(what, stringArg(args, 0))
+	if err != nil {
+		return nil, err
+	}
+	newName := stringArg(args, 1)
+	if newName == "" {
+		return nil, valueError("the name of the %s must not be empty", what)
+	}
+	if _, exists := s.items[what][newName]; exists {
+		return nil, cx("%s %s already exists", what, newName)
+	}
+	oldName := item["name"]
+	item["name"] = newName
+	if !saved {
+		return true, nil
+	}
+	delete(s.items[what], oldName.(string))
+	s.items[what][newName] = item
+	for childWhat, refs := range references {
+		for _, ref := range refs {
+			if ref.what != what {
+				continue
+			}
+			for _, child := range s.items[childWhat] {
+				if child[ref.attribute] == oldName {
+					child[ref.attribute] = newName
+				}
+			}
+		}
+	}
+	item["mtime"] = s.touch()
+	return true, nil
+}
+
+// copyItem implements "copy_*". The copy gets a new uid.
+func (s *Server) copyItem(what string, args []interface{}) (interface{}, *fault) {
+	if err := s.checkToken(args, 2); err != nil {
+		return nil, err
+	}
+	item, _, err := s.lookupHandle(what, stringArg(args, 0))
+	if err != nil {
+		return nil, err
+	}
+	clone := deepCopy(item).(map[string]interface{})
+	clone["name"] = stringArg(args, 1)
+	clone["uid"] = randomHex(16)
+	if err := s.store(what, clone, true); err != nil {
+		return nil, err
+	}
+	return true, nil
+}
+
+// children returns the names of the items that reference the given item as parent.
+func (s *Server) children(what string, name interface{}) []interface{} {
+	var children []string
+	for childWhat, refs := range references {
+		for _, ref := range refs {
+			if ref.what != what {
+				continue
+			}
+			for childName, child := range s.items[childWhat] {
+				if child[ref.attribute] == name {
+					children = append(children, childName)
+				}
+			}
+		}
+	}
+	sort.Strings(children)
+	result := make([]interface{}, 0, len(children))
+	for _, child := range children {
+		result = append(result, child)
+	}
+	return result
+}
+
+// parents returns the chain of items an item inherits from, starting with the direct parent.
+func (s *Server) parents(what string, item map[string]interface{}) []map[string]interface{} {
+	var chain []map[string]interface{}
+	for len(chain) < 16 {
+		var parent map[string]interface{}
+		for _, ref := range references[what] {
+			name, _ := item[ref.attribute].(string)
+			if name == "" || name == inherit {
+				continue
+			}
+			if parent = s.items[ref.what][name]; parent != nil {
+				what = ref.what
+				break
+			}
+		}
+		if parent == nil {
+			break
+		}
+		chain = append(chain, parent)
+		item = parent
+	}
+	return chain
+}
+
+// resolve replaces "<<inherit>>" with the value of the nearest parent or the settings. The mergedAttributes are
+// merged with the ones of the parents instead.
+func (s *Server) resolve(key string, value interface{}, chain []map[string]interface{}) interface{} {
+	var inherited interface{}
+	if len(chain) > 0 {
+		inherited = s.resolve(key, chain[0][key], chain[1:])
+	} else if setting, ok := s.Settings[key]; ok {
+		inherited = deepCopy(setting)
+	} else if setting, ok := s.Settings["default_"+key]; ok {
+		inherited = deepCopy(setting)
+	}
+
+	if value == nil || value == inherit {
+		if inherited == nil {
+			return value
+		}
+		return inherited
+	}
+	own, isMap := value.(map[string]interface{})
+	parent, parentIsMap := inherited.(map[string]interface{})
+	if mergedAttributes[key] && isMap && parentIsMap {
+		merged := deepCopy(parent).(map[string]interface{})
+		for k, v := range own {
+			merged[k] = deepCopy(v)
+		}
+		return merged
+	}
+	return value
+}
+
+// flattenValue converts lists to space separated strings and dictionaries to "key=value" pairs like the flattened
+// items of Cobbler. Other values are returned unmodified.
+func flattenValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, element := range v {
+			parts = append(parts, fmt.Sprint(element))
+		}
+		return strings.Join(parts, " ")
+	case map[string]interface{}:
+		parts := make([]string, 0, len(v))
+		for _, key := range sortedKeys(v) {
+			if v[key] == nil || v[key] == "" {
+				parts = append(parts, key)
+			} else {
+				parts = append(parts, fmt.Sprintf("%s=%v", key, flattenValue(v[key])))
+			}
+		}
+		return strings.Join(parts, " ")
+	}
+	return value
+}
+
+func splitList(text string) []interface{} {
+	fields := strings.Fields(text)
+	list := make([]interface{}, 0, len(fields))
+	for _, field := range fields {
+		list = append(list, field)
+	}
+	return list
+}
+
+// deepCopy copies the structs and arrays of a decoded XML-RPC value.
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return copyMap(v)
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, element := range v {
+			copied[i] = deepCopy(element)
+		}
+		return copied
+	case []string:
+		copied := make([]interface{}, len(v))
+		for i, element := range v {
+			copied[i] = element
+		}
+		return copied
+	}
+	return value
+}
+
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		copied[key] = deepCopy(value)
+	}
+	return copied
+}
+
+// floatArg returns the argument at index as float, integers are converted.
+func floatArg(args []interface{}, index int) float64 {
+	if index >= len(args) {
+		return 0
+	}
+	return floatValue(args[index])
+}
+
+func floatValue(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	}
+	return 0
+}
+
+// asText returns the text of a scalar value as it is compared by "find_*".
+func asText(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	return fmt.Sprint(value)
+}
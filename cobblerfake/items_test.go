@@ -0,0 +1,239 @@
+package cobblerfake
+
+import (
+	"reflect"
+	"testing"
+)
+
+// seededServer returns a server with a distro, a profile and a system.
+func seededServer(t *testing.T) (*Server, string) {
+	t.Helper()
+	server := NewServer()
+	for _, item := range []struct {
+		what       string
+		attributes map[string]interface{}
+	}{
+		{"distro", map[string]interface{}{"name": "distro", "kernel_options": map[string]interface{}{"a": "1", "b": "2"}}},
+		{"profile", map[string]interface{}{"name": "profile", "distro": "distro",
+			"kernel_options": map[string]interface{}{"b": "3"}, "virt_ram": inherit}},
+		{"system", map[string]interface{}{"name": "system", "profile": "profile", "kernel_options": inherit,
+			"virt_ram": inherit, "owners": []interface{}{"admin", "dev"}}},
+	} {
+		if err := server.AddItem(item.what, item.attributes); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return server, server.Login(DefaultUser)
+}
+
+func TestParseItemMethod(t *testing.T) {
+	tests := []struct {
+		method    string
+		args      []interface{}
+		operation string
+		what      string
+		rest      int
+	}{
+		{"get_system", []interface{}{"test"}, "get", "system", 1},
+		{"get_systems", nil, "list", "system", 0},
+		{"get_mgmtclasses_since", []interface{}{1.0}, "since", "mgmtclass", 1},
+		{"get_profile_handle", []interface{}{"test"}, "handle", "profile", 1},
+		{"get_distro_as_rendered", []interface{}{"test"}, "rendered", "distro", 1},
+		{"find_repo", []interface{}{map[string]interface{}{}}, "find", "repo", 1},
+		{"get_item", []interface{}{"menu", "test"}, "get", "menu", 1},
+		{"get_item_names", []interface{}{"distro"}, "names", "distro", 0},
+		{"ping", nil, "", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			// Arrange & Act
+			operation, what, rest, _ := parseItemMethod(tt.method, tt.args)
+
+			// Assert
+			if operation != tt.operation || what != tt.what || len(rest) != tt.rest {
+				t.Errorf("got %s %s %v", operation, what, rest)
+			}
+		})
+	}
+}
+
+func TestItemLifecycle(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	token := server.Login(DefaultUser)
+	if err := server.AddItem("image", map[string]interface{}{"name": "image"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	handle, _ := server.call("new_system", []interface{}{token})
+	_, errName := server.call("modify_system", []interface{}{handle, "name", "test", token})
+	_, errImage := server.call("modify_system", []interface{}{handle, "image", "image", token})
+	beforeSave, _ := server.call("get_system", []interface{}{"test", false, token})
+	_, errSave := server.call("save_system", []interface{}{handle, token, "new"})
+	afterSave, _ := server.call("get_system", []interface{}{"test", false, token})
+	_, errDuplicate := server.call("copy_system", []interface{}{"system::test", "test", token})
+
+	// Assert
+	for _, f := range []*fault{errName, errImage, errSave} {
+		if f != nil {
+			t.Fatal(f)
+		}
+	}
+	if beforeSave != notFound {
+		t.Errorf("expected the system to be invisible before it is saved, got %v", beforeSave)
+	}
+	if system, ok := afterSave.(map[string]interface{}); !ok || system["image"] != "image" || system["uid"] == "" {
+		t.Errorf("unexpected system %v", afterSave)
+	}
+	if errDuplicate == nil || errDuplicate.Message != "An object already exists with that name. Try 'edit'?" {
+		t.Errorf("expected the copy to fail, got %v", errDuplicate)
+	}
+}
+
+func TestSaveWithoutParent(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	token := server.Login(DefaultUser)
+	handle, _ := server.call("new_profile", []interface{}{token})
+	_, _ = server.call("modify_profile", []interface{}{handle, "name", "test", token})
+
+	// Act
+	_, f := server.call("save_profile", []interface{}{handle, token, "new"})
+
+	// Assert
+	if f == nil {
+		t.Errorf("expected a profile without distro or parent to be rejected")
+	}
+}
+
+func TestModifyInvalidReference(t *testing.T) {
+	// Arrange
+	server, token := seededServer(t)
+
+	// Act
+	_, f := server.call("modify_system", []interface{}{"system::system", "profile", "missing", token})
+
+	// Assert
+	if f == nil || f.Message != "invalid profile name: missing" {
+		t.Errorf("expected the reference to be rejected, got %v", f)
+	}
+}
+
+func TestInterfaces(t *testing.T) {
+	// Arrange
+	server, token := seededServer(t)
+	fields := map[string]interface{}{"mac_address-eth0": "aa:bb:cc:dd:ee:ff", "cnames-eth0": "a b"}
+
+	// Act
+	_, errModify := server.call("modify_system", []interface{}{"system::system", "modify_interface", fields, token})
+	_, errRename := server.call("modify_system", []interface{}{"system::system", "rename_interface",
+		map[string]interface{}{"interface": "eth0", "rename_interface": "eth1"}, token})
+	nics := server.items["system"]["system"]["interfaces"].(map[string]interface{})
+
+	// Assert
+	if errModify != nil || errRename != nil {
+		t.Fatal(errModify, errRename)
+	}
+	nic, ok := nics["eth1"].(map[string]interface{})
+	if !ok || len(nic) != 23 || nic["mac_address"] != "aa:bb:cc:dd:ee:ff" {
+		t.Fatalf("unexpected interfaces %v", nics)
+	}
+	if !reflect.DeepEqual(nic["cnames"], []interface{}{"a", "b"}) {
+		t.Errorf("expected the cnames to be split, got %v", nic["cnames"])
+	}
+}
+
+func TestGetItemResolved(t *testing.T) {
+	// Arrange
+	server, _ := seededServer(t)
+
+	// Act
+	resolved := server.getItem("system", "system", false, true).(map[string]interface{})
+
+	// Assert
+	expected := map[string]interface{}{"a": "1", "b": "3"}
+	if !reflect.DeepEqual(resolved["kernel_options"], expected) {
+		t.Errorf("expected the merged kernel options, got %v", resolved["kernel_options"])
+	}
+	if resolved["virt_ram"] != 512 {
+		t.Errorf("expected the default of the settings, got %v", resolved["virt_ram"])
+	}
+}
+
+func TestGetItemFlattened(t *testing.T) {
+	// Arrange
+	server, _ := seededServer(t)
+
+	// Act
+	flattened := server.getItem("distro", "distro", true, false).(map[string]interface{})
+	system := server.getItem("system", "system", true, false).(map[string]interface{})
+
+	// Assert
+	if flattened["kernel_options"] != "a=1 b=2" {
+		t.Errorf("expected flattened kernel options, got %v", flattened["kernel_options"])
+	}
+	if system["owners"] != "admin dev" {
+		t.Errorf("expected flattened owners, got %v", system["owners"])
+	}
+	if !reflect.DeepEqual(flattened["children"], []interface{}{"profile"}) {
+		t.Errorf("expected the children to keep their structure, got %v", flattened["children"])
+	}
+}
+
+func TestRenameUpdatesChildren(t *testing.T) {
+	// Arrange
+	server, token := seededServer(t)
+
+	// Act
+	_, f := server.call("rename_profile", []interface{}{"profile::profile", "renamed", token})
+
+	// Assert
+	if f != nil {
+		t.Fatal(f)
+	}
+	if server.items["system"]["system"]["profile"] != "renamed" {
+		t.Errorf("expected the system to reference the renamed profile")
+	}
+	if _, ok := server.items["profile"]["profile"]; ok {
+		t.Errorf("expected the old name to be gone")
+	}
+}
+
+func TestRemoveRecursive(t *testing.T) {
+	// Arrange
+	server, token := seededServer(t)
+
+	// Act
+	_, errOrphan := server.call("remove_distro", []interface{}{"distro", token, false})
+	_, errRecursive := server.call("remove_distro", []interface{}{"distro", token, true})
+
+	// Assert
+	if errOrphan == nil {
+		t.Errorf("expected the distro with children not to be removed")
+	}
+	if errRecursive != nil {
+		t.Fatal(errRecursive)
+	}
+	if len(server.items["profile"]) != 0 || len(server.items["system"]) != 0 {
+		t.Errorf("expected the children to be removed")
+	}
+}
+
+func TestGetItemsSince(t *testing.T) {
+	// Arrange
+	server, token := seededServer(t)
+	since := server.mtime
+	_, _ = server.call("modify_profile", []interface{}{"profile::profile", "comment", "changed", token})
+
+	// Act
+	result, f := server.call("get_profiles_since", []interface{}{since})
+
+	// Assert
+	if f != nil {
+		t.Fatal(f)
+	}
+	if profiles := result.([]interface{}); len(profiles) != 1 {
+		t.Errorf("expected the modified profile, got %v", profiles)
+	}
+}
@@ -0,0 +1,456 @@
+// Package cobblerfake implements an in-process fake of the Cobbler XML-RPC API for integration tests. The [Server]
+// keeps all items in memory and implements the lifecycle the client relies on: handles from "new_*" and
+// "get_*_handle", "modify_*" and "save_*", the "~" marker for items that don't exist, "<<inherit>>" values resolved
+// through the parents of an item, network interfaces of systems, tokens and background tasks with event ids.
+//
+//	server := httptest.NewServer(cobblerfake.NewServer())
+//	defer server.Close()
+//	c := cobblerclient.NewClient(http.DefaultClient, cobblerclient.ClientConfig{
+//		URL:      server.URL,
+//		Username: cobblerfake.DefaultUser,
+//		Password: cobblerfake.DefaultPassword,
+//	})
+//
+// The fake doesn't render templates, run real tasks or write anything to disk. Methods it doesn't implement fail like
+// they do on a server that doesn't know them.
+package cobblerfake
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cobbler/cobblerclient/cobblertest"
+)
+
+const (
+	// DefaultUser is the user that is accepted by "login" of a new server.
+	DefaultUser = "cobbler"
+	// DefaultPassword is the password of DefaultUser.
+	DefaultPassword = "cobbler"
+
+	inherit  = "<<inherit>>"
+	notFound = "~"
+)
+
+// fault is an exception raised by the handler of a method. It is sent as XML-RPC fault in the format of Cobbler.
+type fault struct {
+	// Exception is the Python class of the exception, e.g. "cobbler.cexceptions.CX".
+	Exception string
+	Message   string
+	// quoted is set for exceptions whose message Python prints with repr, like the ones of Cobbler.
+	quoted bool
+}
+
+// String returns the fault string, e.g. "<class 'cobbler.cexceptions.CX'>:'invalid token: abc'".
+func (f *fault) String() string {
+	if f.quoted {
+		return fmt.Sprintf("<class '%s'>:'%s'", f.Exception, f.Message)
+	}
+	return fmt.Sprintf("<class '%s'>:%s", f.Exception, f.Message)
+}
+
+// cx creates a fault for a CX, the exception Cobbler uses for most errors.
+func cx(format string, args ...interface{}) *fault {
+	return &fault{Exception: "cobbler.cexceptions.CX", Message: fmt.Sprintf(format, args...), quoted: true}
+}
+
+// valueError creates a fault for a ValueError, e.g. of a rejected attribute value.
+func valueError(format string, args ...interface{}) *fault {
+	return &fault{Exception: "ValueError", Message: fmt.Sprintf(format, args...)}
+}
+
+// Server is an [http.Handler] that answers XML-RPC requests like a Cobbler server. The exported fields configure the
+// server and must not be changed once it handles requests. A Server is safe for concurrent use.
+type Server struct {
+	// Users maps the users accepted by "login" to their passwords.
+	Users map[string]string
+	// Version is returned by "version" and "extended_version".
+	Version [3]int
+	// Settings are returned by "get_settings". They are also the last step when "<<inherit>>" values are resolved:
+	// an attribute that none of the parents defines is looked up as "<name>" and "default_<name>".
+	Settings map[string]interface{}
+
+	mu sync.Mutex
+	// tokens maps the tokens returned by "login" to their users.
+	tokens map[string]string
+	// items holds the saved items by type and name.
+	items map[string]map[string]map[string]interface{}
+	// pending holds the items of "new_*" by their handle until they are saved.
+	pending map[string]*pendingItem
+	events  map[string]*event
+	// counter numbers the event ids.
+	counter int
+	// mtime is the time of the last modification, returned by "last_modified_time".
+	mtime float64
+}
+
+// pendingItem is an item that was created with "new_*" but not saved yet.
+type pendingItem struct {
+	what       string
+	attributes map[string]interface{}
+}
+
+// event is a background task. All tasks complete immediately.
+type event struct {
+	time   float64
+	name   string
+	state  string
+	log    string
+	readBy []string
+}
+
+// NewServer creates a server without items that accepts [DefaultUser] and reports version 3.3.4.
+func NewServer() *Server {
+	return &Server{
+		Users:   map[string]string{DefaultUser: DefaultPassword},
+		Version: [3]int{3, 3, 4},
+		Settings: map[string]interface{}{
+			"allow_dynamic_settings":   false,
+			"default_virt_bridge":      "xenbr0",
+			"default_virt_disk_driver": "raw",
+			"default_virt_file_size":   5.0,
+			"default_virt_ram":         512,
+			"default_virt_type":        "xenpv",
+			"next_server_v4":           "127.0.0.1",
+			"next_server_v6":           "::1",
+			"server":                   "127.0.0.1",
+		},
+		tokens:  make(map[string]string),
+		items:   make(map[string]map[string]map[string]interface{}),
+		pending: make(map[string]*pendingItem),
+		events:  make(map[string]*event),
+		mtime:   now(),
+	}
+}
+
+// ServeHTTP answers a single XML-RPC request. Faults are sent with status 200 like Cobbler does, only requests that
+// can't be parsed are rejected with 400.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "XML-RPC requests must be sent with POST", http.StatusMethodNotAllowed)
+		return
+	}
+	call, err := cobblertest.DecodeCall(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("malformed XML-RPC request: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	result, callFault := s.call(call.Method, call.Params)
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/xml")
+	if callFault != nil {
+		_, _ = w.Write(cobblertest.EncodeFault(1, callFault.String()))
+		return
+	}
+	_, _ = w.Write(cobblertest.EncodeResponse(result))
+}
+
+// Login returns a new token for the user without checking a password, e.g. to prepare a test.
+func (s *Server) Login(user string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.newToken(user)
+}
+
+// ExpireTokens invalidates all tokens, thus the next call with a token fails with "invalid token".
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = make(map[string]string)
+}
+
+// call dispatches a method. The caller must hold the lock.
+func (s *Server) call(method string, args []interface{}) (interface{}, *fault) {
+	switch method {
+	case "system.multicall":
+		return s.multicall(args)
+	case "ping":
+		return true, nil
+	case "version":
+		return float64(s.Version[0]) + float64(s.Version[1])/10, nil
+	case "extended_version":
+		return s.extendedVersion(), nil
+	case "login":
+		return s.login(args)
+	case "logout":
+		if err := s.checkToken(args, 0); err != nil {
+			return nil, err
+		}
+		delete(s.tokens, stringArg(args, 0))
+		return true, nil
+	case "token_check":
+		if err := s.checkToken(args, 0); err != nil {
+			return nil, err
+		}
+		return true, nil
+	case "get_user_from_token":
+		if err := s.checkToken(args, 0); err != nil {
+			return nil, err
+		}
+		return s.tokens[stringArg(args, 0)], nil
+	case "check_access", "check_access_no_fail":
+		if err := s.checkToken(args, 0); err != nil {
+			return nil, err
+		}
+		return 1, nil
+	case "get_authn_module_name":
+		return "authentication.configfile", nil
+	case "last_modified_time":
+		return s.mtime, nil
+	case "get_settings":
+		return copyMap(s.Settings), nil
+	case "modify_setting":
+		return s.modifySetting(args)
+	case "sync":
+		if err := s.checkToken(args, 0); err != nil {
+			return nil, err
+		}
+		return true, nil
+	case "get_events":
+		return s.getEvents(stringArg(args, 0)), nil
+	case "get_event_log":
+		return s.getEventLog(stringArg(args, 0))
+	case "get_task_status":
+		return s.getTaskStatus(stringArg(args, 0))
+	}
+	if strings.HasPrefix(method, "background_") {
+		return s.background(method, args)
+	}
+	if result, handled, err := s.callItemMethod(method, args); handled {
+		return result, err
+	}
+	return nil, &fault{Exception: "Exception", Message: fmt.Sprintf("method %q is not supported", method)}
+}
+
+// multicall implements "system.multicall". Every call succeeds or fails on its own, thus a fault is returned as the
+// element of the call and not for the whole request.
+func (s *Server) multicall(args []interface{}) (interface{}, *fault) {
+	calls, ok := sliceArg(args, 0)
+	if !ok {
+		return nil, &fault{Exception: "TypeError", Message: "system.multicall expects an array of calls"}
+	}
+	results := make([]interface{}, 0, len(calls))
+	for _, rawCall := range calls {
+		call, ok := rawCall.(map[string]interface{})
+		method, _ := call["methodName"].(string)
+		params, _ := call["params"].([]interface{})
+		if !ok || method == "" {
+			results = append(results, faultStruct(&fault{Exception: "TypeError", Message: "invalid call in multicall"}))
+			continue
+		}
+		if method == "system.multicall" {
+			results = append(results, faultStruct(&fault{Exception: "Exception",
+				Message: "recursive system.multicall forbidden"}))
+			continue
+		}
+		result, callFault := s.call(method, params)
+		if callFault != nil {
+			results = append(results, faultStruct(callFault))
+			continue
+		}
+		results = append(results, []interface{}{result})
+	}
+	return results, nil
+}
+
+func faultStruct(f *fault) map[string]interface{} {
+	return map[string]interface{}{"faultCode": 1, "faultString": f.String()}
+}
+
+func (s *Server) extendedVersion() map[string]interface{} {
+	version := fmt.Sprintf("%d.%d.%d", s.Version[0], s.Version[1], s.Version[2])
+	return map[string]interface{}{
+		"gitdate":       "?",
+		"gitstamp":      "?",
+		"builddate":     "Mon Jan  1 00:00:00 2024",
+		"version":       version,
+		"version_tuple": []interface{}{s.Version[0], s.Version[1], s.Version[2]},
+	}
+}
+
+func (s *Server) login(args []interface{}) (interface{}, *fault) {
+	user, password := stringArg(args, 0), stringArg(args, 1)
+	expected, ok := s.Users[user]
+	if !ok || expected != password {
+		return nil, cx("login failed (%s)", user)
+	}
+	return s.newToken(user), nil
+}
+
+func (s *Server) newToken(user string) string {
+	token := randomHex(16)
+	s.tokens[token] = user
+	return token
+}
+
+// checkToken fails unless the argument at index is a token returned by "login".
+func (s *Server) checkToken(args []interface{}, index int) *fault {
+	token := stringArg(args, index)
+	if _, ok := s.tokens[token]; !ok {
+		return cx("invalid token: %s", token)
+	}
+	return nil
+}
+
+func (s *Server) modifySetting(args []interface{}) (interface{}, *fault) {
+	if err := s.checkToken(args, 2); err != nil {
+		return nil, err
+	}
+	if allowed, _ := s.Settings["allow_dynamic_settings"].(bool); !allowed {
+		return 1, nil
+	}
+	name := stringArg(args, 0)
+	if _, ok := s.Settings[name]; !ok {
+		return 1, nil
+	}
+	s.Settings[name] = args[1]
+	return 0, nil
+}
+
+// background starts a task. The name of the task is derived from the method, e.g. "Sync" for "background_sync".
+func (s *Server) background(method string, args []interface{}) (interface{}, *fault) {
+	if err := s.checkToken(args, 1); err != nil {
+		return nil, err
+	}
+	task := strings.TrimPrefix(method, "background_")
+	name := strings.ToUpper(task[:1]) + task[1:]
+	s.counter++
+	started := time.Now()
+	id := fmt.Sprintf("%s_%s_%06x", started.Format("2006-01-02_150405"), name, s.counter)
+	s.events[id] = &event{
+		time:   float64(started.UnixNano()) / 1e9,
+		name:   name,
+		state:  "complete",
+		log:    fmt.Sprintf("running %s\n### TASK COMPLETE ###\n", task),
+		readBy: make([]string, 0),
+	}
+	return id, nil
+}
+
+// getEvents returns all events. If user is set, only the events the user didn't read yet are returned and they are
+// marked as read by the user.
+func (s *Server) getEvents(user string) map[string]interface{} {
+	events := make(map[string]interface{})
+	for id, e := range s.events {
+		if user != "" {
+			if containsString(e.readBy, user) {
+				continue
+			}
+			e.readBy = append(e.readBy, user)
+		}
+		events[id] = e.array()
+	}
+	return events
+}
+
+func (s *Server) getEventLog(id string) (interface{}, *fault) {
+	e, ok := s.events[id]
+	if !ok {
+		return "?", nil
+	}
+	return e.log, nil
+}
+
+func (s *Server) getTaskStatus(id string) (interface{}, *fault) {
+	e, ok := s.events[id]
+	if !ok {
+		return nil, cx("no event with that id")
+	}
+	return e.array(), nil
+}
+
+// array returns the event in the format of "get_events" and "get_task_status".
+func (e *event) array() []interface{} {
+	readBy := make([]interface{}, 0, len(e.readBy))
+	for _, user := range e.readBy {
+		readBy = append(readBy, user)
+	}
+	return []interface{}{e.time, e.name, e.state, readBy}
+}
+
+// touch records a modification for "last_modified_time" and returns the new time.
+func (s *Server) touch() float64 {
+	current := now()
+	if current <= s.mtime {
+		// Keep the time strictly increasing, thus changes within the resolution of the clock are detected.
+		current = s.mtime + 0.000001
+	}
+	s.mtime = current
+	return current
+}
+
+func now() float64 {
+	return float64(time.Now().UnixNano()) / 1e9
+}
+
+func randomHex(bytes int) string {
+	buf := make([]byte, bytes)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// stringArg returns the argument at index if it is a string.
+func stringArg(args []interface{}, index int) string {
+	if index >= len(args) {
+		return ""
+	}
+	value, _ := args[index].(string)
+	return value
+}
+
+// boolArg returns the argument at index if it is a bool.
+func boolArg(args []interface{}, index int) bool {
+	if index >= len(args) {
+		return false
+	}
+	value, _ := args[index].(bool)
+	return value
+}
+
+// sliceArg returns the argument at index if it is an array.
+func sliceArg(args []interface{}, index int) ([]interface{}, bool) {
+	if index >= len(args) {
+		return nil, false
+	}
+	value, ok := args[index].([]interface{})
+	return value, ok
+}
+
+// mapArg returns the argument at index if it is a struct.
+func mapArg(args []interface{}, index int) (map[string]interface{}, bool) {
+	if index >= len(args) {
+		return nil, false
+	}
+	value, ok := args[index].(map[string]interface{})
+	return value, ok
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of a map in ascending order.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
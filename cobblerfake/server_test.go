@@ -0,0 +1,138 @@
+package cobblerfake
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServeHTTP(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	body := `<?xml version="1.0"?><methodCall><methodName>login</methodName><params>` +
+		`<param><value><string>cobbler</string></value></param>` +
+		`<param><value><string>cobbler</string></value></param></params></methodCall>`
+	request := httptest.NewRequest(http.MethodPost, "/cobbler_api", strings.NewReader(body))
+	recorder := httptest.NewRecorder()
+
+	// Act
+	server.ServeHTTP(recorder, request)
+
+	// Assert
+	response, _ := io.ReadAll(recorder.Body)
+	if recorder.Code != http.StatusOK || !strings.Contains(string(response), "<params>") {
+		t.Errorf("expected a successful login, got %d:\n%s", recorder.Code, response)
+	}
+	if len(server.tokens) != 1 {
+		t.Errorf("expected a token to be issued")
+	}
+}
+
+func TestServeHTTPMalformed(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	request := httptest.NewRequest(http.MethodPost, "/cobbler_api", strings.NewReader("<methodCall>"))
+	recorder := httptest.NewRecorder()
+
+	// Act
+	server.ServeHTTP(recorder, request)
+
+	// Assert
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", recorder.Code)
+	}
+}
+
+func TestCallUnsupportedMethod(t *testing.T) {
+	// Arrange
+	server := NewServer()
+
+	// Act
+	_, f := server.call("generate_ipxe", []interface{}{"test"})
+
+	// Assert
+	if f == nil || f.String() != `<class 'Exception'>:method "generate_ipxe" is not supported` {
+		t.Errorf("unexpected fault %v", f)
+	}
+}
+
+func TestCallInvalidToken(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	token := server.Login(DefaultUser)
+	server.ExpireTokens()
+
+	// Act
+	_, f := server.call("new_system", []interface{}{token})
+
+	// Assert
+	if f == nil || !strings.Contains(f.Message, "invalid token") {
+		t.Errorf("expected an invalid token, got %v", f)
+	}
+}
+
+func TestMulticall(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	calls := []interface{}{
+		map[string]interface{}{"methodName": "ping", "params": []interface{}{}},
+		map[string]interface{}{"methodName": "new_system", "params": []interface{}{"invalid"}},
+	}
+
+	// Act
+	result, f := server.call("system.multicall", []interface{}{calls})
+
+	// Assert
+	if f != nil {
+		t.Fatal(f)
+	}
+	results := result.([]interface{})
+	if ping, ok := results[0].([]interface{}); !ok || ping[0] != true {
+		t.Errorf("expected the result of ping to be wrapped in an array, got %v", results[0])
+	}
+	if fault, ok := results[1].(map[string]interface{}); !ok || fault["faultCode"] != 1 {
+		t.Errorf("expected a fault struct for the invalid token, got %v", results[1])
+	}
+}
+
+func TestBackground(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	token := server.Login(DefaultUser)
+
+	// Act
+	id, f := server.call("background_reposync", []interface{}{map[string]interface{}{}, token})
+	unread := server.getEvents("cobbler")
+	read := server.getEvents("cobbler")
+
+	// Assert
+	if f != nil {
+		t.Fatal(f)
+	}
+	status := server.events[id.(string)].array()
+	if status[1] != "Reposync" || status[2] != "complete" {
+		t.Errorf("unexpected event %v", status)
+	}
+	if len(unread) != 1 || len(read) != 0 {
+		t.Errorf("expected the event to be returned once for the user, got %d and %d", len(unread), len(read))
+	}
+}
+
+func TestLastModifiedTime(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	before := server.mtime
+
+	// Act
+	err := server.AddItem("distro", map[string]interface{}{"name": "test"})
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.mtime <= before {
+		t.Errorf("expected the modification time to move forward")
+	}
+}
@@ -0,0 +1,121 @@
+package cobblertest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Matcher matches a value of a call in place of an expected value, see [Expectation.WithArgs] and [Diff].
+type Matcher interface {
+	// Match reports whether the decoded value is acceptable.
+	Match(actual interface{}) bool
+	// String describes the accepted values in messages.
+	String() string
+}
+
+// Any matches every value, e.g. a token that doesn't matter for the test.
+var Any Matcher = anyMatcher{}
+
+type anyMatcher struct{}
+
+func (anyMatcher) Match(interface{}) bool { return true }
+
+func (anyMatcher) String() string { return "<any>" }
+
+// MatchFunc returns a [Matcher] that accepts the values for which match returns true.
+func MatchFunc(description string, match func(actual interface{}) bool) Matcher {
+	return funcMatcher{description: description, match: match}
+}
+
+type funcMatcher struct {
+	description string
+	match       func(interface{}) bool
+}
+
+func (m funcMatcher) Match(actual interface{}) bool { return m.match(actual) }
+
+func (m funcMatcher) String() string { return m.description }
+
+// Diff compares an expected value with a decoded XML-RPC value and returns one line per difference, e.g.
+// `[2]["mac_address-eth0"]: expected "aa:bb:cc:dd:ee:ff", got "aa:bb:cc:dd:ee:00"`. The expected value is passed
+// through [Normalize] first and may contain a [Matcher] at any level. Struct members are compared independent of their
+// order. The result is empty if both values are equal.
+func Diff(expected, actual interface{}) []string {
+	var lines []string
+	diff("", Normalize(expected), actual, &lines)
+	return lines
+}
+
+func diff(path string, expected, actual interface{}, lines *[]string) {
+	report := func(format string, args ...interface{}) {
+		location := path
+		if location == "" {
+			location = "value"
+		}
+		*lines = append(*lines, location+": "+fmt.Sprintf(format, args...))
+	}
+
+	switch e := expected.(type) {
+	case Matcher:
+		if !e.Match(actual) {
+			report("expected %s, got %s", e, Format(actual))
+		}
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			report("expected a struct, got %s", Format(actual))
+			return
+		}
+		for _, key := range unionKeys(e, a) {
+			memberPath := fmt.Sprintf("%s[%q]", path, key)
+			expectedMember, inExpected := e[key]
+			actualMember, inActual := a[key]
+			switch {
+			case !inActual:
+				*lines = append(*lines, fmt.Sprintf("%s: missing, expected %s", memberPath, Format(expectedMember)))
+			case !inExpected:
+				*lines = append(*lines, fmt.Sprintf("%s: unexpected %s", memberPath, Format(actualMember)))
+			default:
+				diff(memberPath, expectedMember, actualMember, lines)
+			}
+		}
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok {
+			report("expected an array, got %s", Format(actual))
+			return
+		}
+		for i := 0; i < len(e) || i < len(a); i++ {
+			elementPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(a):
+				*lines = append(*lines, fmt.Sprintf("%s: missing, expected %s", elementPath, Format(e[i])))
+			case i >= len(e):
+				*lines = append(*lines, fmt.Sprintf("%s: unexpected %s", elementPath, Format(a[i])))
+			default:
+				diff(elementPath, e[i], a[i], lines)
+			}
+		}
+	default:
+		if !reflect.DeepEqual(expected, actual) {
+			report("expected %s, got %s", Format(expected), Format(actual))
+		}
+	}
+}
+
+func unionKeys(a, b map[string]interface{}) []string {
+	union := make(map[string]bool, len(a)+len(b))
+	for key := range a {
+		union[key] = true
+	}
+	for key := range b {
+		union[key] = true
+	}
+	return sortedKeys(union)
+}
+
+// formatDiff indents the lines of a [Diff] for an error message.
+func formatDiff(lines []string) string {
+	return "  " + strings.Join(lines, "\n  ")
+}
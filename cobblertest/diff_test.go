@@ -0,0 +1,53 @@
+package cobblertest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected interface{}
+		actual   interface{}
+		lines    []string
+	}{
+		{name: "equal", expected: []string{"a"}, actual: []interface{}{"a"}},
+		{name: "scalar", expected: 1, actual: 1.0, lines: []string{"value: expected 1, got 1.0"}},
+		{
+			name:     "struct",
+			expected: map[string]interface{}{"mac_address-eth0": "aa", "mtu-eth0": "1500"},
+			actual:   map[string]interface{}{"mac_address-eth0": "bb", "static-eth0": true},
+			lines: []string{
+				`["mac_address-eth0"]: expected "aa", got "bb"`,
+				`["mtu-eth0"]: missing, expected "1500"`,
+				`["static-eth0"]: unexpected true`,
+			},
+		},
+		{
+			name:     "array",
+			expected: []interface{}{"a", []string{"b"}},
+			actual:   []interface{}{"a", "b", "c"},
+			lines:    []string{`[1]: expected an array, got "b"`, `[2]: unexpected "c"`},
+		},
+		{name: "any", expected: []interface{}{Any, "b"}, actual: []interface{}{map[string]interface{}{}, "b"}},
+		{
+			name:     "matcher",
+			expected: MatchFunc("a positive number", func(v interface{}) bool { n, ok := v.(int); return ok && n > 0 }),
+			actual:   -1,
+			lines:    []string{"value: expected a positive number, got -1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange & Act
+			lines := Diff(tt.expected, tt.actual)
+
+			// Assert
+			if !reflect.DeepEqual(lines, tt.lines) {
+				t.Errorf("unexpected diff:\n%s", strings.Join(lines, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package cobblertest
+
+import "fmt"
+
+// NotFound is the result of "get_*" for items that don't exist.
+const NotFound = "~"
+
+// Inherit is the value of attributes that are inherited from the parent of an item.
+const Inherit = "<<inherit>>"
+
+// Item returns the struct of an item like "get_<what>" does. The attributes that every item has, like "uid" and
+// "mtime", are added unless they are given. The client only accepts structs with a "uid" as item.
+func Item(name string, attributes map[string]interface{}) map[string]interface{} {
+	item := map[string]interface{}{
+		"name":     name,
+		"uid":      fmt.Sprintf("%032x", len(name)),
+		"ctime":    1700000000.0,
+		"mtime":    1700000000.0,
+		"depth":    0,
+		"comment":  "",
+		"children": []interface{}{},
+		"owners":   Inherit,
+	}
+	for key, value := range attributes {
+		item[key] = Normalize(value)
+	}
+	return item
+}
+
+// Items returns the result of "get_<what>s" and "find_<what>" with expand set.
+func Items(items ...map[string]interface{}) []interface{} {
+	result := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		result = append(result, item)
+	}
+	return result
+}
+
+// NetworkInterface returns the struct of a network interface of a system with the given attributes. The client only
+// recognizes interfaces with all 23 attributes, the missing ones are added with their default.
+func NetworkInterface(attributes map[string]interface{}) map[string]interface{} {
+	nic := map[string]interface{}{
+		"bonding_opts":         "",
+		"bridge_opts":          "",
+		"cnames":               []interface{}{},
+		"connected_mode":       false,
+		"dhcp_tag":             "",
+		"dns_name":             "",
+		"if_gateway":           "",
+		"interface_master":     "",
+		"interface_type":       "na",
+		"ip_address":           "",
+		"ipv6_address":         "",
+		"ipv6_default_gateway": "",
+		"ipv6_mtu":             "",
+		"ipv6_prefix":          "",
+		"ipv6_secondaries":     []interface{}{},
+		"ipv6_static_routes":   []interface{}{},
+		"mac_address":          "",
+		"management":           false,
+		"mtu":                  "",
+		"netmask":              "",
+		"static":               false,
+		"static_routes":        []interface{}{},
+		"virt_bridge":          "",
+	}
+	for key, value := range attributes {
+		nic[key] = Normalize(value)
+	}
+	return nic
+}
+
+// Handle returns the handle of a saved item like "get_<what>_handle" does.
+func Handle(what, name string) string {
+	return fmt.Sprintf("%s::%s", what, name)
+}
+
+// NewHandle returns a handle of an unsaved item like "new_<what>" does.
+func NewHandle(what string) string {
+	return fmt.Sprintf("___NEW___%s::abc123==", what)
+}
+
+// Multicall returns the result of "system.multicall". Every result is wrapped in an array, except for the values of
+// [MulticallFault], which are passed as they are.
+func Multicall(results ...interface{}) []interface{} {
+	wrapped := make([]interface{}, 0, len(results))
+	for _, result := range results {
+		if f, ok := result.(multicallFault); ok {
+			wrapped = append(wrapped, map[string]interface{}(f))
+			continue
+		}
+		wrapped = append(wrapped, []interface{}{result})
+	}
+	return wrapped
+}
+
+type multicallFault map[string]interface{}
+
+// MulticallFault returns the result of a single call of a multicall that failed with the given fault string.
+func MulticallFault(message string) interface{} {
+	return multicallFault{"faultCode": 1, "faultString": message}
+}
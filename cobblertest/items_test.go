@@ -0,0 +1,40 @@
+package cobblertest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestItem(t *testing.T) {
+	// Arrange
+	attributes := map[string]interface{}{
+		"profile":    "centos",
+		"interfaces": map[string]interface{}{"eth0": NetworkInterface(map[string]interface{}{"mac_address": "aa"})},
+	}
+
+	// Act
+	item := Item("test", attributes)
+
+	// Assert
+	if item["name"] != "test" || item["uid"] == "" || item["profile"] != "centos" {
+		t.Errorf("unexpected item %v", item)
+	}
+	nic := item["interfaces"].(map[string]interface{})["eth0"].(map[string]interface{})
+	if len(nic) != 23 || nic["mac_address"] != "aa" || nic["interface_type"] != "na" {
+		t.Errorf("unexpected interface %v", nic)
+	}
+}
+
+func TestMulticall(t *testing.T) {
+	// Arrange & Act
+	result := Multicall(true, MulticallFault("failed"))
+
+	// Assert
+	expected := []interface{}{
+		[]interface{}{true},
+		map[string]interface{}{"faultCode": 1, "faultString": "failed"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("unexpected result %#v", result)
+	}
+}
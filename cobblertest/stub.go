@@ -0,0 +1,241 @@
+// Package cobblertest provides helpers to test code that uses cobblerclient without a Cobbler server. The [Stub] is
+// an HTTP client that checks every XML-RPC call against a list of expectations and answers it:
+//
+//	stub := cobblertest.NewStub(t)
+//	stub.Expect("get_system_handle").WithArgs("test", cobblertest.Token).Reply(cobblertest.Handle("system", "test"))
+//	stub.Expect("modify_system").WithArgs("system::test", "comment", "new", cobblertest.Any).Reply(true)
+//	c := cobblerclient.NewClient(stub, cobblerclient.ClientConfig{URL: cobblertest.URL})
+//	c.SetToken(cobblertest.Token)
+//
+// Calls that don't match are reported with a diff of the XML-RPC values. Expectations can also be read from fixture
+// files, see [Stub.ExpectFixture]. For tests that need a server with state, see the cobblerfake package.
+package cobblertest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// The values of a client configuration that matches the fixtures of cobblerclient.
+const (
+	URL      = "http://localhost:8081/cobbler_api"
+	Username = "cobbler"
+	Password = "cobbler"
+	// Token is the token of the fixtures. Set it with cobblerclient.Client.SetToken.
+	Token = "securetoken99"
+)
+
+// Stub is an HTTP client for cobblerclient that answers the calls with the responses of the expectations. The
+// expectations must be met in the order they were added. Expectations that weren't met when the test finishes are
+// reported as errors. A Stub is safe for concurrent use.
+type Stub struct {
+	// FixtureDir is the directory of the files used by [Stub.ExpectFixture] and [Expectation.ReplyFixture]. It defaults
+	// to "fixtures".
+	FixtureDir string
+
+	t  testing.TB
+	mu sync.Mutex
+	// expectations holds all expectations, next is the index of the first one that isn't used up.
+	expectations []*Expectation
+	next         int
+	calls        []*Call
+}
+
+// NewStub creates a stub without expectations. Expectations that aren't met are reported when the test finishes.
+func NewStub(t testing.TB) *Stub {
+	s := &Stub{FixtureDir: "fixtures", t: t}
+	t.Cleanup(s.assertMet)
+	return s
+}
+
+// Expectation is a call the [Stub] expects and the response it sends.
+type Expectation struct {
+	stub   *Stub
+	method string
+	// args are the expected parameters. If checkArgs isn't set, any parameters match.
+	args      []interface{}
+	checkArgs bool
+	response  []byte
+	// times is the number of calls the expectation answers, used counts the calls already answered.
+	times    int
+	used     int
+	optional bool
+}
+
+// Expect adds an expectation for a call of method with any parameters. Unless a reply is set, the call is answered
+// with true.
+func (s *Stub) Expect(method string) *Expectation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := &Expectation{stub: s, method: method, response: EncodeResponse(true), times: 1}
+	s.expectations = append(s.expectations, e)
+	return e
+}
+
+// ExpectFixture adds an expectation for the call in the fixture "<name>-req.xml", which is answered with the
+// response in "<name>-res.xml".
+func (s *Stub) ExpectFixture(name string) *Expectation {
+	s.t.Helper()
+	request := s.readFixture(name + "-req.xml")
+	call, err := DecodeCall(bytes.NewReader(request))
+	if err != nil {
+		s.t.Fatalf("cobblertest: parsing fixture %s-req.xml: %s", name, err)
+	}
+	return s.Expect(call.Method).WithArgs(call.Params...).ReplyFixture(name + "-res.xml")
+}
+
+// WithArgs sets the expected parameters of the call. The values are compared with [Diff], thus they may contain a
+// [Matcher] like [Any].
+func (e *Expectation) WithArgs(args ...interface{}) *Expectation {
+	e.stub.mu.Lock()
+	defer e.stub.mu.Unlock()
+	e.args = args
+	e.checkArgs = true
+	return e
+}
+
+// Reply sets the result of the call. The value is converted with [Normalize].
+func (e *Expectation) Reply(result interface{}) *Expectation {
+	return e.ReplyBody(EncodeResponse(result))
+}
+
+// ReplyFault answers the call with a fault, e.g. "<class 'cobbler.cexceptions.CX'>:'invalid token: abc'".
+func (e *Expectation) ReplyFault(message string) *Expectation {
+	return e.ReplyBody(EncodeFault(1, message))
+}
+
+// ReplyFixture answers the call with the content of a file in the FixtureDir of the stub.
+func (e *Expectation) ReplyFixture(file string) *Expectation {
+	e.stub.t.Helper()
+	return e.ReplyBody(e.stub.readFixture(file))
+}
+
+// ReplyBody answers the call with a raw response body.
+func (e *Expectation) ReplyBody(body []byte) *Expectation {
+	e.stub.mu.Lock()
+	defer e.stub.mu.Unlock()
+	e.response = body
+	return e
+}
+
+// Times sets how often the call is expected in a row.
+func (e *Expectation) Times(n int) *Expectation {
+	e.stub.mu.Lock()
+	defer e.stub.mu.Unlock()
+	e.times = n
+	return e
+}
+
+// Optional marks the expectation as not required, thus it isn't reported if the call wasn't received when the test
+// finishes, e.g. because the client is expected to give up before.
+func (e *Expectation) Optional() *Expectation {
+	e.stub.mu.Lock()
+	defer e.stub.mu.Unlock()
+	e.optional = true
+	return e
+}
+
+// String describes the expected call.
+func (e *Expectation) String() string {
+	if !e.checkArgs {
+		return e.method + "(...)"
+	}
+	return (&Call{Method: e.method, Params: e.args}).String()
+}
+
+// Post checks the call against the next expectation and answers it. Calls that don't match are reported as test
+// errors and answered with a fault.
+func (s *Stub) Post(url, bodyType string, body io.Reader) (*http.Response, error) {
+	request, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	call, err := DecodeCall(bytes.NewReader(request))
+	if err != nil {
+		s.t.Errorf("cobblertest: received a malformed request: %s\n%s", err, request)
+		return response(EncodeFault(1, "cobblertest: malformed request")), nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, call)
+	if s.next >= len(s.expectations) {
+		s.t.Errorf("cobblertest: unexpected call %d %s", len(s.calls), call)
+		return response(EncodeFault(1, "cobblertest: unexpected call "+call.Method)), nil
+	}
+	e := s.expectations[s.next]
+	if e.used++; e.used >= e.times {
+		s.next++
+	}
+	if mismatch := e.mismatch(call); len(mismatch) > 0 {
+		s.t.Errorf("cobblertest: call %d doesn't match the expectation %s:\n%s", len(s.calls), e, formatDiff(mismatch))
+		return response(EncodeFault(1, "cobblertest: unexpected call "+call.Method)), nil
+	}
+	return response(e.response), nil
+}
+
+// Do checks the call of the request like [Stub.Post]. Requests whose context is done fail with the error of the
+// context, like they do with [http.Client].
+func (s *Stub) Do(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	return s.Post(req.URL.String(), req.Header.Get("Content-Type"), req.Body)
+}
+
+// Calls returns the calls the stub received so far.
+func (s *Stub) Calls() []*Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Call(nil), s.calls...)
+}
+
+// mismatch returns the differences between the call and the expectation.
+func (e *Expectation) mismatch(call *Call) []string {
+	var lines []string
+	if call.Method != e.method {
+		lines = append(lines, fmt.Sprintf("method: expected %s, got %s", e.method, call.Method))
+	}
+	if e.checkArgs {
+		for _, line := range Diff(e.args, call.Params) {
+			lines = append(lines, "params"+line)
+		}
+	}
+	return lines
+}
+
+// assertMet reports the expectations that weren't met.
+func (s *Stub) assertMet() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.expectations[s.next:] {
+		if e.optional {
+			continue
+		}
+		s.t.Errorf("cobblertest: expected call %s wasn't received (%d of %d calls)", e, e.used, e.times)
+	}
+}
+
+func (s *Stub) readFixture(file string) []byte {
+	s.t.Helper()
+	content, err := os.ReadFile(filepath.Join(s.FixtureDir, file)) // #nosec G304 -- fixtures are provided by the test
+	if err != nil {
+		s.t.Fatalf("cobblertest: reading fixture: %s", err)
+	}
+	return content
+}
+
+func response(body []byte) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": []string{"text/xml"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}
+}
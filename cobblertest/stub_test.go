@@ -0,0 +1,149 @@
+package cobblertest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// recordingT records the errors of a stub instead of failing the test.
+type recordingT struct {
+	*testing.T
+	errors   []string
+	cleanups []func()
+}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingT) Cleanup(f func()) {
+	r.cleanups = append(r.cleanups, f)
+}
+
+func (r *recordingT) finish() {
+	for _, f := range r.cleanups {
+		f()
+	}
+}
+
+func post(t *testing.T, stub *Stub, body string) string {
+	t.Helper()
+	res, err := stub.Post(URL, "text/xml", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, _ := io.ReadAll(res.Body)
+	return string(content)
+}
+
+const modifyCall = `<?xml version="1.0"?><methodCall><methodName>modify_system</methodName><params>` +
+	`<param><value><string>system::test</string></value></param>` +
+	`<param><value><string>comment</string></value></param>` +
+	`<param><value><string>new</string></value></param>` +
+	`<param><value><string>securetoken99</string></value></param></params></methodCall>`
+
+func TestStubExpect(t *testing.T) {
+	// Arrange
+	rt := &recordingT{T: t}
+	stub := NewStub(rt)
+	stub.Expect("modify_system").WithArgs("system::test", "comment", "new", Any).Reply(false)
+
+	// Act
+	response := post(t, stub, modifyCall)
+	rt.finish()
+
+	// Assert
+	if len(rt.errors) != 0 {
+		t.Errorf("unexpected errors %v", rt.errors)
+	}
+	if !strings.Contains(response, "<boolean>0</boolean>") {
+		t.Errorf("expected the reply, got %s", response)
+	}
+	if calls := stub.Calls(); len(calls) != 1 || calls[0].Params[3] != Token {
+		t.Errorf("expected the call to be recorded, got %v", calls)
+	}
+}
+
+func TestStubMismatch(t *testing.T) {
+	// Arrange
+	rt := &recordingT{T: t}
+	stub := NewStub(rt)
+	stub.Expect("modify_system").WithArgs("system::test", "comment", "old", Token)
+
+	// Act
+	response := post(t, stub, modifyCall)
+
+	// Assert
+	if len(rt.errors) != 1 || !strings.Contains(response, "<fault>") {
+		t.Errorf("expected the mismatch to be reported, got %v and %s", rt.errors, response)
+	}
+}
+
+func TestStubUnmetExpectation(t *testing.T) {
+	// Arrange
+	rt := &recordingT{T: t}
+	stub := NewStub(rt)
+	stub.Expect("ping").Times(2)
+	_ = post(t, stub, `<methodCall><methodName>ping</methodName></methodCall>`)
+
+	// Act
+	rt.finish()
+
+	// Assert
+	if len(rt.errors) != 1 || !strings.Contains(rt.errors[0], "wasn't received") {
+		t.Errorf("expected the unmet expectation to be reported, got %v", rt.errors)
+	}
+}
+
+func TestStubOptionalExpectation(t *testing.T) {
+	// Arrange
+	rt := &recordingT{T: t}
+	stub := NewStub(rt)
+	stub.Expect("ping").Optional()
+
+	// Act
+	rt.finish()
+
+	// Assert
+	if len(rt.errors) != 0 {
+		t.Errorf("expected the optional expectation not to be reported, got %v", rt.errors)
+	}
+}
+
+func TestStubUnexpectedCall(t *testing.T) {
+	// Arrange
+	rt := &recordingT{T: t}
+	stub := NewStub(rt)
+
+	// Act
+	response := post(t, stub, modifyCall)
+
+	// Assert
+	if len(rt.errors) != 1 || !strings.Contains(response, "unexpected call modify_system") {
+		t.Errorf("expected the call to be rejected, got %v and %s", rt.errors, response)
+	}
+}
+
+func TestStubExpectFixture(t *testing.T) {
+	// Arrange
+	stub := NewStub(t)
+	stub.FixtureDir = "../fixtures"
+	stub.ExpectFixture("ping")
+	req, _ := http.NewRequest(http.MethodPost, URL, strings.NewReader(
+		`<?xml version="1.0"?><methodCall><methodName>ping</methodName><params></params></methodCall>`))
+
+	// Act
+	res, err := stub.Do(req)
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, _ := io.ReadAll(res.Body)
+	if !strings.Contains(string(content), "<boolean>1</boolean>") {
+		t.Errorf("expected the response of the fixture, got %s", content)
+	}
+}
@@ -0,0 +1,362 @@
+package cobblertest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Call is a decoded XML-RPC request. The parameters are decoded to string, int, bool, float64, nil, []interface{} and
+// map[string]interface{}.
+type Call struct {
+	Method string
+	Params []interface{}
+}
+
+// String formats the call like a function call, e.g. `modify_system("system::test", "comment", "new")`.
+func (c *Call) String() string {
+	args := make([]string, 0, len(c.Params))
+	for _, param := range c.Params {
+		args = append(args, Format(param))
+	}
+	return fmt.Sprintf("%s(%s)", c.Method, strings.Join(args, ", "))
+}
+
+// DecodeCall parses an XML-RPC request.
+func DecodeCall(r io.Reader) (*Call, error) {
+	decoder := xml.NewDecoder(r)
+	call := &Call{Params: make([]interface{}, 0)}
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			if call.Method == "" {
+				return nil, errors.New("missing <methodName>")
+			}
+			return call, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "methodName":
+			if call.Method, err = text(decoder); err != nil {
+				return nil, err
+			}
+			call.Method = strings.TrimSpace(call.Method)
+		case "value":
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			call.Params = append(call.Params, value)
+		}
+	}
+}
+
+// decodeValue decodes the content of a <value> element whose start was already read.
+func decodeValue(decoder *xml.Decoder) (interface{}, error) {
+	var untyped strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.CharData:
+			untyped.Write(t)
+		case xml.EndElement:
+			return untyped.String(), nil
+		case xml.StartElement:
+			value, err := decodeTyped(decoder, t.Name.Local)
+			if err != nil {
+				return nil, err
+			}
+			return value, decoder.Skip()
+		}
+	}
+}
+
+// decodeTyped decodes the content of a type element like <string> whose start was already read.
+func decodeTyped(decoder *xml.Decoder, kind string) (interface{}, error) {
+	switch kind {
+	case "struct":
+		members := make(map[string]interface{})
+		for {
+			start, err := nextStart(decoder)
+			if err != nil || start == nil {
+				return members, err
+			}
+			name, value, err := decodeMember(decoder)
+			if err != nil {
+				return nil, err
+			}
+			members[name] = value
+		}
+	case "array":
+		values := make([]interface{}, 0)
+		if start, err := nextStart(decoder); err != nil || start == nil {
+			return values, err
+		}
+		for {
+			start, err := nextStart(decoder)
+			if err != nil {
+				return nil, err
+			}
+			if start == nil {
+				return values, decoder.Skip()
+			}
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+	case "nil":
+		return nil, decoder.Skip()
+	}
+
+	content, err := text(decoder)
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case "int", "i4", "i8":
+		return strconv.Atoi(strings.TrimSpace(content))
+	case "boolean":
+		return strings.TrimSpace(content) == "1", nil
+	case "double":
+		return strconv.ParseFloat(strings.TrimSpace(content), 64)
+	}
+	return content, nil
+}
+
+// decodeMember decodes the <name> and <value> of a struct member and consumes the end of the member.
+func decodeMember(decoder *xml.Decoder) (string, interface{}, error) {
+	var name string
+	var value interface{}
+	for {
+		start, err := nextStart(decoder)
+		if err != nil {
+			return "", nil, err
+		}
+		if start == nil {
+			return name, value, nil
+		}
+		switch start.Name.Local {
+		case "name":
+			name, err = text(decoder)
+		case "value":
+			value, err = decodeValue(decoder)
+		default:
+			err = decoder.Skip()
+		}
+		if err != nil {
+			return "", nil, err
+		}
+	}
+}
+
+// nextStart returns the next start element or nil if the current element ends first.
+func nextStart(decoder *xml.Decoder) (*xml.StartElement, error) {
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			return &t, nil
+		case xml.EndElement:
+			return nil, nil
+		}
+	}
+}
+
+// text reads the character data up to the end of the current element.
+func text(decoder *xml.Decoder) (string, error) {
+	var builder strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := token.(type) {
+		case xml.CharData:
+			builder.Write(t)
+		case xml.EndElement:
+			return builder.String(), nil
+		case xml.StartElement:
+			return "", fmt.Errorf("unexpected element <%s>", t.Name.Local)
+		}
+	}
+}
+
+// EncodeResponse returns the XML-RPC response with the given result. Besides the types returned by [DecodeCall], all
+// Go values that [Normalize] accepts can be encoded.
+func EncodeResponse(result interface{}) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("<?xml version='1.0'?>\n<methodResponse>\n<params>\n<param>\n")
+	encodeValue(&buf, Normalize(result))
+	buf.WriteString("\n</param>\n</params>\n</methodResponse>\n")
+	return buf.Bytes()
+}
+
+// EncodeFault returns an XML-RPC fault response like the one of Python's xmlrpc.server.
+func EncodeFault(code int, message string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("<?xml version='1.0'?>\n<methodResponse>\n<fault>\n")
+	encodeValue(&buf, map[string]interface{}{"faultCode": code, "faultString": message})
+	buf.WriteString("\n</fault>\n</methodResponse>\n")
+	return buf.Bytes()
+}
+
+// encodeValue writes a normalized value.
+func encodeValue(buf *bytes.Buffer, value interface{}) {
+	buf.WriteString("<value>")
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("<nil/>")
+	case string:
+		buf.WriteString("<string>")
+		_ = xml.EscapeText(buf, []byte(v))
+		buf.WriteString("</string>")
+	case bool:
+		if v {
+			buf.WriteString("<boolean>1</boolean>")
+		} else {
+			buf.WriteString("<boolean>0</boolean>")
+		}
+	case int:
+		fmt.Fprintf(buf, "<int>%d</int>", v)
+	case float64:
+		fmt.Fprintf(buf, "<double>%s</double>", formatFloat(v))
+	case []interface{}:
+		buf.WriteString("<array><data>")
+		for _, element := range v {
+			encodeValue(buf, element)
+		}
+		buf.WriteString("</data></array>")
+	case map[string]interface{}:
+		buf.WriteString("<struct>")
+		for _, key := range sortedKeys(v) {
+			buf.WriteString("<member><name>")
+			_ = xml.EscapeText(buf, []byte(key))
+			buf.WriteString("</name>")
+			encodeValue(buf, v[key])
+			buf.WriteString("</member>")
+		}
+		buf.WriteString("</struct>")
+	default:
+		buf.WriteString("<string>")
+		_ = xml.EscapeText(buf, []byte(fmt.Sprint(v)))
+		buf.WriteString("</string>")
+	}
+	buf.WriteString("</value>")
+}
+
+// Normalize converts a Go value to the types returned by [DecodeCall]: all integers become int, floats become
+// float64, slices and arrays become []interface{} and maps with string keys become map[string]interface{}. Pointers
+// are dereferenced. Other values, like a [Matcher], are returned unmodified.
+func Normalize(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	if _, ok := value.(Matcher); ok {
+		return value
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return Normalize(v.Elem().Interface())
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.String:
+		return v.String()
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return []interface{}{}
+		}
+		normalized := make([]interface{}, v.Len())
+		for i := range normalized {
+			normalized[i] = Normalize(v.Index(i).Interface())
+		}
+		return normalized
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return value
+		}
+		normalized := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			normalized[iter.Key().String()] = Normalize(iter.Value().Interface())
+		}
+		return normalized
+	}
+	return value
+}
+
+// Format formats a value for messages: strings are quoted, structs are printed with sorted keys and doubles always
+// have a decimal point, thus they can be told apart from integers.
+func Format(value interface{}) string {
+	switch v := Normalize(value).(type) {
+	case nil:
+		return "nil"
+	case string:
+		return strconv.Quote(v)
+	case float64:
+		return formatFloat(v)
+	case []interface{}:
+		elements := make([]string, 0, len(v))
+		for _, element := range v {
+			elements = append(elements, Format(element))
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	case map[string]interface{}:
+		members := make([]string, 0, len(v))
+		for _, key := range sortedKeys(v) {
+			members = append(members, fmt.Sprintf("%q: %s", key, Format(v[key])))
+		}
+		return "{" + strings.Join(members, ", ") + "}"
+	case Matcher:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func formatFloat(value float64) string {
+	formatted := strconv.FormatFloat(value, 'f', -1, 64)
+	if !strings.ContainsAny(formatted, ".eE") {
+		formatted += ".0"
+	}
+	return formatted
+}
+
+// sortedKeys returns the keys of a map in ascending order.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
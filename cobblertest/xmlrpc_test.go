@@ -0,0 +1,129 @@
+package cobblertest
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeCall(t *testing.T) {
+	// Arrange
+	body := `<?xml version="1.0"?>
+<methodCall>
+  <methodName>modify_system</methodName>
+  <params>
+    <param><value><string>___NEW___system::abc</string></value></param>
+    <param><value>untyped</value></param>
+    <param><value><struct>
+      <member><name>mtu-eth0</name><value><int>1500</int></value></member>
+      <member><name>static-eth0</name><value><boolean>1</boolean></value></member>
+      <member><name>cnames-eth0</name><value><array><data>
+        <value><string>a</string></value><value><string>b</string></value>
+      </data></array></value></member>
+      <member><name>empty-eth0</name><value><array><data></data></array></value></member>
+    </struct></value></param>
+    <param><value><double>1.5</double></value></param>
+    <param><value><nil/></value></param>
+  </params>
+</methodCall>`
+
+	// Act
+	call, err := DecodeCall(strings.NewReader(body))
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []interface{}{
+		"___NEW___system::abc",
+		"untyped",
+		map[string]interface{}{
+			"mtu-eth0":    1500,
+			"static-eth0": true,
+			"cnames-eth0": []interface{}{"a", "b"},
+			"empty-eth0":  []interface{}{},
+		},
+		1.5,
+		nil,
+	}
+	if call.Method != "modify_system" || !reflect.DeepEqual(call.Params, expected) {
+		t.Errorf("unexpected call %s %#v", call.Method, call.Params)
+	}
+}
+
+func TestDecodeCallMalformed(t *testing.T) {
+	// Arrange
+	body := `<methodCall><params></params></methodCall>`
+
+	// Act
+	_, err := DecodeCall(strings.NewReader(body))
+
+	// Assert
+	if err == nil {
+		t.Errorf("expected an error for a call without a method name")
+	}
+}
+
+func TestEncodeResponse(t *testing.T) {
+	// Arrange
+	result := []interface{}{"a<b", int64(1), true, 2.5, nil, map[string]string{"b": "2", "a": "1"}}
+
+	// Act
+	body := EncodeResponse(result)
+
+	// Assert
+	expected := `<value><array><data><value><string>a&lt;b</string></value><value><int>1</int></value>` +
+		`<value><boolean>1</boolean></value><value><double>2.5</double></value><value><nil/></value>` +
+		`<value><struct><member><name>a</name><value><string>1</string></value></member>` +
+		`<member><name>b</name><value><string>2</string></value></member></struct></value></data></array></value>`
+	if !bytes.Contains(body, []byte(expected)) {
+		t.Errorf("unexpected response:\n%s", body)
+	}
+}
+
+func TestEncodeFault(t *testing.T) {
+	// Arrange
+	message := "<class 'cobbler.cexceptions.CX'>:'invalid token: abc'"
+
+	// Act
+	body := EncodeFault(1, message)
+
+	// Assert
+	expected := `<string>&lt;class &#39;cobbler.cexceptions.CX&#39;&gt;:&#39;invalid token: abc&#39;</string>`
+	if !bytes.Contains(body, []byte("<fault>")) || !bytes.Contains(body, []byte(expected)) {
+		t.Errorf("unexpected fault:\n%s", body)
+	}
+}
+
+func TestCallString(t *testing.T) {
+	// Arrange
+	call := &Call{Method: "modify_system", Params: []interface{}{"system::test", "virt_ram", 1.0,
+		map[string]interface{}{"b": []interface{}{1, true}, "a": nil}}}
+
+	// Act
+	formatted := call.String()
+
+	// Assert
+	expected := `modify_system("system::test", "virt_ram", 1.0, {"a": nil, "b": [1, true]})`
+	if formatted != expected {
+		t.Errorf("expected %s, got %s", expected, formatted)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	// Arrange
+	name := "test"
+	value := map[string]interface{}{"ints": []int32{1, 2}, "strings": []string{"a"}, "nil": []string(nil),
+		"pointer": &name, "float": float32(0.5)}
+
+	// Act
+	normalized := Normalize(value)
+
+	// Assert
+	expected := map[string]interface{}{"ints": []interface{}{1, 2}, "strings": []interface{}{"a"},
+		"nil": []interface{}{}, "pointer": "test", "float": 0.5}
+	if !reflect.DeepEqual(normalized, expected) {
+		t.Errorf("unexpected value %#v", normalized)
+	}
+}
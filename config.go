@@ -0,0 +1,354 @@
+package cobblerclient
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The environment variables read by [LoadConfig] and [LoadConfigFile].
+const (
+	EnvURL             = "COBBLER_URL"
+	EnvUsername        = "COBBLER_USERNAME"
+	EnvPassword        = "COBBLER_PASSWORD"
+	EnvPasswordFile    = "COBBLER_PASSWORD_FILE"
+	EnvPasswordCommand = "COBBLER_PASSWORD_COMMAND"
+	EnvToken           = "COBBLER_TOKEN"
+	// EnvProfile selects the server profile of the configuration file.
+	EnvProfile = "COBBLER_PROFILE"
+	// EnvConfigFile overrides the path of the configuration file.
+	EnvConfigFile = "COBBLER_CONFIG"
+)
+
+// ErrNoURL is returned by the configuration loaders if neither the environment nor the configuration file define the
+// URL of the server.
+var ErrNoURL = errors.New("cobblerclient: no server URL configured")
+
+// ServerProfile describes a single named server inside a configuration file. The password is taken from the first
+// non-empty source of Password, PasswordFile and PasswordCommand, so it doesn't need to be stored in plain text.
+type ServerProfile struct {
+	URL      string `yaml:"url"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	// PasswordFile is the path of a file that contains the password. Trailing line breaks are removed.
+	PasswordFile string `yaml:"password_file"`
+	// PasswordCommand is executed with "sh -c" and its output is used as password. Trailing line breaks are removed.
+	PasswordCommand    string        `yaml:"password_command"`
+	Token              string        `yaml:"token"`
+	CACertFile         string        `yaml:"ca_cert_file"`
+	ClientCertFile     string        `yaml:"client_cert_file"`
+	ClientKeyFile      string        `yaml:"client_key_file"`
+	InsecureSkipVerify bool          `yaml:"insecure_skip_verify"`
+	ProxyURL           string        `yaml:"proxy_url"`
+	UserAgent          string        `yaml:"user_agent"`
+	Timeout            time.Duration `yaml:"timeout"`
+	// ReplicaURLs are read-only replicas of the server. In the INI format they are separated by commas.
+	ReplicaURLs []string `yaml:"replica_urls"`
+}
+
+// ConfigFile is the content of a configuration file with several named servers. In YAML it looks like this:
+//
+//	default: lab
+//	servers:
+//	  lab:
+//	    url: https://cobbler.lab.example.com/cobbler_api
+//	    username: cobbler
+//	    password_command: pass show cobbler/lab
+//
+// The INI format uses one section per server and a "default" key outside of all sections:
+//
+//	default = lab
+//
+//	[lab]
+//	url = https://cobbler.lab.example.com/cobbler_api
+//	username = cobbler
+//	password_file = ~/.config/cobbler/lab.password
+type ConfigFile struct {
+	// Default is the name of the server profile that is used if no profile is selected explicitly.
+	Default string                   `yaml:"default"`
+	Servers map[string]ServerProfile `yaml:"servers"`
+}
+
+// DefaultConfigFile returns the path of the configuration file that is used by [LoadConfig] if [EnvConfigFile] is not
+// set, e.g. "~/.config/cobbler/client.yaml" on Linux. If only a "client.ini" exists in the same directory, that one
+// is returned instead.
+func DefaultConfigFile() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(configDir, "cobbler", "client.yaml")
+	iniPath := filepath.Join(configDir, "cobbler", "client.ini")
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		if _, err := os.Stat(iniPath); err == nil {
+			return iniPath, nil
+		}
+	}
+	return path, nil
+}
+
+// LoadConfig builds a [ClientConfig] from the environment and the configuration file. The file is taken from
+// [EnvConfigFile] or, if that is unset, from [DefaultConfigFile]. A missing default file is not an error. See
+// [LoadConfigFile] for the precedence rules.
+func LoadConfig(profile string) (ClientConfig, error) {
+	path, explicit := os.LookupEnv(EnvConfigFile)
+	if !explicit || path == "" {
+		defaultPath, err := DefaultConfigFile()
+		if err != nil {
+			return loadConfig(nil, "", profile)
+		}
+		if _, err := os.Stat(defaultPath); errors.Is(err, os.ErrNotExist) {
+			return loadConfig(nil, "", profile)
+		}
+		path = defaultPath
+	}
+	return LoadConfigFile(path, profile)
+}
+
+// LoadConfigFile builds a [ClientConfig] from the configuration file at path and the environment. The server profile
+// is selected in this order: the profile argument, [EnvProfile], the default of the file and finally the only server
+// of the file. The environment variables take precedence over the values of the profile. If one of [EnvPassword],
+// [EnvPasswordFile] or [EnvPasswordCommand] is set, all password sources of the profile are ignored.
+func LoadConfigFile(path, profile string) (ClientConfig, error) {
+	configFile, err := ReadConfigFile(path)
+	if err != nil {
+		return ClientConfig{}, err
+	}
+	return loadConfig(configFile, path, profile)
+}
+
+// ReadConfigFile parses the configuration file at path. Files with the extension ".ini", ".conf" or ".cfg" are read in
+// the INI format, all others as YAML.
+func ReadConfigFile(path string) (*ConfigFile, error) {
+	content, err := os.ReadFile(expandHome(path)) // #nosec G304 -- the path is provided by the user
+	if err != nil {
+		return nil, err
+	}
+	var configFile *ConfigFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ini", ".conf", ".cfg":
+		configFile, err = parseINIConfig(content)
+	default:
+		configFile = &ConfigFile{}
+		err = yaml.Unmarshal(content, configFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cobblerclient: parsing %s: %w", path, err)
+	}
+	return configFile, nil
+}
+
+// loadConfig merges the selected profile of configFile, which may be nil, with the environment.
+func loadConfig(configFile *ConfigFile, path, profile string) (ClientConfig, error) {
+	if profile == "" {
+		profile = os.Getenv(EnvProfile)
+	}
+	server, err := selectProfile(configFile, path, profile)
+	if err != nil {
+		return ClientConfig{}, err
+	}
+	applyEnvironment(&server)
+
+	password, err := resolvePassword(server)
+	if err != nil {
+		return ClientConfig{}, err
+	}
+	if server.URL == "" {
+		return ClientConfig{}, fmt.Errorf("%w: set %s or add a server to the configuration file", ErrNoURL, EnvURL)
+	}
+	return ClientConfig{
+		URL:                server.URL,
+		ReplicaURLs:        server.ReplicaURLs,
+		Username:           server.Username,
+		Password:           password,
+		Token:              server.Token,
+		Timeout:            server.Timeout,
+		UserAgent:          server.UserAgent,
+		CACertFile:         expandHome(server.CACertFile),
+		ClientCertFile:     expandHome(server.ClientCertFile),
+		ClientKeyFile:      expandHome(server.ClientKeyFile),
+		InsecureSkipVerify: server.InsecureSkipVerify,
+		ProxyURL:           server.ProxyURL,
+	}, nil
+}
+
+// selectProfile returns the requested profile of configFile. Without an explicitly requested profile, the default of
+// the file or its only server is used.
+func selectProfile(configFile *ConfigFile, path, profile string) (ServerProfile, error) {
+	if configFile == nil {
+		if profile != "" {
+			return ServerProfile{}, fmt.Errorf("cobblerclient: server profile %q requested but no configuration file found", profile)
+		}
+		return ServerProfile{}, nil
+	}
+	if profile == "" {
+		profile = configFile.Default
+	}
+	if profile == "" && len(configFile.Servers) == 1 {
+		for name := range configFile.Servers {
+			profile = name
+		}
+	}
+	if profile == "" {
+		if len(configFile.Servers) == 0 {
+			return ServerProfile{}, nil
+		}
+		return ServerProfile{}, fmt.Errorf("cobblerclient: %s defines several servers but no default", path)
+	}
+	server, ok := configFile.Servers[profile]
+	if !ok {
+		return ServerProfile{}, fmt.Errorf("cobblerclient: server profile %q not found in %s", profile, path)
+	}
+	return server, nil
+}
+
+// applyEnvironment overrides the values of server with the ones set in the environment.
+func applyEnvironment(server *ServerProfile) {
+	for variable, target := range map[string]*string{
+		EnvURL:      &server.URL,
+		EnvUsername: &server.Username,
+		EnvToken:    &server.Token,
+	} {
+		if value, ok := os.LookupEnv(variable); ok {
+			*target = value
+		}
+	}
+
+	password, hasPassword := os.LookupEnv(EnvPassword)
+	passwordFile, hasPasswordFile := os.LookupEnv(EnvPasswordFile)
+	passwordCommand, hasPasswordCommand := os.LookupEnv(EnvPasswordCommand)
+	if hasPassword || hasPasswordFile || hasPasswordCommand {
+		server.Password = password
+		server.PasswordFile = passwordFile
+		server.PasswordCommand = passwordCommand
+	}
+}
+
+// resolvePassword reads the password from the first non-empty password source of server.
+func resolvePassword(server ServerProfile) (string, error) {
+	switch {
+	case server.Password != "":
+		return server.Password, nil
+	case server.PasswordFile != "":
+		content, err := os.ReadFile(expandHome(server.PasswordFile)) // #nosec G304 -- the path is provided by the user
+		if err != nil {
+			return "", fmt.Errorf("cobblerclient: reading password file: %w", err)
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	case server.PasswordCommand != "":
+		var stderr bytes.Buffer
+		cmd := exec.Command("sh", "-c", server.PasswordCommand) // #nosec G204 -- the command is provided by the user
+		cmd.Stderr = &stderr
+		output, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("cobblerclient: running password command: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return strings.TrimRight(string(output), "\r\n"), nil
+	}
+	return "", nil
+}
+
+// expandHome replaces a leading "~" of path with the home directory of the user.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// parseINIConfig parses the INI format described at [ConfigFile].
+func parseINIConfig(content []byte) (*ConfigFile, error) {
+	configFile := &ConfigFile{Servers: make(map[string]ServerProfile)}
+	var section string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			configFile.Servers[section] = configFile.Servers[section]
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNumber)
+		}
+		key, value = strings.TrimSpace(key), strings.Trim(strings.TrimSpace(value), `"`)
+		if section == "" {
+			if key != "default" {
+				return nil, fmt.Errorf("line %d: unknown global key %q", lineNumber, key)
+			}
+			configFile.Default = value
+			continue
+		}
+		server := configFile.Servers[section]
+		if err := setINIValue(&server, key, value); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		configFile.Servers[section] = server
+	}
+	return configFile, scanner.Err()
+}
+
+// iniStringKeys maps the keys of the INI format to the string fields of a [ServerProfile].
+func iniStringKeys(server *ServerProfile) map[string]*string {
+	return map[string]*string{
+		"url":              &server.URL,
+		"username":         &server.Username,
+		"password":         &server.Password,
+		"password_file":    &server.PasswordFile,
+		"password_command": &server.PasswordCommand,
+		"token":            &server.Token,
+		"ca_cert_file":     &server.CACertFile,
+		"client_cert_file": &server.ClientCertFile,
+		"client_key_file":  &server.ClientKeyFile,
+		"proxy_url":        &server.ProxyURL,
+		"user_agent":       &server.UserAgent,
+	}
+}
+
+// setINIValue sets the field of server that belongs to key.
+func setINIValue(server *ServerProfile, key, value string) error {
+	if target, ok := iniStringKeys(server)[key]; ok {
+		*target = value
+		return nil
+	}
+	var err error
+	switch key {
+	case "insecure_skip_verify":
+		server.InsecureSkipVerify, err = strconv.ParseBool(value)
+	case "timeout":
+		server.Timeout, err = time.ParseDuration(value)
+	case "replica_urls":
+		server.ReplicaURLs = nil
+		for _, url := range strings.Split(value, ",") {
+			if url = strings.TrimSpace(url); url != "" {
+				server.ReplicaURLs = append(server.ReplicaURLs, url)
+			}
+		}
+	default:
+		keys := []string{"insecure_skip_verify", "replica_urls", "timeout"}
+		for stringKey := range iniStringKeys(server) {
+			keys = append(keys, stringKey)
+		}
+		sort.Strings(keys)
+		return fmt.Errorf("unknown key %q, expected one of %s", key, strings.Join(keys, ", "))
+	}
+	return err
+}
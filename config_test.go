@@ -0,0 +1,195 @@
+package cobblerclient
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testConfigYAML = `default: lab
+servers:
+  lab:
+    url: https://cobbler.lab.example.com/cobbler_api
+    username: cobbler
+    password: lab-secret
+    timeout: 30s
+  prod:
+    url: https://cobbler.example.com/cobbler_api
+    username: admin
+    password_file: %s
+    insecure_skip_verify: true
+    replica_urls:
+      - https://cobbler-replica.example.com/cobbler_api
+`
+
+const testConfigINI = `# Cobbler servers
+default = prod
+
+[lab]
+url = https://cobbler.lab.example.com/cobbler_api
+username = cobbler
+password_command = echo command-secret
+
+[prod]
+url = https://cobbler.example.com/cobbler_api
+username = admin
+password = "prod-secret"
+timeout = 1m
+replica_urls = https://cobbler-replica1.example.com/cobbler_api, https://cobbler-replica2.example.com/cobbler_api
+`
+
+// isolateConfigEnvironment removes all configuration variables from the environment for the duration of the test and
+// points the user configuration directory to an empty directory.
+func isolateConfigEnvironment(t *testing.T) string {
+	for _, variable := range []string{EnvURL, EnvUsername, EnvPassword, EnvPasswordFile, EnvPasswordCommand, EnvToken,
+		EnvProfile, EnvConfigFile} {
+		t.Setenv(variable, "")
+		FailOnError(t, os.Unsetenv(variable))
+	}
+	configDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	return configDir
+}
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	FailOnError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadConfigFileYAML(t *testing.T) {
+	// Arrange
+	isolateConfigEnvironment(t)
+	passwordFile := writeConfigFile(t, "password", "file-secret\n")
+	path := writeConfigFile(t, "client.yaml", fmt.Sprintf(testConfigYAML, passwordFile))
+
+	// Act
+	lab, labErr := LoadConfigFile(path, "")
+	prod, prodErr := LoadConfigFile(path, "prod")
+
+	// Assert
+	FailOnError(t, labErr)
+	FailOnError(t, prodErr)
+	if lab.URL != "https://cobbler.lab.example.com/cobbler_api" || lab.Password != "lab-secret" ||
+		lab.Timeout != 30*time.Second {
+		t.Errorf("unexpected default profile: %+v", lab)
+	}
+	if prod.Username != "admin" || prod.Password != "file-secret" || !prod.InsecureSkipVerify ||
+		len(prod.ReplicaURLs) != 1 {
+		t.Errorf("unexpected prod profile: %+v", prod)
+	}
+}
+
+func TestLoadConfigFileINI(t *testing.T) {
+	// Arrange
+	isolateConfigEnvironment(t)
+	path := writeConfigFile(t, "client.ini", testConfigINI)
+
+	// Act
+	prod, prodErr := LoadConfigFile(path, "")
+	lab, labErr := LoadConfigFile(path, "lab")
+
+	// Assert
+	FailOnError(t, prodErr)
+	FailOnError(t, labErr)
+	if prod.URL != "https://cobbler.example.com/cobbler_api" || prod.Password != "prod-secret" ||
+		prod.Timeout != time.Minute {
+		t.Errorf("unexpected default profile: %+v", prod)
+	}
+	if len(prod.ReplicaURLs) != 2 || prod.ReplicaURLs[1] != "https://cobbler-replica2.example.com/cobbler_api" {
+		t.Errorf("expected two replicas, got %v", prod.ReplicaURLs)
+	}
+	if lab.Password != "command-secret" {
+		t.Errorf(`"command-secret" expected; got "%s"`, lab.Password)
+	}
+}
+
+func TestLoadConfigEnvironmentPrecedence(t *testing.T) {
+	// Arrange
+	isolateConfigEnvironment(t)
+	path := writeConfigFile(t, "client.ini", testConfigINI)
+	passwordFile := writeConfigFile(t, "password", "env-file-secret")
+	t.Setenv(EnvConfigFile, path)
+	t.Setenv(EnvProfile, "lab")
+	t.Setenv(EnvUsername, "env-user")
+	t.Setenv(EnvPasswordFile, passwordFile)
+	t.Setenv(EnvToken, "securetoken99")
+
+	// Act
+	cfg, err := LoadConfig("")
+
+	// Assert
+	FailOnError(t, err)
+	if cfg.URL != "https://cobbler.lab.example.com/cobbler_api" {
+		t.Errorf("expected the URL of the lab profile, got %s", cfg.URL)
+	}
+	if cfg.Username != "env-user" || cfg.Password != "env-file-secret" || cfg.Token != "securetoken99" {
+		t.Errorf("expected the environment to take precedence: %+v", cfg)
+	}
+	c := NewClient(nil, cfg)
+	if c.Token() != "securetoken99" {
+		t.Errorf("expected the client to use the configured token")
+	}
+}
+
+func TestLoadConfigEnvironmentOnly(t *testing.T) {
+	// Arrange
+	isolateConfigEnvironment(t)
+	t.Setenv(EnvURL, "http://localhost:8081/cobbler_api")
+	t.Setenv(EnvUsername, "cobbler")
+	t.Setenv(EnvPassword, "cobbler")
+
+	// Act
+	cfg, err := LoadConfig("")
+
+	// Assert
+	FailOnError(t, err)
+	if cfg.URL != "http://localhost:8081/cobbler_api" || cfg.Username != "cobbler" || cfg.Password != "cobbler" {
+		t.Errorf("unexpected configuration: %+v", cfg)
+	}
+}
+
+func TestLoadConfigDefaultFile(t *testing.T) {
+	// Arrange
+	configDir := isolateConfigEnvironment(t)
+	FailOnError(t, os.MkdirAll(filepath.Join(configDir, "cobbler"), 0o700))
+	FailOnError(t, os.WriteFile(filepath.Join(configDir, "cobbler", "client.ini"), []byte(testConfigINI), 0o600))
+
+	// Act
+	cfg, err := LoadConfig("")
+
+	// Assert
+	FailOnError(t, err)
+	if cfg.URL != "https://cobbler.example.com/cobbler_api" {
+		t.Errorf("expected the default profile of the INI file, got %s", cfg.URL)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	// Arrange
+	isolateConfigEnvironment(t)
+	path := writeConfigFile(t, "client.ini", testConfigINI)
+
+	// Act
+	_, noURLErr := LoadConfig("")
+	_, unknownProfileErr := LoadConfigFile(path, "staging")
+	_, missingFileErr := LoadConfigFile(filepath.Join(t.TempDir(), "missing.yaml"), "")
+	_, invalidKeyErr := parseINIConfig([]byte("[lab]\nurl = http://localhost\npasswd = secret\n"))
+
+	// Assert
+	if !errors.Is(noURLErr, ErrNoURL) {
+		t.Errorf("expected ErrNoURL, got %v", noURLErr)
+	}
+	if unknownProfileErr == nil {
+		t.Errorf("expected an error for an unknown profile")
+	}
+	if !errors.Is(missingFileErr, os.ErrNotExist) {
+		t.Errorf("expected a missing file error, got %v", missingFileErr)
+	}
+	if invalidKeyErr == nil {
+		t.Errorf("expected an error for an unknown key")
+	}
+}
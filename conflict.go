@@ -0,0 +1,122 @@
+package cobblerclient
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ConflictError is returned by updates and edit sessions with [ClientConfig.ConflictCheck] enabled if the item was
+// modified on the server since it was read. It matches [ErrConflict].
+type ConflictError struct {
+	// What is the item type, e.g. "profile".
+	What string
+	// Name is the name of the item.
+	Name string
+	// Expected is the modification time of the item when it was read, Actual the one the server reported before the
+	// modifications were sent.
+	Expected float64
+	Actual   float64
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s %s was modified on the server at %s after it was read at %s", e.What, e.Name,
+		formatMTime(e.Actual), formatMTime(e.Expected))
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// RefreshError is returned by updates and edit sessions with [ClientConfig.ConflictCheck] enabled if the item was
+// saved, but its new modification time couldn't be read afterwards. The item keeps its old modification time, thus its
+// next update fails with a [ConflictError] unless the item is read again. It unwraps to the error of the read.
+type RefreshError struct {
+	// What is the item type, e.g. "profile".
+	What string
+	// Name is the name of the item.
+	Name string
+	Err  error
+}
+
+func (e *RefreshError) Error() string {
+	return fmt.Sprintf("%s %s was saved, but reading its modification time failed: %s", e.What, e.Name, e.Err)
+}
+
+func (e *RefreshError) Unwrap() error {
+	return e.Err
+}
+
+// formatMTime formats a modification time of Cobbler, which are seconds since the epoch.
+func formatMTime(mtime float64) string {
+	return time.Unix(0, int64(mtime*float64(time.Second))).UTC().Format(time.RFC3339Nano)
+}
+
+// RetryOnConflict calls fn until it doesn't fail with [ErrConflict] or it was called attempts times. fn has to read
+// the item, modify it and update it each time, so the modifications are applied to the latest state of the server.
+// The last error of fn is returned. fn is called at least once, even if attempts is zero or negative.
+//
+//	err := cobbler.RetryOnConflict(3, func() error {
+//		profile, err := client.GetProfile("centos7", false, false)
+//		if err != nil {
+//			return err
+//		}
+//		profile.Comment = "updated"
+//		return client.UpdateProfile(profile)
+//	})
+func RetryOnConflict(attempts int, fn func() error) error {
+	err := fn()
+	for attempt := 1; attempt < attempts && errors.Is(err, ErrConflict); attempt++ {
+		err = fn()
+	}
+	return err
+}
+
+// itemMTime reads the current modification time of an item from the server. The read cache is bypassed.
+func (c *Client) itemMTime(what, name string) (float64, error) {
+	method := "get_" + what
+	result, err := c.getConcreteItem(method, name, false, false)
+	if err != nil {
+		return 0, err
+	}
+	if result == "~" {
+		return 0, notFoundError(what, name)
+	}
+	attributes, err := asMap(method, result)
+	if err != nil {
+		return 0, err
+	}
+	return asFloat(method, attributes["mtime"])
+}
+
+// checkConflict fails with a [ConflictError] if [ClientConfig.ConflictCheck] is enabled and the modification time of
+// the item on the server differs from the one it had when it was read. Items that were never read from the server are
+// not checked.
+func (c *Client) checkConflict(what string, item *Item) error {
+	if !c.config.ConflictCheck || item.MTime == 0 {
+		return nil
+	}
+	mtime, err := c.itemMTime(what, item.Name)
+	if err != nil {
+		return err
+	}
+	if mtime != item.MTime {
+		return &ConflictError{What: what, Name: item.Name, Expected: item.MTime, Actual: mtime}
+	}
+	return nil
+}
+
+// refreshMTime sets the modification time of an item that was saved to the one of the server, so the item can be
+// updated again without a conflict. A failed read is returned as [RefreshError]. It does nothing unless
+// [ClientConfig.ConflictCheck] is enabled.
+func (c *Client) refreshMTime(what string, item *Item) error {
+	if !c.config.ConflictCheck {
+		return nil
+	}
+	mtime, err := c.itemMTime(what, item.Name)
+	if err != nil {
+		return &RefreshError{What: what, Name: item.Name, Err: err}
+	}
+	item.MTime = mtime
+	return nil
+}
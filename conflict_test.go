@@ -0,0 +1,204 @@
+package cobblerclient
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cobbler/cobblerclient/cobblertest"
+)
+
+func conflictCheckedNetworkService(t *testing.T) (*ItemService[network], *cobblertest.Stub) {
+	stub := cobblertest.NewStub(t)
+	checked := config
+	checked.ConflictCheck = true
+	c := NewClient(stub, checked)
+	c.SetToken(cobblertest.Token)
+	c.setCachedVersion(CobblerVersion{3, 3, 4})
+	return NewItemService[network](&c, "network"), stub
+}
+
+func TestUpdateConflict(t *testing.T) {
+	// Arrange
+	s, stub := conflictCheckedNetworkService(t)
+	stub.Expect("get_network").Reply(cobblertest.Item("lan", nil))
+	stub.Expect("get_network").Reply(cobblertest.Item("lan", map[string]interface{}{"mtime": 1700000100.0}))
+	lan, err := s.Get("lan", false, false)
+	FailOnError(t, err)
+	lan.CIDR = "10.0.1.0/24"
+
+	// Act
+	err = s.Update(lan)
+
+	// Assert
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected a ConflictError, got %v", err)
+	}
+	if conflict.Expected != 1700000000.0 || conflict.Actual != 1700000100.0 {
+		t.Errorf("unexpected modification times in %+v", conflict)
+	}
+}
+
+func TestUpdateWithoutConflict(t *testing.T) {
+	// Arrange
+	s, stub := conflictCheckedNetworkService(t)
+	stub.Expect("get_network").Reply(cobblertest.Item("lan", nil))
+	stub.Expect("get_network").Reply(cobblertest.Item("lan", nil))
+	stub.Expect("get_item_handle").Reply(cobblertest.Handle("network", "lan"))
+	stub.Expect("modify_network").WithArgs(cobblertest.Handle("network", "lan"), "cidr", "10.0.1.0/24", cobblertest.Token)
+	stub.Expect("save_network")
+	stub.Expect("get_network").Reply(cobblertest.Item("lan", map[string]interface{}{"mtime": 1700000100.0}))
+	lan, err := s.Get("lan", false, false)
+	FailOnError(t, err)
+	lan.CIDR = "10.0.1.0/24"
+
+	// Act
+	err = s.Update(lan)
+
+	// Assert
+	FailOnError(t, err)
+	if lan.MTime != 1700000100.0 {
+		t.Errorf("expected the modification time of the server, got %f", lan.MTime)
+	}
+}
+
+func TestEditSessionConflict(t *testing.T) {
+	// Arrange
+	s, stub := conflictCheckedNetworkService(t)
+	stub.Expect("get_network").Reply(cobblertest.Item("lan", nil))
+	stub.Expect("get_network").Reply(cobblertest.Item("lan", map[string]interface{}{"mtime": 1700000100.0}))
+	session, err := s.Edit("lan")
+	FailOnError(t, err)
+	session.Item().CIDR = "10.0.1.0/24"
+
+	// Act
+	err = session.Save(EditModeBypass)
+
+	// Assert
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+}
+
+func TestRetryOnConflict(t *testing.T) {
+	tests := []struct {
+		name      string
+		conflicts int
+		wantCalls int
+		wantErr   error
+	}{
+		{name: "no conflict", conflicts: 0, wantCalls: 1},
+		{name: "resolved", conflicts: 2, wantCalls: 3},
+		{name: "exhausted", conflicts: 5, wantCalls: 3, wantErr: ErrConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			calls := 0
+			fn := func() error {
+				calls++
+				if calls <= tt.conflicts {
+					return &ConflictError{What: "profile", Name: "centos7"}
+				}
+				return nil
+			}
+
+			// Act
+			err := RetryOnConflict(3, fn)
+
+			// Assert
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("expected %d calls, got %d", tt.wantCalls, calls)
+			}
+		})
+	}
+}
+
+func TestRetryOnConflictWithoutAttempts(t *testing.T) {
+	// Arrange
+	calls := 0
+
+	// Act
+	err := RetryOnConflict(0, func() error {
+		calls++
+		return &ConflictError{What: "profile", Name: "centos7"}
+	})
+
+	// Assert
+	if !errors.Is(err, ErrConflict) || calls != 1 {
+		t.Errorf("expected the conflict after one call, got %v after %d calls", err, calls)
+	}
+}
+
+func TestRetryOnConflictOtherError(t *testing.T) {
+	// Arrange
+	calls := 0
+	failure := errors.New("failure")
+
+	// Act
+	err := RetryOnConflict(3, func() error {
+		calls++
+		return failure
+	})
+
+	// Assert
+	if err != failure || calls != 1 {
+		t.Errorf("expected the error after one call, got %v after %d calls", err, calls)
+	}
+}
+
+func TestUpdateRefreshFailure(t *testing.T) {
+	// Arrange
+	s, stub := conflictCheckedNetworkService(t)
+	stub.Expect("get_network").Reply(cobblertest.Item("lan", nil))
+	stub.Expect("get_network").Reply(cobblertest.Item("lan", nil))
+	stub.Expect("get_item_handle").Reply(cobblertest.Handle("network", "lan"))
+	stub.Expect("modify_network")
+	stub.Expect("save_network")
+	stub.Expect("get_network").ReplyFault("<class 'cobbler.cexceptions.CX'>:'internal error'")
+	lan, err := s.Get("lan", false, false)
+	FailOnError(t, err)
+	lan.CIDR = "10.0.1.0/24"
+
+	// Act
+	err = s.Update(lan)
+
+	// Assert
+	var refresh *RefreshError
+	if !errors.As(err, &refresh) || errors.Is(err, ErrConflict) {
+		t.Fatalf("expected a RefreshError, got %v", err)
+	}
+	if lan.MTime != 1700000000.0 {
+		t.Errorf("expected the old modification time, got %f", lan.MTime)
+	}
+}
+
+func TestEditSessionRefreshFailure(t *testing.T) {
+	// Arrange
+	s, stub := conflictCheckedNetworkService(t)
+	stub.Expect("get_network").Reply(cobblertest.Item("lan", nil))
+	stub.Expect("get_network").Reply(cobblertest.Item("lan", nil))
+	stub.Expect("get_item_handle").Reply(cobblertest.Handle("network", "lan"))
+	stub.Expect("modify_network")
+	stub.Expect("save_network")
+	stub.Expect("get_network").ReplyFault("<class 'cobbler.cexceptions.CX'>:'internal error'")
+	session, err := s.Edit("lan")
+	FailOnError(t, err)
+	session.Item().CIDR = "10.0.1.0/24"
+
+	// Act
+	err = session.Save(EditModeBypass)
+	errClosed := session.Save(EditModeBypass)
+
+	// Assert
+	var refresh *RefreshError
+	if !errors.As(err, &refresh) {
+		t.Errorf("expected a RefreshError, got %v", err)
+	}
+	if !errors.Is(errClosed, ErrSessionClosed) {
+		t.Errorf("expected the saved session to be closed, got %v", errClosed)
+	}
+}
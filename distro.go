@@ -16,11 +16,7 @@ limitations under the License.
 
 package cobblerclient
 
-import (
-	"fmt"
-	"reflect"
-	"time"
-)
+import "time"
 
 // Distro is a created distro.
 // Get the fields from cobbler/items/distro.py
@@ -53,294 +49,94 @@ func NewDistro() Distro {
 	}
 }
 
-// convertRawDistro ...
-func convertRawDistro(name string, xmlrpcResult interface{}) (*Distro, error) {
-	var distro Distro
-
-	if xmlrpcResult == "~" {
-		return nil, fmt.Errorf("distro %s not found", name)
-	}
-
-	decodeResult, err := decodeCobblerItem(xmlrpcResult, &distro)
-	if err != nil {
-		return nil, err
-	}
-
-	// Now clean the Value structs
-	decodedDistro := decodeResult.(*Distro)
-	err = sanitizeValueMapStruct(&decodedDistro.KernelOptions)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedDistro.KernelOptionsPost)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedDistro.AutoinstallMeta)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedDistro.FetchableFiles)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedDistro.BootFiles)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedDistro.TemplateFiles)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedDistro.MgmtParameters)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueSliceStruct(&decodedDistro.Owners)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueSliceStruct(&decodedDistro.MgmtClasses)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueSliceStruct(&decodedDistro.BootLoaders)
-	return &distro, nil
-}
-
-func sanitizeValueSliceStruct(value *Value[[]string]) error {
-	if value.IsInherited {
-		value.Data = make([]string, 0)
-		value.FlattenedValue = ""
-		value.RawData = make([]string, 0)
-	} else {
-		kopts, err := returnStringSlice(value.RawData, nil)
-		if err == nil {
-			value.Data = kopts
-		} else {
-			kopts, ok := value.RawData.(string)
-			if ok {
-				value.Data = make([]string, 0)
-				value.FlattenedValue = kopts
-			} else {
-				if value.RawData == nil {
-					value.Data = make([]string, 0)
-					value.FlattenedValue = ""
-					value.RawData = make([]string, 0)
-				} else {
-					return fmt.Errorf("error converting raw list value")
-				}
-			}
-		}
-	}
-	return nil
-}
-
-func sanitizeValueMapStruct(value *Value[map[string]interface{}]) error {
-	if value.IsInherited {
-		value.Data = make(map[string]interface{})
-		value.FlattenedValue = ""
-		value.RawData = make(map[string]interface{})
-	} else {
-		kopts, ok := value.RawData.(map[string]interface{})
-		if ok {
-			value.Data = kopts
-		} else {
-			kopts, ok := value.RawData.(string)
-			if ok {
-				value.Data = make(map[string]interface{})
-				value.FlattenedValue = kopts
-			} else {
-				if value.RawData == nil {
-					value.Data = make(map[string]interface{})
-					value.FlattenedValue = ""
-					value.RawData = make(map[string]interface{})
-				} else {
-					return fmt.Errorf("error converting raw map value")
-				}
-			}
-		}
-	}
-	return nil
-}
-
-// convertRawDistrosList...
-func convertRawDistrosList(xmlrpcResult interface{}) ([]*Distro, error) {
-	var distros []*Distro
-
-	for _, d := range xmlrpcResult.([]interface{}) {
-		distro, err := convertRawDistro("unknown", d)
-		if err != nil {
-			return nil, err
-		}
-		distro.Meta = ItemMeta{
-			IsFlattened: false,
-			IsResolved:  false,
-		}
-		distros = append(distros, distro)
-	}
-
-	return distros, nil
+// distros returns the service for distros.
+func (c *Client) distros() *ItemService[Distro] {
+	return NewItemService[Distro](c, "distro")
 }
 
 // GetDistros returns all distros in Cobbler.
 func (c *Client) GetDistros() ([]*Distro, error) {
-	result, err := c.Call("get_distros", "-1", c.Token)
-	if err != nil {
-		return nil, err
-	}
+	return c.distros().GetAll()
+}
 
-	return convertRawDistrosList(result)
+// EachDistro calls fn for every distro in Cobbler. The distros are decoded one by one while the response is read, thus
+// only a single distro is held in memory at a time. The iteration stops at the first error returned by fn, which is
+// returned unchanged.
+func (c *Client) EachDistro(fn func(*Distro) error) error {
+	return c.distros().Each(fn)
 }
 
 // GetDistro returns a single distro obtained by its name.
 func (c *Client) GetDistro(name string, flattened, resolved bool) (*Distro, error) {
-	result, err := c.getConcreteItem("get_distro", name, flattened, resolved)
-	if err != nil {
-		return nil, err
-	}
-
-	distro, err := convertRawDistro(name, result)
-	if err != nil {
-		return nil, err
-	}
-	distro.Meta = ItemMeta{
-		IsFlattened: flattened,
-		IsResolved:  resolved,
-	}
-	return distro, nil
+	return c.distros().Get(name, flattened, resolved)
 }
 
 // CreateDistro creates a distro.
 func (c *Client) CreateDistro(distro Distro) (*Distro, error) {
-	// Make sure a distro with the same name does not already exist
-	if _, err := c.GetDistro(distro.Name, false, false); err == nil {
-		return nil, fmt.Errorf("a Distro with the name %s already exists", distro.Name)
-	}
-
-	result, err := c.Call("new_distro", c.Token)
-	if err != nil {
-		return nil, err
-	}
-	newID := result.(string)
-
-	item := reflect.ValueOf(&distro).Elem()
-	if err := c.updateCobblerFields("distro", item, newID); err != nil {
-		return nil, err
-	}
-
-	if _, err := c.Call("save_distro", newID, c.Token); err != nil {
-		return nil, err
-	}
-
-	return c.GetDistro(distro.Name, false, false)
+	return c.distros().Create(distro)
 }
 
 // UpdateDistro updates a single distro.
 func (c *Client) UpdateDistro(distro *Distro) error {
-	item := reflect.ValueOf(distro).Elem()
-	id, err := c.GetItemHandle("distro", distro.Name)
-	if err != nil {
-		return err
-	}
-
-	if err := c.updateCobblerFields("distro", item, id); err != nil {
-		return err
-	}
-
-	if _, err := c.Call("save_distro", id, c.Token); err != nil {
-		return err
-	}
+	return c.distros().Update(distro)
+}
 
-	return nil
+// EditDistro starts an edit session for the distro with the given name. All modifications are saved at once.
+func (c *Client) EditDistro(name string) (*EditSession[Distro], error) {
+	return c.distros().Edit(name)
 }
 
 // SaveDistro saves all changes performed via XML-RPC to disk on the server side.
 func (c *Client) SaveDistro(objectId, editmode string) error {
-	_, err := c.Call("save_distro", objectId, c.Token, editmode)
-	return err
+	return c.distros().Save(objectId, editmode)
 }
 
 // CopyDistro duplicates a distro on the server with a new name.
 func (c *Client) CopyDistro(objectId, newName string) error {
-	_, err := c.Call("copy_distro", objectId, newName, c.Token)
-	return err
+	return c.distros().Copy(objectId, newName)
+}
+
+// RenameDistro renames a distro with a given object id.
+func (c *Client) RenameDistro(objectId, newName string) error {
+	return c.distros().Rename(objectId, newName)
 }
 
-// DeleteDistro deletes a single Distro by its name.
+// DeleteDistro deletes a single distro by its name.
 func (c *Client) DeleteDistro(name string) error {
-	return c.DeleteDistroRecursive(name, false)
+	return c.distros().Delete(name, false)
 }
 
-// DeleteDistroRecursive deletes a single Distro by its name with the option to do so recursively.
+// DeleteDistroRecursive deletes a single distro by its name with the option to do so recursively.
 func (c *Client) DeleteDistroRecursive(name string, recursive bool) error {
-	_, err := c.Call("remove_distro", name, c.Token, recursive)
-	return err
+	return c.distros().Delete(name, recursive)
 }
 
 // ListDistroNames returns a list of all distro names currently available in Cobbler.
 func (c *Client) ListDistroNames() ([]string, error) {
-	return c.GetItemNames("distro")
-}
-
-// GetDistrosSince returns all distros which were created after the specified date.
-func (c *Client) GetDistrosSince(mtime time.Time) ([]*Distro, error) {
-	var distros []*Distro
-
-	result, err := c.Call("get_distros_since", float64(mtime.Unix()))
-	if err != nil {
-		return nil, err
-	}
-
-	for _, d := range result.([]interface{}) {
-		var distro Distro
-		decodedResult, err := decodeCobblerItem(d, &distro)
-		if err != nil {
-			return nil, err
-		}
-
-		distros = append(distros, decodedResult.(*Distro))
-	}
-
-	return distros, nil
+	return c.distros().ListNames()
 }
 
 // FindDistro searches for one or more distros by any of its attributes.
 func (c *Client) FindDistro(criteria map[string]interface{}) ([]*Distro, error) {
-	var distros []*Distro
-
-	result, err := c.Call("find_distro", criteria, true, c.Token)
-	if err != nil {
-		return nil, err
-	}
-
-	for _, d := range result.([]interface{}) {
-		var distro Distro
-		decodedResult, err := decodeCobblerItem(d, &distro)
-		if err != nil {
-			return nil, err
-		}
-
-		distros = append(distros, decodedResult.(*Distro))
-	}
-
-	return distros, nil
+	return c.distros().Find(criteria)
 }
 
 // FindDistroNames searches for one or more distros by any of its attributes.
 func (c *Client) FindDistroNames(criteria map[string]interface{}) ([]string, error) {
-	resultUnmarshalled, err := c.Call("find_distro", criteria, false, c.Token)
-	return returnStringSlice(resultUnmarshalled, err)
+	return c.distros().FindNames(criteria)
 }
 
-// RenameDistro renames a distro with a given object id.
-func (c *Client) RenameDistro(objectId, newName string) error {
-	_, err := c.Call("rename_distro", objectId, newName, c.Token)
-	return err
+// GetDistrosSince returns all distros which were modified after the specified date.
+func (c *Client) GetDistrosSince(mtime time.Time) ([]*Distro, error) {
+	return c.distros().Since(mtime)
 }
 
 // GetDistroHandle gets the internal ID of a Cobbler item.
 func (c *Client) GetDistroHandle(name string) (string, error) {
-	res, err := c.Call("get_distro_handle", name, c.Token)
-	return returnString(res, err)
+	return c.distros().Handle(name)
+}
+
+// GetDistroAsRendered returns the datastructure after it has passed through Cobblers inheritance structure.
+func (c *Client) GetDistroAsRendered(name string) (map[string]interface{}, error) {
+	return c.distros().AsRendered(name)
 }
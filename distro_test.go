@@ -38,7 +38,7 @@ func TestGetDistros(t *testing.T) {
 
 func TestGetDistro(t *testing.T) {
 	c := createStubHTTPClientSingle(t, "get-distro")
-	c.CachedVersion = CobblerVersion{3, 3, 2}
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
 	distro, err := c.GetDistro("Ubuntu-20.04-x86_64", false, false)
 	FailOnError(t, err)
 
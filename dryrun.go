@@ -0,0 +1,273 @@
+package cobblerclient
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// dryRunHandlePrefix starts all object handles returned by "new_*" calls in dry-run mode.
+const dryRunHandlePrefix = "dry-run::"
+
+// redactedToken replaces the token in the arguments of planned calls.
+const redactedToken = "<token>"
+
+// PlannedCall is a call that modifies data and was recorded by a client in dry-run mode instead of being sent.
+type PlannedCall struct {
+	// Method is the name of the XML-RPC method.
+	Method string
+	// Args are the encoded arguments of the call. The token of the client is replaced by "<token>".
+	Args []interface{}
+	// Result is the synthetic result that was returned to the caller, e.g. the handle of a new object.
+	Result interface{}
+}
+
+func (p PlannedCall) String() string {
+	args := make([]string, 0, len(p.Args))
+	for _, arg := range p.Args {
+		args = append(args, formatPlanValue(arg))
+	}
+	return fmt.Sprintf("%s(%s) = %s", p.Method, strings.Join(args, ", "), formatPlanValue(p.Result))
+}
+
+// formatPlanValue formats a value of a planned call. Maps are printed with sorted keys, thus plans are comparable.
+func formatPlanValue(value interface{}) string {
+	switch typedValue := value.(type) {
+	case string:
+		return fmt.Sprintf("%q", typedValue)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(typedValue))
+		for key := range typedValue {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		members := make([]string, 0, len(keys))
+		for _, key := range keys {
+			members = append(members, fmt.Sprintf("%q: %s", key, formatPlanValue(typedValue[key])))
+		}
+		return "{" + strings.Join(members, ", ") + "}"
+	case []interface{}:
+		elements := make([]string, 0, len(typedValue))
+		for _, element := range typedValue {
+			elements = append(elements, formatPlanValue(element))
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// Plan holds the calls that were recorded by a client in dry-run mode (see [ClientConfig.DryRun]). It is shared
+// between all copies of the client and safe for concurrent use.
+type Plan struct {
+	mu    sync.Mutex
+	calls []PlannedCall
+	// objects are the items created by "new_*" calls by their handle.
+	objects map[string]*plannedObject
+	// handles counts the handles per item type.
+	handles map[string]int
+	events  int
+}
+
+// plannedObject is an item that only exists in the plan. Its attributes are collected from the "modify_*" calls, thus
+// it can be read back like an item of the server.
+type plannedObject struct {
+	what       string
+	attributes map[string]interface{}
+}
+
+func newPlan() *Plan {
+	return &Plan{objects: make(map[string]*plannedObject), handles: make(map[string]int)}
+}
+
+// Calls returns the recorded calls in the order in which they were made.
+func (p *Plan) Calls() []PlannedCall {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]PlannedCall(nil), p.calls...)
+}
+
+// Reset drops all recorded calls and planned items.
+func (p *Plan) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = nil
+	p.objects = make(map[string]*plannedObject)
+	p.handles = make(map[string]int)
+	p.events = 0
+}
+
+// String returns the numbered list of recorded calls, one call per line.
+func (p *Plan) String() string {
+	var builder strings.Builder
+	for i, call := range p.Calls() {
+		fmt.Fprintf(&builder, "%d. %s\n", i+1, call)
+	}
+	return builder.String()
+}
+
+// Plan returns the calls recorded in dry-run mode. It is nil unless [ClientConfig.DryRun] is set.
+func (c *Client) Plan() *Plan {
+	return c.plan
+}
+
+// isMutatingMethod checks if a call of the given XML-RPC method may modify data on the server. Logins aren't
+// considered as modifications. Unknown methods are, so a dry run never sends them to the server.
+func isMutatingMethod(method string) bool {
+	return !IsReadOnlyMethod(method) && method != "login" && method != "logout"
+}
+
+// dryRun wraps the innermost [Invoker] of a client in dry-run mode. Calls that modify data are recorded in the plan and
+// answered with synthetic results. Reads are sent to the server, except for reads of items that only exist in the
+// plan. A "system.multicall" is unpacked, thus its reads are sent one by one while its modifications are recorded.
+func (c *Client) dryRun(next Invoker) Invoker {
+	return func(ctx context.Context, call *RPCCall) (interface{}, error) {
+		if call.Method == "system.multicall" && len(call.Args) == 1 {
+			if calls, ok := call.Args[0].([]interface{}); ok {
+				return c.dryRunMulticall(ctx, calls, next)
+			}
+		}
+		if isMutatingMethod(call.Method) {
+			return c.plan.record(call.Method, redactToken(call.Args, c.Token())), nil
+		}
+		if result, ok := c.plan.read(call.Method, call.Args); ok {
+			return result, nil
+		}
+		return next(ctx, call)
+	}
+}
+
+// dryRunMulticall executes the calls of a "system.multicall" one by one and wraps their results like the server does.
+func (c *Client) dryRunMulticall(ctx context.Context, calls []interface{}, next Invoker) (interface{}, error) {
+	invoker := c.dryRun(next)
+	results := make([]interface{}, 0, len(calls))
+	for _, rawCall := range calls {
+		nested, ok := rawCall.(map[string]interface{})
+		method, _ := nested["methodName"].(string)
+		params, _ := nested["params"].([]interface{})
+		if !ok || method == "" {
+			return nil, fmt.Errorf("cobblerclient: invalid multicall entry %v", rawCall)
+		}
+		result, err := invoker(ctx, &RPCCall{Method: method, Args: params})
+		if err != nil {
+			results = append(results, map[string]interface{}{"faultCode": 1, "faultString": err.Error()})
+			continue
+		}
+		results = append(results, []interface{}{result})
+	}
+	return results, nil
+}
+
+// redactToken replaces the token in a copy of args.
+func redactToken(args []interface{}, token string) []interface{} {
+	if token == "" {
+		return args
+	}
+	return replaceToken(args, token, redactedToken)
+}
+
+// itemOperation splits the method of an item call into the operation and the item type, e.g. "modify_system" into
+// "modify" and "system" or "get_system_handle" into "get_handle" and "system". The generic "*_item" methods take the
+// item type as first argument, which is removed from the returned arguments.
+func itemOperation(method string, args []interface{}) (operation, what string, itemArgs []interface{}) {
+	operation, what, _ = strings.Cut(method, "_")
+	if strings.HasSuffix(what, "_handle") {
+		operation += "_handle"
+		what = strings.TrimSuffix(what, "_handle")
+	}
+	if what == "item" && len(args) > 0 {
+		if itemType, ok := args[0].(string); ok {
+			return operation, itemType, args[1:]
+		}
+	}
+	return operation, what, args
+}
+
+// record appends a call to the plan and returns its synthetic result. "new_*" calls return a handle that is unique
+// within the plan and "background_*" calls return an event id. All other calls report success.
+func (p *Plan) record(method string, args []interface{}) interface{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var result interface{} = true
+	operation, what, itemArgs := itemOperation(method, args)
+	switch operation {
+	case "new":
+		p.handles[what]++
+		handle := fmt.Sprintf("%s%s::%d", dryRunHandlePrefix, what, p.handles[what])
+		// Like on the server, the "uid" marks the attributes as a complete item for the decoder.
+		p.objects[handle] = &plannedObject{what: what, attributes: map[string]interface{}{"uid": handle}}
+		result = handle
+	case "modify":
+		p.modify(what, itemArgs)
+	case "background":
+		p.events++
+		result = fmt.Sprintf("%sevent::%d", dryRunHandlePrefix, p.events)
+	}
+	p.calls = append(p.calls, PlannedCall{Method: method, Args: args, Result: result})
+	return result
+}
+
+// modify applies the arguments of a "modify_*" call to a planned item. The attributes of interfaces are passed as a
+// single map whose keys are the attribute and the interface name joined by a dash, e.g. "mac_address-eth0".
+func (p *Plan) modify(what string, args []interface{}) {
+	if len(args) < 3 {
+		return
+	}
+	handle, _ := args[0].(string)
+	attribute, _ := args[1].(string)
+	object, ok := p.objects[handle]
+	if !ok || object.what != what {
+		return
+	}
+	nic, isNIC := args[2].(map[string]interface{})
+	if attribute != "modify_interface" || !isNIC {
+		object.attributes[attribute] = args[2]
+		return
+	}
+	interfaces, _ := object.attributes["interfaces"].(map[string]interface{})
+	if interfaces == nil {
+		interfaces = make(map[string]interface{})
+		object.attributes["interfaces"] = interfaces
+	}
+	for key, value := range nic {
+		nicAttribute, name, found := strings.Cut(key, "-")
+		if !found {
+			continue
+		}
+		nicAttributes, _ := interfaces[name].(map[string]interface{})
+		if nicAttributes == nil {
+			nicAttributes = make(map[string]interface{})
+			interfaces[name] = nicAttributes
+		}
+		nicAttributes[nicAttribute] = value
+	}
+}
+
+// read answers "get_*" and "get_*_handle" calls for items that only exist in the plan. The item is returned with the
+// attributes set so far, thus multi-step flows like [Client.CreateSystem] can read back the planned item.
+func (p *Plan) read(method string, args []interface{}) (interface{}, bool) {
+	operation, what, itemArgs := itemOperation(method, args)
+	if operation != "get" && operation != "get_handle" || len(itemArgs) == 0 {
+		return nil, false
+	}
+	name, ok := itemArgs[0].(string)
+	if !ok {
+		return nil, false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for handle, object := range p.objects {
+		if object.what != what || object.attributes["name"] != name {
+			continue
+		}
+		if operation == "get_handle" {
+			return handle, true
+		}
+		return deepCopy(reflect.ValueOf(object.attributes)).Interface(), true
+	}
+	return nil, false
+}
@@ -0,0 +1,158 @@
+package cobblerclient
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cobbler/cobblerclient/cobblertest"
+)
+
+// dryRunClient returns a client in dry-run mode whose server doesn't know any item. The methods sent to the server
+// are recorded.
+func dryRunClient(t *testing.T, batch bool, methods *[]string) Client {
+	hc := funcHTTPClient(func(call *cobblertest.Call) string {
+		*methods = append(*methods, call.Method)
+		if call.Method == "get_system" || call.Method == "get_profile" {
+			return xmlrpcParams("<string>~</string>")
+		}
+		t.Errorf("unexpected call of %s", call.Method)
+		return xmlrpcParams("<boolean>1</boolean>")
+	})
+	cfg := config
+	cfg.DryRun = true
+	cfg.BatchUpdates = batch
+	c := NewClient(hc, cfg)
+	c.SetToken("securetoken99")
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
+	return c
+}
+
+func TestDryRunCreateSystem(t *testing.T) {
+	// Arrange
+	var methods []string
+	c := dryRunClient(t, false, &methods)
+	system := NewSystem()
+	system.Name = "test"
+	system.Profile = "centos7-x86_64"
+	system.Interfaces = Interfaces{"eth0": Interface{MACAddress: "aa:bb:cc:dd:ee:ff"}}
+
+	// Act
+	created, err := c.CreateSystem(system)
+
+	// Assert
+	FailOnError(t, err)
+	if len(methods) != 1 || methods[0] != "get_system" {
+		t.Errorf("expected only the existence check to reach the server, got %v", methods)
+	}
+	if created.Name != "test" || created.Profile != "centos7-x86_64" ||
+		created.Interfaces["eth0"].MACAddress != "aa:bb:cc:dd:ee:ff" {
+		t.Errorf("expected the planned system to be returned, got %+v", created)
+	}
+	calls := c.Plan().Calls()
+	if calls[0].Method != "new_system" || calls[0].Result != "dry-run::system::1" {
+		t.Errorf("expected the plan to start with new_system, got %s", calls[0])
+	}
+	if last := calls[len(calls)-1]; last.Method != "save_system" || last.Args[0] != "dry-run::system::1" {
+		t.Errorf("expected the plan to end with save_system, got %s", last)
+	}
+	plan := c.Plan().String()
+	if !strings.HasPrefix(plan, `1. new_system("<token>") = "dry-run::system::1"`) {
+		t.Errorf("unexpected plan:\n%s", plan)
+	}
+	if strings.Contains(plan, "securetoken99") {
+		t.Errorf("the token must not be part of the plan")
+	}
+}
+
+func TestDryRunCreateProfileBatched(t *testing.T) {
+	// Arrange
+	var methods []string
+	c := dryRunClient(t, true, &methods)
+	profile := NewProfile()
+	profile.Name = "test"
+	profile.Distro = "centos7-x86_64"
+
+	// Act
+	created, err := c.CreateProfile(profile)
+
+	// Assert
+	FailOnError(t, err)
+	if created.Name != "test" || created.Distro != "centos7-x86_64" {
+		t.Errorf("expected the planned profile to be returned, got %+v", created)
+	}
+	var modifications int
+	for _, call := range c.Plan().Calls() {
+		if call.Method == "system.multicall" {
+			t.Errorf("expected the multicall to be unpacked")
+		}
+		if call.Method == "modify_profile" {
+			modifications++
+		}
+	}
+	if modifications == 0 {
+		t.Errorf("expected the modifications of the multicall to be planned")
+	}
+}
+
+func TestDryRunReset(t *testing.T) {
+	// Arrange
+	var methods []string
+	c := dryRunClient(t, false, &methods)
+	event, err := c.BackgroundSync(BackgroundSyncOptions{})
+	FailOnError(t, err)
+
+	// Act
+	c.Plan().Reset()
+
+	// Assert
+	if event != "dry-run::event::1" {
+		t.Errorf("expected a synthetic event id, got %s", event)
+	}
+	if len(c.Plan().Calls()) != 0 {
+		t.Errorf("expected an empty plan after the reset")
+	}
+}
+
+func TestDryRunUnprefixedRead(t *testing.T) {
+	// Arrange
+	var methods []string
+	hc := funcHTTPClient(func(call *cobblertest.Call) string {
+		methods = append(methods, call.Method)
+		return xmlrpcParams("<boolean>0</boolean>")
+	})
+	cfg := config
+	cfg.DryRun = true
+	c := NewClient(hc, cfg)
+
+	// Act
+	err := c.IsAutoinstallInUse("default.ks")
+
+	// Assert
+	FailOnError(t, err)
+	if len(methods) != 1 || methods[0] != "is_autoinstall_in_use" {
+		t.Errorf("expected the read to reach the server, got %v", methods)
+	}
+	if len(c.Plan().Calls()) != 0 {
+		t.Errorf("expected an empty plan, got:\n%s", c.Plan())
+	}
+}
+
+func TestIsMutatingMethod(t *testing.T) {
+	tests := map[string]bool{
+		"modify_system":         true,
+		"remove_profile":        true,
+		"background_sync":       true,
+		"sync_dhcp":             true,
+		"power_system":          true,
+		"system.multicall":      true,
+		"get_item_handle":       false,
+		"is_autoinstall_in_use": false,
+		"login":                 false,
+		"frobnicate_system":     true,
+	}
+	for method, want := range tests {
+		if got := isMutatingMethod(method); got != want {
+			t.Errorf("isMutatingMethod(%s) = %v, want %v", method, got, want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package cobblerclient
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// The edit modes of the "save_*" methods.
+const (
+	// EditModeBypass saves the item without further checks. It is used for items that exist already.
+	EditModeBypass = "bypass"
+	// EditModeNew makes the server reject the item if another item with the same name exists.
+	EditModeNew = "new"
+)
+
+// EditSession collects the modifications of a single item and commits them with one save. The modifications are made
+// to the item returned by [EditSession.Item], including in-place edits of its maps and slices and the addition, change
+// or removal of network interfaces of a system. Nothing is sent to the server until [EditSession.Save] is called.
+//
+// A session is closed once it was saved or discarded, further calls of Save fail with [ErrSessionClosed]. If Save
+// fails, the item is not saved and the session stays open, thus the modifications can be corrected and saved again or
+// discarded. Cobbler applies modifications of an existing item to its in-memory copy right away, so the modifications
+// that were sent before the failure are visible to other clients although they were not written to disk.
+type EditSession[T any] struct {
+	service *ItemService[T]
+	item    *T
+	// original is the state of the item when the session was started or last saved.
+	original *T
+	closed   bool
+}
+
+// Edit starts an edit session for the item with the given name. The item is always read from the server, thus the
+// session starts with the current state even if [ClientConfig.ReadCache] is enabled. All calls of the session are sent
+// to the primary server.
+func (s *ItemService[T]) Edit(name string) (*EditSession[T], error) {
+	s = s.onPrimary()
+	item, err := s.get(name, false, false)
+	if err != nil {
+		return nil, err
+	}
+	return &EditSession[T]{service: s, item: item, original: copyItem(item)}, nil
+}
+
+// Item returns the item whose modifications are saved by the session.
+func (e *EditSession[T]) Item() *T {
+	return e.item
+}
+
+// Modified returns true if the item was modified since the session was started.
+func (e *EditSession[T]) Modified() bool {
+	return len(e.updates()) > 0
+}
+
+// Save sends the modifications of the item and saves it with the given edit mode, e.g. [EditModeBypass]. Nothing is
+// sent if the item wasn't modified. The session is closed if Save succeeds. With [ClientConfig.ConflictCheck] enabled,
+// Save fails with a [ConflictError] if the item was modified on the server since the session was started, and with a
+// [RefreshError] if the item was saved and the session closed, but its new modification time couldn't be read.
+func (e *EditSession[T]) Save(editmode string) error {
+	if e.closed {
+		return e.closedError()
+	}
+	updates := e.updates()
+	if len(updates) == 0 {
+		e.closed = true
+		return nil
+	}
+
+	s := e.service
+	if err := s.client.checkConflict(s.what, itemOf(e.original)); err != nil {
+		return err
+	}
+	id, err := s.client.GetItemHandle(s.what, itemOf(e.original).Name)
+	if err != nil {
+		return err
+	}
+	if err := s.client.applyFieldUpdates(s.what, id, updates); err != nil {
+		return err
+	}
+	if err := s.Save(id, editmode); err != nil {
+		return err
+	}
+
+	itemOf(e.item).Meta.snapshot = snapshotItem(s.what, reflect.ValueOf(e.item).Elem())
+	e.original = copyItem(e.item)
+	e.closed = true
+	return s.client.refreshMTime(s.what, itemOf(e.item))
+}
+
+// Discard drops the modifications and restores the item to the state it had when the session was started. The session
+// is closed afterwards. Discarding a closed session has no effect.
+func (e *EditSession[T]) Discard() {
+	if e.closed {
+		return
+	}
+	*e.item = *copyItem(e.original)
+	e.closed = true
+}
+
+// updates returns the modifications of the item. All attributes are modified if the item has no known server state.
+func (e *EditSession[T]) updates() []fieldUpdate {
+	s := e.service
+	return changedFieldUpdates(collectFieldUpdates(s.what, reflect.ValueOf(e.item).Elem()),
+		itemOf(e.item).Meta.snapshot)
+}
+
+// closedError creates the error for a session that was saved or discarded already.
+func (e *EditSession[T]) closedError() error {
+	return fmt.Errorf("%w: %s %s", ErrSessionClosed, e.service.what, itemOf(e.original).Name)
+}
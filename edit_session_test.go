@@ -0,0 +1,105 @@
+package cobblerclient
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cobbler/cobblerclient/cobblertest"
+)
+
+func TestEditSessionSave(t *testing.T) {
+	// Arrange
+	s, stub := networkService(t)
+	stub.Expect("get_network").Reply(cobblertest.Item("lan", map[string]interface{}{"cidr": "10.0.0.0/24"}))
+	stub.Expect("get_item_handle").Reply(cobblertest.Handle("network", "lan"))
+	stub.Expect("modify_network").WithArgs(cobblertest.Handle("network", "lan"), "cidr", "10.0.1.0/24", cobblertest.Token)
+	stub.Expect("modify_network").WithArgs(cobblertest.Handle("network", "lan"), "options",
+		map[string]interface{}{"mtu": "9000"}, cobblertest.Token)
+	stub.Expect("save_network").WithArgs(cobblertest.Handle("network", "lan"), cobblertest.Token, EditModeBypass)
+	session, err := s.Edit("lan")
+	FailOnError(t, err)
+	session.Item().CIDR = "10.0.1.0/24"
+	session.Item().Options["mtu"] = "9000"
+
+	// Act
+	modified := session.Modified()
+	err = session.Save(EditModeBypass)
+	errClosed := session.Save(EditModeBypass)
+
+	// Assert
+	FailOnError(t, err)
+	if !modified {
+		t.Errorf("expected the session to be modified")
+	}
+	if !errors.Is(errClosed, ErrSessionClosed) {
+		t.Errorf("expected ErrSessionClosed, got %v", errClosed)
+	}
+}
+
+func TestEditSessionSaveUnmodified(t *testing.T) {
+	// Arrange
+	s, stub := networkService(t)
+	stub.Expect("get_network").Reply(cobblertest.Item("lan", nil))
+	session, err := s.Edit("lan")
+	FailOnError(t, err)
+
+	// Act
+	err = session.Save(EditModeBypass)
+
+	// Assert
+	FailOnError(t, err)
+}
+
+func TestEditSessionSaveFailure(t *testing.T) {
+	// Arrange
+	s, stub := networkService(t)
+	stub.Expect("get_network").Reply(cobblertest.Item("lan", nil))
+	stub.Expect("get_item_handle").Reply(cobblertest.Handle("network", "lan"))
+	stub.Expect("modify_network").ReplyFault("<class 'ValueError'>:'invalid cidr'")
+	stub.Expect("get_item_handle").Reply(cobblertest.Handle("network", "lan"))
+	stub.Expect("modify_network").WithArgs(cobblertest.Handle("network", "lan"), "cidr", "10.0.1.0/24", cobblertest.Token)
+	stub.Expect("save_network")
+	session, err := s.Edit("lan")
+	FailOnError(t, err)
+	session.Item().CIDR = "10.0.1.0/"
+
+	// Act
+	errInvalid := session.Save(EditModeBypass)
+	session.Item().CIDR = "10.0.1.0/24"
+	err = session.Save(EditModeBypass)
+
+	// Assert
+	if !errors.Is(errInvalid, ErrValidation) {
+		t.Errorf("expected ErrValidation, got %v", errInvalid)
+	}
+	FailOnError(t, err)
+}
+
+func TestEditSessionDiscard(t *testing.T) {
+	// Arrange
+	s, stub := networkService(t)
+	stub.Expect("get_network").Reply(cobblertest.Item("lan", map[string]interface{}{
+		"cidr":    "10.0.0.0/24",
+		"options": map[string]interface{}{"mtu": "1500"},
+	}))
+	session, err := s.Edit("lan")
+	FailOnError(t, err)
+	lan := session.Item()
+	lan.CIDR = "10.0.1.0/24"
+	lan.Options["mtu"] = "9000"
+
+	// Act
+	session.Discard()
+	errClosed := session.Save(EditModeBypass)
+
+	// Assert
+	if lan.CIDR != "10.0.0.0/24" || lan.Options["mtu"] != "1500" {
+		t.Errorf("expected the modifications to be discarded, got %+v", lan)
+	}
+	if session.Modified() {
+		t.Errorf("expected the discarded session to be unmodified")
+	}
+	if !errors.Is(errClosed, ErrSessionClosed) {
+		t.Errorf("expected ErrSessionClosed, got %v", errClosed)
+	}
+}
@@ -0,0 +1,187 @@
+package cobblerclient
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kolo/xmlrpc"
+)
+
+// The sentinel errors can be used together with [errors.Is] to classify all errors returned by the client,
+// independent of whether the error was detected by the server or the client.
+var (
+	// ErrNotFound signals that the requested item does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyExists signals that an item with the same name exists already.
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrPermissionDenied signals that the user of the token is not authorized to perform the call.
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrLoginFailed signals that the server rejected the credentials.
+	ErrLoginFailed = errors.New("login failed")
+	// ErrInvalidToken signals that the token is unknown to the server or expired.
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrValidation signals that a value was rejected either by the client or by the server.
+	ErrValidation = errors.New("validation failed")
+	// ErrMalformedResponse signals that the result of a call doesn't have the shape the client expects. This happens
+	// when something else than Cobbler answers the request, e.g. a proxy, or when the server version isn't supported.
+	ErrMalformedResponse = errors.New("malformed response")
+	// ErrUnsupportedByServer signals that the version of the server doesn't support the call. The client checks this
+	// before a call is sent in case it knows the version that introduced a method.
+	ErrUnsupportedByServer = errors.New("unsupported by server")
+	// ErrSessionClosed signals that an [EditSession] was saved or discarded already.
+	ErrSessionClosed = errors.New("edit session closed")
+	// ErrConflict signals that an item was modified on the server since it was read. See [ClientConfig.ConflictCheck].
+	ErrConflict = errors.New("conflict")
+)
+
+// faultRegex splits the fault string of Cobbler in the exception class and the message.
+// Example: <class 'cobbler.cexceptions.CX'>:'login failed (cobbler)'
+var faultRegex = regexp.MustCompile(`(?s)^<class '([^']+)'>:(.*)$`)
+
+// unknownNameRegex matches the message Cobbler uses for handles of items that don't exist.
+// Example: internal error, unknown system name test
+var unknownNameRegex = regexp.MustCompile(`unknown \w+ name`)
+
+// CobblerFault is the error for all XML-RPC faults returned by the server. It can be matched against the sentinel
+// errors of this package with [errors.Is] and unwraps to the original [xmlrpc.FaultError].
+type CobblerFault struct {
+	// Code is the XML-RPC fault code.
+	Code int
+	// Exception is the fully qualified class name of the server-side exception, e.g. "cobbler.cexceptions.CX". It is
+	// empty if the fault string has an unknown format.
+	Exception string
+	// Message is the message of the server-side exception.
+	Message string
+	// Method is the XML-RPC method that caused the fault.
+	Method string
+
+	fault xmlrpc.FaultError
+}
+
+// newCobblerFault converts an [xmlrpc.FaultError] into a [CobblerFault]. Other errors are returned unmodified.
+func newCobblerFault(method string, err error) error {
+	var fault xmlrpc.FaultError
+	if !errors.As(err, &fault) {
+		return err
+	}
+	cobblerFault := &CobblerFault{
+		Code:    fault.Code,
+		Message: fault.String,
+		Method:  method,
+		fault:   fault,
+	}
+	if matches := faultRegex.FindStringSubmatch(fault.String); matches != nil {
+		cobblerFault.Exception = matches[1]
+		cobblerFault.Message = strings.Trim(matches[2], `'"`)
+	}
+	return cobblerFault
+}
+
+// Error returns the same representation as [xmlrpc.FaultError] does.
+func (f *CobblerFault) Error() string {
+	return f.fault.Error()
+}
+
+// Unwrap returns the original [xmlrpc.FaultError].
+func (f *CobblerFault) Unwrap() error {
+	return f.fault
+}
+
+// Is classifies the fault by its exception class and message.
+func (f *CobblerFault) Is(target error) bool {
+	message := strings.ToLower(f.Message)
+	switch target {
+	case ErrInvalidToken:
+		return strings.Contains(message, "invalid token")
+	case ErrLoginFailed:
+		return strings.Contains(message, "login failed")
+	case ErrPermissionDenied:
+		return strings.Contains(message, "authorization failure") || strings.Contains(message, "permission denied") ||
+			strings.HasSuffix(f.Exception, "PermissionError")
+	case ErrNotFound:
+		return strings.Contains(message, "not found") || unknownNameRegex.MatchString(message) ||
+			strings.HasSuffix(f.Exception, "FileNotFoundError")
+	case ErrAlreadyExists:
+		return strings.Contains(message, "already exists")
+	case ErrUnsupportedByServer:
+		return isUnsupportedMethodFault(message)
+	case ErrValidation:
+		return f.Exception == "ValueError" || f.Exception == "TypeError" ||
+			(strings.HasPrefix(message, "invalid") && !strings.Contains(message, "invalid token"))
+	}
+	return false
+}
+
+// ItemError is returned for client-side failures that concern a single item. Err is one of the sentinel errors of
+// this package.
+type ItemError struct {
+	// What is the item type, e.g. "system".
+	What string
+	// Name is the name of the item.
+	Name string
+	Err  error
+}
+
+func (e *ItemError) Error() string {
+	switch e.Err {
+	case ErrNotFound:
+		return fmt.Sprintf("%s %s not found", e.What, e.Name)
+	case ErrAlreadyExists:
+		return fmt.Sprintf("a %s with the name %s already exists", e.What, e.Name)
+	}
+	return fmt.Sprintf("%s %s: %s", e.What, e.Name, e.Err)
+}
+
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// notFoundError creates the error for an item that could not be found.
+func notFoundError(what, name string) error {
+	return &ItemError{What: what, Name: name, Err: ErrNotFound}
+}
+
+// alreadyExistsError creates the error for an item that exists already.
+func alreadyExistsError(what, name string) error {
+	return &ItemError{What: what, Name: name, Err: ErrAlreadyExists}
+}
+
+// ValidationError is returned when the client rejects a value before sending it or when the server signals that it
+// did not accept a value. It matches [ErrValidation].
+type ValidationError struct {
+	// What is the item type, e.g. "system". It may be empty.
+	What string
+	// Field is the affected attribute. It may be empty.
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// DecodeError is returned when the result of a call doesn't have the expected shape. It matches
+// [ErrMalformedResponse].
+type DecodeError struct {
+	// Method is the XML-RPC method whose result couldn't be decoded.
+	Method string
+	// Expected describes the expected shape of the result, e.g. "string" or "array of strings".
+	Expected string
+	// Value is the part of the result that didn't match.
+	Value interface{}
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("%s: unexpected result of %s: expected %s, got %T", ErrMalformedResponse, e.Method, e.Expected,
+		e.Value)
+}
+
+func (e *DecodeError) Is(target error) bool {
+	return target == ErrMalformedResponse
+}
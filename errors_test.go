@@ -0,0 +1,207 @@
+package cobblerclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kolo/xmlrpc"
+)
+
+func TestNewCobblerFault(t *testing.T) {
+	// Arrange
+	fault := xmlrpc.FaultError{Code: 1, String: `<class 'cobbler.cexceptions.CX'>:'login failed (cobbler)'`}
+
+	// Act
+	err := newCobblerFault("login", fault)
+
+	// Assert
+	var cobblerFault *CobblerFault
+	if !errors.As(err, &cobblerFault) {
+		t.Fatalf("expected a CobblerFault, got %T", err)
+	}
+	if cobblerFault.Exception != "cobbler.cexceptions.CX" {
+		t.Errorf("unexpected exception class %q", cobblerFault.Exception)
+	}
+	if cobblerFault.Message != "login failed (cobbler)" {
+		t.Errorf("unexpected message %q", cobblerFault.Message)
+	}
+	if cobblerFault.Method != "login" || cobblerFault.Code != 1 {
+		t.Errorf("unexpected method or code: %s %d", cobblerFault.Method, cobblerFault.Code)
+	}
+	if err.Error() != fault.Error() {
+		t.Errorf("expected %q, got %q", fault.Error(), err.Error())
+	}
+	var original xmlrpc.FaultError
+	if !errors.As(err, &original) {
+		t.Errorf("expected the fault to unwrap to xmlrpc.FaultError")
+	}
+}
+
+func TestCobblerFaultIs(t *testing.T) {
+	tests := []struct {
+		name   string
+		fault  string
+		target error
+	}{
+		{name: "invalid-token", fault: `<class 'cobbler.cexceptions.CX'>:'invalid token: abc'`, target: ErrInvalidToken},
+		{name: "login-failed", fault: `<class 'cobbler.cexceptions.CX'>:'login failed (cobbler)'`, target: ErrLoginFailed},
+		{name: "permission", fault: `<class 'cobbler.cexceptions.CX'>:'authorization failure for user x'`, target: ErrPermissionDenied},
+		{name: "unknown-name", fault: `<class 'cobbler.cexceptions.CX'>:'internal error, unknown system name x'`, target: ErrNotFound},
+		{name: "exists", fault: `<class 'cobbler.cexceptions.CX'>:'An object already exists with that name.'`, target: ErrAlreadyExists},
+		{name: "value-error", fault: `<class 'ValueError'>:invalid literal for int()`, target: ErrValidation},
+	}
+	sentinels := []error{ErrNotFound, ErrAlreadyExists, ErrPermissionDenied, ErrLoginFailed, ErrInvalidToken, ErrValidation}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newCobblerFault("method", xmlrpc.FaultError{Code: 1, String: tt.fault})
+			for _, sentinel := range sentinels {
+				if got := errors.Is(err, sentinel); got != (sentinel == tt.target) {
+					t.Errorf("errors.Is(%q, %v) = %v", tt.fault, sentinel, got)
+				}
+			}
+		})
+	}
+}
+
+func TestItemError(t *testing.T) {
+	notFound := notFoundError("system", "test")
+	if !errors.Is(notFound, ErrNotFound) || notFound.Error() != "system test not found" {
+		t.Errorf("unexpected not found error: %v", notFound)
+	}
+	exists := alreadyExistsError("system", "test")
+	if !errors.Is(exists, ErrAlreadyExists) || exists.Error() != "a system with the name test already exists" {
+		t.Errorf("unexpected already exists error: %v", exists)
+	}
+	var itemErr *ItemError
+	if !errors.As(exists, &itemErr) || itemErr.What != "system" || itemErr.Name != "test" {
+		t.Errorf("expected an ItemError for system test")
+	}
+}
+
+func TestLoginFaultIsTyped(t *testing.T) {
+	// Arrange
+	c := createStubHTTPClientSingle(t, "login-err")
+
+	// Act
+	_, err := c.Login()
+
+	// Assert
+	var fault *CobblerFault
+	if !errors.As(err, &fault) || fault.Method != "login" {
+		t.Fatalf("expected a CobblerFault for login, got %v", err)
+	}
+	if !errors.Is(err, ErrLoginFailed) {
+		t.Errorf("expected the fault to match ErrLoginFailed")
+	}
+}
+
+// rawHTTPClient answers every request with the same body, independent of the method.
+type rawHTTPClient string
+
+func (r rawHTTPClient) Post(uri, bodyType string, req io.Reader) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(string(r)))}, nil
+}
+
+// malformedResponses are replies of a server (or something in front of it) that don't match what the client expects.
+var malformedResponses = map[string]string{
+	"proxy-error-page": "<html><body><h1>Bad Gateway</h1></body></html>",
+	"string":           "<methodResponse>" + xmlrpcParams("<string>garbage</string>") + "</methodResponse>",
+	"integer":          "<methodResponse>" + xmlrpcParams("<int>42</int>") + "</methodResponse>",
+	"boolean":          "<methodResponse>" + xmlrpcParams("<boolean>1</boolean>") + "</methodResponse>",
+	"empty-struct":     "<methodResponse>" + xmlrpcParams("<struct></struct>") + "</methodResponse>",
+	"empty-array":      "<methodResponse>" + xmlrpcParams("<array><data></data></array>") + "</methodResponse>",
+	"array-of-ints": "<methodResponse>" +
+		xmlrpcParams("<array><data><value><int>1</int></value><value><int>2</int></value></data></array>") +
+		"</methodResponse>",
+	"struct-of-arrays": "<methodResponse>" +
+		xmlrpcParams("<struct><member><name>key</name><value><array><data></data></array></value></member></struct>") +
+		"</methodResponse>",
+}
+
+// malformedResponseArg returns a usable argument of type t for calling a method via reflection.
+func malformedResponseArg(t reflect.Type) reflect.Value {
+	switch {
+	case t == reflect.TypeOf((*context.Context)(nil)).Elem():
+		return reflect.ValueOf(context.Background())
+	case t.Kind() == reflect.Ptr:
+		return reflect.New(t.Elem())
+	case t.Kind() == reflect.Map:
+		return reflect.MakeMap(t)
+	case t.Kind() == reflect.Func:
+		return reflect.MakeFunc(t, func([]reflect.Value) []reflect.Value {
+			results := make([]reflect.Value, t.NumOut())
+			for i := range results {
+				results[i] = reflect.Zero(t.Out(i))
+			}
+			return results
+		})
+	}
+	return reflect.Zero(t)
+}
+
+func TestMalformedResponses(t *testing.T) {
+	// Methods that panic on purpose for invalid arguments of the caller.
+	skipped := []string{"WithContext"}
+
+	for name, body := range malformedResponses {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			c := NewClient(rawHTTPClient(body), config)
+			c.SetToken("securetoken99")
+			c.setCachedVersion(CobblerVersion{3, 3, 2})
+			clientValue := reflect.ValueOf(&c)
+			clientType := clientValue.Type()
+
+			for i := 0; i < clientType.NumMethod(); i++ {
+				method := clientType.Method(i)
+				if stringInSlice(method.Name, skipped) {
+					continue
+				}
+				args := make([]reflect.Value, 0, method.Type.NumIn()-1)
+				for j := 1; j < method.Type.NumIn(); j++ {
+					args = append(args, malformedResponseArg(method.Type.In(j)))
+				}
+
+				// Act & Assert
+				func() {
+					defer func() {
+						if r := recover(); r != nil {
+							t.Errorf("%s panicked: %v", method.Name, r)
+						}
+					}()
+					clientValue.Method(i).Call(args)
+				}()
+			}
+		})
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	// Arrange
+	c := NewClient(rawHTTPClient(malformedResponses["string"]), config)
+
+	// Act
+	_, err := c.Ping()
+
+	// Assert
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected a DecodeError, got %v", err)
+	}
+	if decodeErr.Method != "ping" || decodeErr.Expected != "boolean" {
+		t.Errorf("unexpected method or expected shape: %s %s", decodeErr.Method, decodeErr.Expected)
+	}
+	if !errors.Is(err, ErrMalformedResponse) {
+		t.Errorf("expected the error to match ErrMalformedResponse")
+	}
+	if !strings.Contains(err.Error(), fmt.Sprintf("%T", "")) {
+		t.Errorf("expected the received type in the message, got %q", err.Error())
+	}
+}
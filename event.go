@@ -16,19 +16,32 @@ var EMPTYEVENT = CobblerEvent{
 	ReadByWho: nil,
 }
 
-func unmarshalEvent(eventId string, data interface{}) *CobblerEvent {
-	eventData := data.([]interface{})
-	readByWho := make([]string, 0)
-	for _, user := range eventData[3].([]interface{}) {
-		readByWho = append(readByWho, user.(string))
+// unmarshalEvent converts the event array of the given method to a [CobblerEvent].
+func unmarshalEvent(method, eventId string, data interface{}) (*CobblerEvent, error) {
+	invalidEvent := &DecodeError{
+		Method:   method,
+		Expected: "array of [double, string, string, array of strings]",
+		Value:    data,
+	}
+	eventData, ok := data.([]interface{})
+	if !ok || len(eventData) < 4 {
+		return nil, invalidEvent
+	}
+	stateTime, errTime := convertToFloat(eventData[0])
+	name, okName := eventData[1].(string)
+	state, okState := eventData[2].(string)
+	rawReadByWho, okReadByWho := eventData[3].([]interface{})
+	readByWho, errReadByWho := convertToStringSlice(rawReadByWho)
+	if errTime != nil || !okName || !okState || !okReadByWho || errReadByWho != nil {
+		return nil, invalidEvent
 	}
 	return &CobblerEvent{
 		ID:        eventId,
-		StateTime: eventData[0].(float64),
-		Name:      eventData[1].(string),
-		State:     eventData[2].(string),
+		StateTime: stateTime,
+		Name:      name,
+		State:     state,
 		ReadByWho: readByWho,
-	}
+	}, nil
 }
 
 // GetEvents retrieves all events from the Cobbler server
@@ -38,17 +51,24 @@ func (c *Client) GetEvents(forUser string) ([]*CobblerEvent, error) {
 	if err != nil {
 		return nil, err
 	}
-	for key, event := range unmarshalledResult.(map[string]interface{}) {
-		eventObj := unmarshalEvent(key, event)
+	rawEvents, err := asMap("get_events", unmarshalledResult)
+	if err != nil {
+		return nil, err
+	}
+	for key, event := range rawEvents {
+		eventObj, err := unmarshalEvent("get_events", key, event)
+		if err != nil {
+			return nil, err
+		}
 		events = append(events, eventObj)
 	}
-	return events, err
+	return events, nil
 }
 
 // GetEventLog retrieves the logged messages for a given event id.
 func (c *Client) GetEventLog(eventId string) (string, error) {
 	res, err := c.Call("get_event_log", eventId)
-	return returnString(res, err)
+	return returnString("get_event_log", res, err)
 }
 
 // GetTaskStatus takes the event ID from Cobbler and returns its status.
@@ -57,6 +77,9 @@ func (c *Client) GetTaskStatus(eventId string) (CobblerEvent, error) {
 	if err != nil {
 		return EMPTYEVENT, err
 	}
-	eventObj := unmarshalEvent(eventId, unmarshalledResult)
-	return *eventObj, err
+	eventObj, err := unmarshalEvent("get_task_status", eventId, unmarshalledResult)
+	if err != nil {
+		return EMPTYEVENT, err
+	}
+	return *eventObj, nil
 }
@@ -0,0 +1,226 @@
+package cobblerclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kolo/xmlrpc"
+)
+
+// DefaultHealthCheckInterval is the default for [ClientConfig.HealthCheckInterval].
+const DefaultHealthCheckInterval = 30 * time.Second
+
+// primaryOnlyMethods are read-only methods that depend on the session of the primary server, thus they never fail
+// over to a replica.
+var primaryOnlyMethods = []string{"token_check", "get_user_from_token", "check_access", "check_access_no_fail"}
+
+// EndpointStatus is the health of a server as seen by the client.
+type EndpointStatus struct {
+	URL string
+	// Primary is set for [ClientConfig.URL], which receives all calls that may modify data.
+	Primary bool
+	Healthy bool
+	// LastError is the error that marked the endpoint as unhealthy. It is nil for healthy endpoints.
+	LastError error
+	// Checked is the time of the last failure or health check. It is zero if the endpoint never failed.
+	Checked time.Time
+}
+
+// endpoint is a single server and its health. Endpoints are shared between all copies of the client.
+type endpoint struct {
+	url     string
+	primary bool
+
+	mu      sync.Mutex
+	failure error
+	checked time.Time
+}
+
+// newEndpoints returns the primary endpoint followed by the replicas.
+func newEndpoints(config ClientConfig) []*endpoint {
+	endpoints := []*endpoint{{url: config.URL, primary: true}}
+	for _, url := range config.ReplicaURLs {
+		endpoints = append(endpoints, &endpoint{url: url})
+	}
+	return endpoints
+}
+
+// claim decides if the endpoint may be used now. An unhealthy endpoint is only used again after interval has passed
+// since its last check. In that case check is set and the caller has to verify the health of the endpoint before it is
+// used. The claim postpones the next check, thus concurrent callers don't check the same endpoint at once.
+func (e *endpoint) claim(interval time.Duration) (usable, check bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.failure == nil {
+		return true, false
+	}
+	if time.Since(e.checked) < interval {
+		return false, false
+	}
+	e.checked = time.Now()
+	return true, true
+}
+
+func (e *endpoint) markFailed(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failure = err
+	e.checked = time.Now()
+}
+
+func (e *endpoint) markHealthy() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.failure != nil {
+		e.failure = nil
+		e.checked = time.Now()
+	}
+}
+
+func (e *endpoint) status() EndpointStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return EndpointStatus{
+		URL:       e.url,
+		Primary:   e.primary,
+		Healthy:   e.failure == nil,
+		LastError: e.failure,
+		Checked:   e.checked,
+	}
+}
+
+// Endpoints returns the health of the primary server and all replicas as recorded by previous calls.
+func (c *Client) Endpoints() []EndpointStatus {
+	endpoints := c.endpointList()
+	statuses := make([]EndpointStatus, 0, len(endpoints))
+	for _, e := range endpoints {
+		statuses = append(statuses, e.status())
+	}
+	return statuses
+}
+
+// CheckEndpoints sends "ping" to the primary server and all replicas and records their health. Endpoints that answer
+// are used again immediately, even if the [ClientConfig.HealthCheckInterval] hasn't passed yet.
+func (c *Client) CheckEndpoints() []EndpointStatus {
+	ctx := c.Context()
+	endpoints := c.endpointList()
+	var wg sync.WaitGroup
+	for _, e := range endpoints {
+		wg.Add(1)
+		go func(e *endpoint) {
+			defer wg.Done()
+			c.checkEndpoint(ctx, e)
+		}(e)
+	}
+	wg.Wait()
+	return c.Endpoints()
+}
+
+// checkEndpoint pings a single endpoint and records the outcome.
+func (c *Client) checkEndpoint(ctx context.Context, e *endpoint) error {
+	body, err := c.roundTrip(ctx, e.url, pingRequest)
+	if err == nil {
+		var alive interface{}
+		alive, err = decodeResponse("ping", body)
+		if err == nil {
+			if ok, _ := alive.(bool); !ok {
+				err = errors.New("cobblerclient: ping was not answered with true")
+			}
+		}
+	}
+	if err != nil {
+		e.markFailed(err)
+		return err
+	}
+	e.markHealthy()
+	return nil
+}
+
+// pingRequest is the encoded "ping" call used for health checks.
+var pingRequest, _ = xmlrpc.EncodeMethodCall("ping")
+
+// endpointList returns the endpoints of the client. A client that wasn't created by [NewClient] only has the primary.
+func (c *Client) endpointList() []*endpoint {
+	if len(c.endpoints) == 0 {
+		return newEndpoints(c.config)
+	}
+	return c.endpoints
+}
+
+// failsOver checks if a call of method may be answered by a replica. Handles are only requested to modify an item,
+// thus the "get_*_handle" methods are sent to the primary like the modification itself.
+func failsOver(method string) bool {
+	return IsReadOnlyMethod(method) && !stringInSlice(method, primaryOnlyMethods) && !strings.HasSuffix(method, "_handle")
+}
+
+// onPrimary returns a shallow copy of the client whose calls are all sent to the primary server. It is used for the
+// reads that a modification depends on, like existence checks and modification times, because a replica may not have
+// caught up with the primary yet.
+func (c *Client) onPrimary() *Client {
+	c2 := *c
+	c2.primary = true
+	return &c2
+}
+
+func (c *Client) healthCheckInterval() time.Duration {
+	if c.config.HealthCheckInterval <= 0 {
+		return DefaultHealthCheckInterval
+	}
+	return c.config.HealthCheckInterval
+}
+
+// failover runs attempt against the endpoints that may answer a call of method and returns the URL of the endpoint
+// that succeeded. If all attempts failed, the URL is empty. Calls that may modify data and all calls of a client
+// returned by [Client.onPrimary] are always sent to the primary. Read-only calls try the primary and then the replicas
+// in the configured order, skipping the endpoints that failed recently. An unhealthy endpoint whose health check is
+// due is pinged first. If no endpoint is usable, the primary is tried anyway.
+func (c *Client) failover(ctx context.Context, method string, attempt func(url string) error) (string, error) {
+	endpoints := c.endpointList()
+	if len(endpoints) == 1 || c.primary || !failsOver(method) {
+		return c.attemptEndpoint(endpoints[0], attempt)
+	}
+
+	var lastErr error
+	for _, e := range endpoints {
+		usable, check := e.claim(c.healthCheckInterval())
+		if !usable {
+			continue
+		}
+		if check && c.checkEndpoint(ctx, e) != nil {
+			continue
+		}
+		url, err := c.attemptEndpoint(e, attempt)
+		if err == nil || ctx.Err() != nil || !isEndpointFailure(err) {
+			return url, err
+		}
+		lastErr = err
+	}
+	if lastErr != nil {
+		return "", lastErr
+	}
+	return c.attemptEndpoint(endpoints[0], attempt)
+}
+
+// attemptEndpoint runs attempt against a single endpoint and records its health. The URL of the endpoint is only
+// returned if the attempt succeeded.
+func (c *Client) attemptEndpoint(e *endpoint, attempt func(url string) error) (string, error) {
+	err := attempt(e.url)
+	if isEndpointFailure(err) {
+		e.markFailed(err)
+		return "", err
+	}
+	if err != nil {
+		return "", err
+	}
+	e.markHealthy()
+	return e.url, nil
+}
+
+// isEndpointFailure checks if an error of the transport means that the server couldn't answer. The errors of the
+// context of the call say nothing about the health of the server.
+func isEndpointFailure(err error) bool {
+	return err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,223 @@
+package cobblerclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"reflect"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/cobbler/cobblerclient/cobblertest"
+)
+
+const (
+	primaryURL = "http://cobbler.example.com/cobbler_api"
+	replicaURL = "http://cobbler-replica.example.com/cobbler_api"
+)
+
+// clusterHTTPClient fakes a primary server and its replicas. Every request is recorded as "<url> <method>". Servers
+// that are down refuse the connection.
+type clusterHTTPClient struct {
+	mu       sync.Mutex
+	down     map[string]bool
+	requests []string
+}
+
+func newClusterHTTPClient(down ...string) *clusterHTTPClient {
+	h := &clusterHTTPClient{down: make(map[string]bool)}
+	for _, url := range down {
+		h.down[url] = true
+	}
+	return h
+}
+
+func (h *clusterHTTPClient) Post(uri, bodyType string, req io.Reader) (*http.Response, error) {
+	call, err := cobblertest.DecodeCall(req)
+	if err != nil {
+		return nil, err
+	}
+	h.mu.Lock()
+	h.requests = append(h.requests, uri+" "+call.Method)
+	down := h.down[uri]
+	h.mu.Unlock()
+	if down {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	}
+
+	var value string
+	switch call.Method {
+	case "get_item_names":
+		value = fmt.Sprintf("<array><data><value><string>%s</string></value></data></array>", uri)
+	case "get_systems":
+		value = "<array><data></data></array>"
+	default:
+		value = "<boolean>1</boolean>"
+	}
+	body := fmt.Sprintf("<?xml version='1.0'?>\n<methodResponse>%s</methodResponse>", xmlrpcParams(value))
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(body))}, nil
+}
+
+func (h *clusterHTTPClient) setDown(url string, down bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.down[url] = down
+}
+
+func (h *clusterHTTPClient) takeRequests() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	requests := h.requests
+	h.requests = nil
+	return requests
+}
+
+// clusterClient returns a client for the fake cluster without retries. The endpoint of every call is recorded.
+func clusterClient(hc *clusterHTTPClient, interval time.Duration, endpoints *[]string) Client {
+	cfg := config
+	cfg.URL = primaryURL
+	cfg.ReplicaURLs = []string{replicaURL}
+	cfg.HealthCheckInterval = interval
+	cfg.RetryPolicy = RetryPolicy{MaxAttempts: 1}
+	cfg.Interceptors = []Interceptor{func(ctx context.Context, call *RPCCall, next Invoker) (interface{}, error) {
+		result, err := next(ctx, call)
+		*endpoints = append(*endpoints, call.Endpoint)
+		return result, err
+	}}
+	c := NewClient(hc, cfg)
+	c.SetToken("securetoken99")
+	return c
+}
+
+func TestFailoverReadToReplica(t *testing.T) {
+	// Arrange
+	hc := newClusterHTTPClient(primaryURL)
+	var endpoints []string
+	c := clusterClient(hc, time.Hour, &endpoints)
+
+	// Act
+	names, namesErr := c.GetItemNames("system")
+	_, systemsErr := c.GetSystems()
+
+	// Assert
+	FailOnError(t, namesErr)
+	FailOnError(t, systemsErr)
+	if len(names) != 1 || names[0] != replicaURL {
+		t.Errorf("expected the answer of the replica, got %v", names)
+	}
+	expectedRequests := []string{primaryURL + " get_item_names", replicaURL + " get_item_names",
+		replicaURL + " get_systems"}
+	if requests := hc.takeRequests(); !reflect.DeepEqual(requests, expectedRequests) {
+		t.Errorf("expected the primary to be skipped after it failed, got %v", requests)
+	}
+	if !reflect.DeepEqual(endpoints, []string{replicaURL, replicaURL}) {
+		t.Errorf("expected the replica to be recorded as endpoint, got %v", endpoints)
+	}
+	statuses := c.Endpoints()
+	if len(statuses) != 2 || !statuses[0].Primary || statuses[0].Healthy || statuses[0].LastError == nil ||
+		!statuses[1].Healthy {
+		t.Errorf("expected an unhealthy primary and a healthy replica, got %+v", statuses)
+	}
+}
+
+func TestFailoverWritesPinnedToPrimary(t *testing.T) {
+	// Arrange
+	hc := newClusterHTTPClient(primaryURL)
+	var endpoints []string
+	c := clusterClient(hc, time.Hour, &endpoints)
+
+	// Act
+	_, modifyErr := c.Call("modify_system", "system::1", "comment", "test", c.Token())
+	_, tokenErr := c.Call("token_check", c.Token())
+
+	// Assert
+	if modifyErr == nil || tokenErr == nil {
+		t.Errorf("expected the calls to fail while the primary is down")
+	}
+	expectedRequests := []string{primaryURL + " modify_system", primaryURL + " token_check"}
+	if requests := hc.takeRequests(); !reflect.DeepEqual(requests, expectedRequests) {
+		t.Errorf("expected only requests to the primary, got %v", requests)
+	}
+	if !reflect.DeepEqual(endpoints, []string{"", ""}) {
+		t.Errorf("expected no endpoint for failed calls, got %v", endpoints)
+	}
+}
+
+func TestFailoverWriteSequencePinnedToPrimary(t *testing.T) {
+	// Arrange
+	hc := newClusterHTTPClient(primaryURL)
+	var endpoints []string
+	c := clusterClient(hc, time.Hour, &endpoints)
+	_, err := c.GetItemNames("system")
+	FailOnError(t, err)
+	hc.setDown(primaryURL, false)
+	hc.takeRequests()
+
+	// Act
+	_, readErr := c.GetItemNames("system")
+	_, handleErr := c.Call("get_system_handle", "www1", c.Token())
+	c.endpoints[0].markFailed(errors.New("outdated"))
+	_, pinnedErr := c.onPrimary().GetItemNames("system")
+
+	// Assert
+	FailOnError(t, readErr)
+	FailOnError(t, handleErr)
+	FailOnError(t, pinnedErr)
+	expectedRequests := []string{replicaURL + " get_item_names", primaryURL + " get_system_handle",
+		primaryURL + " get_item_names"}
+	if requests := hc.takeRequests(); !reflect.DeepEqual(requests, expectedRequests) {
+		t.Errorf("expected only the reads outside of a write sequence to use the replica, got %v", requests)
+	}
+}
+
+func TestFailoverHealthCheck(t *testing.T) {
+	// Arrange
+	hc := newClusterHTTPClient(primaryURL)
+	var endpoints []string
+	c := clusterClient(hc, time.Millisecond, &endpoints)
+	_, err := c.GetItemNames("system")
+	FailOnError(t, err)
+	hc.takeRequests()
+	hc.setDown(primaryURL, false)
+	time.Sleep(2 * time.Millisecond)
+
+	// Act
+	_, err = c.GetItemNames("system")
+
+	// Assert
+	FailOnError(t, err)
+	expectedRequests := []string{primaryURL + " ping", primaryURL + " get_item_names"}
+	if requests := hc.takeRequests(); !reflect.DeepEqual(requests, expectedRequests) {
+		t.Errorf("expected the primary to be pinged before it is used again, got %v", requests)
+	}
+	if endpoints[len(endpoints)-1] != primaryURL {
+		t.Errorf("expected the primary to answer, got %s", endpoints[len(endpoints)-1])
+	}
+	if statuses := c.Endpoints(); !statuses[0].Healthy {
+		t.Errorf("expected the primary to be healthy again, got %+v", statuses[0])
+	}
+}
+
+func TestCheckEndpoints(t *testing.T) {
+	// Arrange
+	hc := newClusterHTTPClient(replicaURL)
+	var endpoints []string
+	c := clusterClient(hc, time.Hour, &endpoints)
+
+	// Act
+	statuses := c.CheckEndpoints()
+
+	// Assert
+	if len(statuses) != 2 || !statuses[0].Healthy || statuses[1].Healthy || statuses[1].URL != replicaURL {
+		t.Errorf("expected a healthy primary and an unhealthy replica, got %+v", statuses)
+	}
+	if requests := hc.takeRequests(); len(requests) != 2 {
+		t.Errorf("expected a ping per endpoint, got %v", requests)
+	}
+}
@@ -1,10 +1,6 @@
 package cobblerclient
 
-import (
-	"fmt"
-	"reflect"
-	"time"
-)
+import "time"
 
 // File is a created file.
 // Get the fields from cobbler/items/file.py
@@ -21,217 +17,94 @@ func NewFile() File {
 	}
 }
 
-func convertRawFile(name string, xmlrpcResult interface{}) (*File, error) {
-	var file File
-
-	if xmlrpcResult == "~" {
-		return nil, fmt.Errorf("profile %s not found", name)
-	}
-
-	decodeResult, err := decodeCobblerItem(xmlrpcResult, &file)
-	if err != nil {
-		return nil, err
-	}
-
-	// Now clean the Value structs
-	decodedFile := decodeResult.(*File)
-	err = sanitizeValueMapStruct(&decodedFile.KernelOptions)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedFile.KernelOptionsPost)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedFile.AutoinstallMeta)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedFile.FetchableFiles)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedFile.BootFiles)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedFile.TemplateFiles)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedFile.MgmtParameters)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueSliceStruct(&decodedFile.Owners)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueSliceStruct(&decodedFile.MgmtClasses)
-	return decodedFile, nil
-}
-
-func convertRawFilesList(xmlrpcResult interface{}) ([]*File, error) {
-	var files []*File
-
-	for _, d := range xmlrpcResult.([]interface{}) {
-		file, err := convertRawFile("unknown", d)
-		if err != nil {
-			return nil, err
-		}
-		file.Meta = ItemMeta{
-			IsFlattened: false,
-			IsResolved:  false,
-		}
-		files = append(files, file)
-	}
-
-	return files, nil
+// files returns the service for files.
+func (c *Client) files() *ItemService[File] {
+	return NewItemService[File](c, "file")
 }
 
-// GetFiles returns a list of all files.
+// GetFiles returns all files in Cobbler.
 func (c *Client) GetFiles() ([]*File, error) {
-	result, err := c.Call("get_files", "-1", c.Token)
-	if err != nil {
-		return nil, err
-	}
+	return c.files().GetAll()
+}
 
-	return convertRawFilesList(result)
+// EachFile calls fn for every file in Cobbler. The files are decoded one by one while the response is read, thus
+// only a single file is held in memory at a time. The iteration stops at the first error returned by fn, which is
+// returned unchanged.
+func (c *Client) EachFile(fn func(*File) error) error {
+	return c.files().Each(fn)
 }
 
 // GetFile returns a single file obtained by its name.
 func (c *Client) GetFile(name string, flattened, resolved bool) (*File, error) {
-	result, err := c.getConcreteItem("get_file", name, flattened, resolved)
-	if err != nil {
-		return nil, err
-	}
-
-	file, err := convertRawFile(name, result)
-	if err != nil {
-		return nil, err
-	}
-	file.Meta = ItemMeta{
-		IsFlattened: flattened,
-		IsResolved:  resolved,
-	}
-	return file, nil
+	return c.files().Get(name, flattened, resolved)
 }
 
-// CreateFile creates a single file.
+// CreateFile creates a file.
 func (c *Client) CreateFile(file File) (*File, error) {
-	// Make sure a file with the same name does not already exist
-	if _, err := c.GetFile(file.Name, false, false); err == nil {
-		return nil, fmt.Errorf("a File with the name %s already exists", file.Name)
-	}
-
-	result, err := c.Call("new_file", c.Token)
-	if err != nil {
-		return nil, err
-	}
-	newID := result.(string)
-
-	item := reflect.ValueOf(&file).Elem()
-	if err := c.updateCobblerFields("file", item, newID); err != nil {
-		return nil, err
-	}
-
-	if err = c.SaveFile(newID, "new"); err != nil {
-		return nil, err
-	}
-
-	return c.GetFile(file.Name, false, false)
+	return c.files().Create(file)
 }
 
 // UpdateFile updates a single file.
 func (c *Client) UpdateFile(file *File) error {
-	item := reflect.ValueOf(file).Elem()
-	id, err := c.GetItemHandle("file", file.Name)
-	if err != nil {
-		return err
-	}
+	return c.files().Update(file)
+}
 
-	if err := c.updateCobblerFields("file", item, id); err != nil {
-		return err
-	}
+// EditFile starts an edit session for the file with the given name. All modifications are saved at once.
+func (c *Client) EditFile(name string) (*EditSession[File], error) {
+	return c.files().Edit(name)
+}
 
-	if err = c.SaveFile(id, "bypass"); err != nil {
-		return err
-	}
+// SaveFile saves all changes performed via XML-RPC to disk on the server side.
+func (c *Client) SaveFile(objectId, editmode string) error {
+	return c.files().Save(objectId, editmode)
+}
 
-	return nil
+// CopyFile duplicates a file on the server with a new name.
+func (c *Client) CopyFile(objectId, newName string) error {
+	return c.files().Copy(objectId, newName)
 }
 
-// DeleteFile deletes a single File by its name.
+// RenameFile renames a file with a given object id.
+func (c *Client) RenameFile(objectId, newName string) error {
+	return c.files().Rename(objectId, newName)
+}
+
+// DeleteFile deletes a single file by its name.
 func (c *Client) DeleteFile(name string) error {
-	return c.DeleteFileRecursive(name, false)
+	return c.files().Delete(name, false)
 }
 
-// DeleteFileRecursive deletes a single File by its name with the option to do so recursively.
+// DeleteFileRecursive deletes a single file by its name with the option to do so recursively.
 func (c *Client) DeleteFileRecursive(name string, recursive bool) error {
-	_, err := c.Call("remove_file", name, c.Token, recursive)
-	return err
+	return c.files().Delete(name, recursive)
 }
 
-// ListFileNames returns a list of all files names currently available in Cobbler.
+// ListFileNames returns a list of all file names currently available in Cobbler.
 func (c *Client) ListFileNames() ([]string, error) {
-	return c.GetItemNames("file")
+	return c.files().ListNames()
 }
 
 // FindFile searches for one or more files by any of its attributes.
 func (c *Client) FindFile(criteria map[string]interface{}) ([]*File, error) {
-	result, err := c.Call("find_file", criteria, true, c.Token)
-	if err != nil {
-		return nil, err
-	}
-
-	return convertRawFilesList(result)
+	return c.files().Find(criteria)
 }
 
 // FindFileNames searches for one or more files by any of its attributes.
 func (c *Client) FindFileNames(criteria map[string]interface{}) ([]string, error) {
-	resultUnmarshalled, err := c.Call("find_file", criteria, false, c.Token)
-	return returnStringSlice(resultUnmarshalled, err)
-}
-
-// GetFileHandle gets the internal ID of a Cobbler item.
-func (c *Client) GetFileHandle(name string) (string, error) {
-	res, err := c.Call("get_file_handle", name, c.Token)
-	return returnString(res, err)
+	return c.files().FindNames(criteria)
 }
 
-// CopyFile duplicates a file on the server with a new name.
-func (c *Client) CopyFile(objectId, newName string) error {
-	_, err := c.Call("copy_file", objectId, newName, c.Token)
-	return err
-}
-
-// GetFilesSince returns all files which were created after the specified date.
+// GetFilesSince returns all files which were modified after the specified date.
 func (c *Client) GetFilesSince(mtime time.Time) ([]*File, error) {
-	result, err := c.Call("get_files_since", float64(mtime.Unix()))
-	if err != nil {
-		return nil, err
-	}
+	return c.files().Since(mtime)
+}
 
-	return convertRawFilesList(result)
+// GetFileHandle gets the internal ID of a Cobbler item.
+func (c *Client) GetFileHandle(name string) (string, error) {
+	return c.files().Handle(name)
 }
 
 // GetFileAsRendered returns the datastructure after it has passed through Cobblers inheritance structure.
 func (c *Client) GetFileAsRendered(name string) (map[string]interface{}, error) {
-	result, err := c.Call("get_file_as_rendered", name, c.Token)
-	if err != nil {
-		return nil, err
-	}
-	return result.(map[string]interface{}), err
-}
-
-// SaveFile saves all changes performed via XML-RPC to disk on the server side.
-func (c *Client) SaveFile(objectId, editmode string) error {
-	_, err := c.Call("save_file", objectId, c.Token, editmode)
-	return err
-}
-
-// RenameFile renames a file with a given object id.
-func (c *Client) RenameFile(objectId, newName string) error {
-	_, err := c.Call("rename_file", objectId, newName, c.Token)
-	return err
+	return c.files().AsRendered(name)
 }
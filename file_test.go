@@ -39,7 +39,7 @@ func TestGetFiles(t *testing.T) {
 func TestGetFile(t *testing.T) {
 	// Arrange
 	c := createStubHTTPClientSingle(t, "get-file")
-	c.CachedVersion = CobblerVersion{3, 3, 2}
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
 
 	// Act
 	file, err := c.GetFile("testfile", false, false)
@@ -8,4 +8,5 @@ require (
 	github.com/go-viper/mapstructure/v2 v2.0.0
 	github.com/kolo/xmlrpc v0.0.0-20190909154602-56d5ec7c422e
 	golang.org/x/text v0.3.8 // indirect
+	gopkg.in/yaml.v3 v3.0.1
 )
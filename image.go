@@ -1,10 +1,6 @@
 package cobblerclient
 
-import (
-	"fmt"
-	"reflect"
-	"time"
-)
+import "time"
 
 type Architecture int64
 
@@ -168,8 +164,6 @@ type Image struct {
 	VirtRam              Value[int]     `mapstructure:"virt_ram"`
 	VirtType             string         `mapstructure:"virt_type"`
 	SupportedBootLoaders []string       `mapstructure:"supported_boot_loaders" cobbler:"noupdate"`
-
-	Client
 }
 
 func NewImage() Image {
@@ -192,222 +186,100 @@ func NewImage() Image {
 	}
 }
 
-func convertRawImage(name string, xmlrpcResult interface{}) (*Image, error) {
-	var image Image
-
-	if xmlrpcResult == "~" {
-		return nil, fmt.Errorf("profile %s not found", name)
-	}
-
-	decodeResult, err := decodeCobblerItem(xmlrpcResult, &image)
-	if err != nil {
-		return nil, err
-	}
-
-	// Now clean the Value structs
-	decodedImage := decodeResult.(*Image)
-	err = sanitizeValueMapStruct(&decodedImage.KernelOptions)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedImage.KernelOptionsPost)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedImage.AutoinstallMeta)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedImage.FetchableFiles)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedImage.BootFiles)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedImage.TemplateFiles)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedImage.MgmtParameters)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueSliceStruct(&decodedImage.Owners)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueSliceStruct(&decodedImage.MgmtClasses)
-	return decodedImage, nil
-}
-
-func convertRawImagesList(xmlrpcResult interface{}) ([]*Image, error) {
-	var images []*Image
-
-	for _, d := range xmlrpcResult.([]interface{}) {
-		image, err := convertRawImage("unknown", d)
-		if err != nil {
-			return nil, err
-		}
-		image.Meta = ItemMeta{
-			IsFlattened: false,
-			IsResolved:  false,
-		}
-		images = append(images, image)
-	}
-
-	return images, nil
+// images returns the service for images.
+func (c *Client) images() *ItemService[Image] {
+	return NewItemService[Image](c, "image")
 }
 
 // GetImages returns all images in Cobbler.
 func (c *Client) GetImages() ([]*Image, error) {
-	result, err := c.Call("get_images", "-1", c.Token)
-	if err != nil {
-		return nil, err
-	}
-
-	return convertRawImagesList(result)
+	return c.images().GetAll()
 }
 
-// ListImageNames returns a list of all known image names.
-func (c *Client) ListImageNames() ([]string, error) {
-	return c.GetItemNames("image")
+// EachImage calls fn for every image in Cobbler. The images are decoded one by one while the response is read, thus
+// only a single image is held in memory at a time. The iteration stops at the first error returned by fn, which is
+// returned unchanged.
+func (c *Client) EachImage(fn func(*Image) error) error {
+	return c.images().Each(fn)
 }
 
 // GetImage returns a single image obtained by its name.
 func (c *Client) GetImage(name string, flattened, resolved bool) (*Image, error) {
-	result, err := c.getConcreteItem("get_image", name, flattened, resolved)
-	if err != nil {
-		return nil, err
-	}
-
-	image, err := convertRawImage(name, result)
-	if err != nil {
-		return nil, err
-	}
-	image.Meta = ItemMeta{
-		IsFlattened: flattened,
-		IsResolved:  resolved,
-	}
-	return image, nil
+	return c.images().Get(name, flattened, resolved)
 }
 
 // CreateImage creates an image.
 func (c *Client) CreateImage(image Image) (*Image, error) {
-	// To create an image via the Cobbler API, first call new_image to obtain an ID
-	result, err := c.Call("new_image", c.Token)
-	if err != nil {
-		return nil, err
-	}
-	newID := result.(string)
-	// Set the value of all fields
-	item := reflect.ValueOf(&image).Elem()
-	if err := c.updateCobblerFields("image", item, newID); err != nil {
-		return nil, err
-	}
-
-	// Save the final image
-	if err = c.SaveImage(newID, "new"); err != nil {
-		return nil, err
-	}
-
-	// Return a clean copy of the image
-	return c.GetImage(image.Name, false, false)
+	return c.images().Create(image)
 }
 
 // UpdateImage updates a single image.
 func (c *Client) UpdateImage(image *Image) error {
-	item := reflect.ValueOf(image).Elem()
-	id, err := c.GetItemHandle("image", image.Name)
-	if err != nil {
-		return err
-	}
-
-	if err := c.updateCobblerFields("image", item, id); err != nil {
-		return err
-	}
-
-	// Save the final image
-	if err := c.SaveImage(id, "bypass"); err != nil {
-		return err
-	}
+	return c.images().Update(image)
+}
 
-	return nil
+// EditImage starts an edit session for the image with the given name. All modifications are saved at once.
+func (c *Client) EditImage(name string) (*EditSession[Image], error) {
+	return c.images().Edit(name)
 }
 
-// DeleteImage deletes a single Image by its name.
-func (c *Client) DeleteImage(name string) error {
-	return c.DeleteImageRecursive(name, false)
+// SaveImage saves all changes performed via XML-RPC to disk on the server side.
+func (c *Client) SaveImage(objectId, editmode string) error {
+	return c.images().Save(objectId, editmode)
 }
 
-// DeleteImageRecursive deletes a single Image by its name with the option to do so recursively.
-func (c *Client) DeleteImageRecursive(name string, recursive bool) error {
-	_, err := c.Call("remove_image", name, c.Token, recursive)
-	return err
+// CopyImage duplicates an image on the server with a new name.
+func (c *Client) CopyImage(objectId, newName string) error {
+	return c.images().Copy(objectId, newName)
 }
 
-// FindImage searches for one or more images by any of its attributes.
-func (c *Client) FindImage(criteria map[string]interface{}) ([]*Image, error) {
-	result, err := c.Call("find_image", criteria, true, c.Token)
-	if err != nil {
-		return nil, err
-	}
+// RenameImage renames an image with a given object id.
+func (c *Client) RenameImage(objectId, newName string) error {
+	return c.images().Rename(objectId, newName)
+}
 
-	return convertRawImagesList(result)
+// DeleteImage deletes a single image by its name.
+func (c *Client) DeleteImage(name string) error {
+	return c.images().Delete(name, false)
 }
 
-// FindImageNames searches for one or more distros by any of its attributes.
-func (c *Client) FindImageNames(criteria map[string]interface{}) ([]string, error) {
-	resultUnmarshalled, err := c.Call("find_image", criteria, false, c.Token)
-	return returnStringSlice(resultUnmarshalled, err)
+// DeleteImageRecursive deletes a single image by its name with the option to do so recursively.
+func (c *Client) DeleteImageRecursive(name string, recursive bool) error {
+	return c.images().Delete(name, recursive)
 }
 
-// GetImageHandle gets the internal ID of a Cobbler item.
-func (c *Client) GetImageHandle(name string) (string, error) {
-	res, err := c.Call("get_image_handle", name, c.Token)
-	return returnString(res, err)
+// ListImageNames returns a list of all image names currently available in Cobbler.
+func (c *Client) ListImageNames() ([]string, error) {
+	return c.images().ListNames()
 }
 
-// CopyImage duplicates an image on the server with a new name.
-func (c *Client) CopyImage(objectId, newName string) error {
-	_, err := c.Call("copy_image", objectId, newName, c.Token)
-	return err
+// FindImage searches for one or more images by any of its attributes.
+func (c *Client) FindImage(criteria map[string]interface{}) ([]*Image, error) {
+	return c.images().Find(criteria)
 }
 
-// RenameImage renames an image with a given object id.
-func (c *Client) RenameImage(objectId, newName string) error {
-	_, err := c.Call("rename_image", objectId, newName, c.Token)
-	return err
+// FindImageNames searches for one or more images by any of its attributes.
+func (c *Client) FindImageNames(criteria map[string]interface{}) ([]string, error) {
+	return c.images().FindNames(criteria)
 }
 
-// GetImagesSince returns all images which were created after the specified date.
+// GetImagesSince returns all images which were modified after the specified date.
 func (c *Client) GetImagesSince(mtime time.Time) ([]*Image, error) {
-	result, err := c.Call("get_images_since", float64(mtime.Unix()))
-	if err != nil {
-		return nil, err
-	}
+	return c.images().Since(mtime)
+}
 
-	return convertRawImagesList(result)
+// GetImageHandle gets the internal ID of a Cobbler item.
+func (c *Client) GetImageHandle(name string) (string, error) {
+	return c.images().Handle(name)
 }
 
 // GetImageAsRendered returns the datastructure after it has passed through Cobblers inheritance structure.
 func (c *Client) GetImageAsRendered(name string) (map[string]interface{}, error) {
-	result, err := c.Call("get_image_as_rendered", name, c.Token)
-	if err != nil {
-		return nil, err
-	}
-	return result.(map[string]interface{}), err
-}
-
-// SaveImage saves all changes performed via XML-RPC to disk on the server side.
-func (c *Client) SaveImage(objectId, editmode string) error {
-	_, err := c.Call("save_image", objectId, c.Token, editmode)
-	return err
+	return c.images().AsRendered(name)
 }
 
 // GetValidImageBootLoaders retrieves the list of bootloaders that can be assigned to an image.
 func (c *Client) GetValidImageBootLoaders(imageName string) ([]string, error) {
-	resultUnmarshalled, err := c.Call("get_valid_image_boot_loaders", imageName, c.Token)
-	return returnStringSlice(resultUnmarshalled, err)
+	resultUnmarshalled, err := c.Call("get_valid_image_boot_loaders", imageName, c.Token())
+	return returnStringSlice("get_valid_image_boot_loaders", resultUnmarshalled, err)
 }
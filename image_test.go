@@ -39,7 +39,7 @@ func TestGetImages(t *testing.T) {
 func TestGetImage(t *testing.T) {
 	// Arrange
 	c := createStubHTTPClientSingle(t, "get-image")
-	c.CachedVersion = CobblerVersion{3, 3, 2}
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
 
 	// Act
 	image, err := c.GetImage("testimage", false, false)
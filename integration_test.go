@@ -0,0 +1,365 @@
+package cobblerclient_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cobbler "github.com/cobbler/cobblerclient"
+	"github.com/cobbler/cobblerclient/cobblerfake"
+)
+
+// fakeClient returns a logged in client for a new fake server. The server is stopped when the test finished.
+func fakeClient(t *testing.T, config cobbler.ClientConfig) (cobbler.Client, *cobblerfake.Server) {
+	t.Helper()
+	fake := cobblerfake.NewServer()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	config.URL = server.URL + "/cobbler_api"
+	config.Username = cobblerfake.DefaultUser
+	config.Password = cobblerfake.DefaultPassword
+	c := cobbler.NewClient(http.DefaultClient, config)
+	if _, err := c.Login(); err != nil {
+		t.Fatal(err)
+	}
+	return c, fake
+}
+
+// createSystem creates a distro, a profile and a system with a single interface.
+func createSystem(t *testing.T, c cobbler.Client) *cobbler.System {
+	t.Helper()
+	distro := cobbler.NewDistro()
+	distro.Name = "centos7-x86_64"
+	distro.Kernel = "/var/lib/cobbler/distro_mirror/centos7/vmlinuz"
+	distro.Initrd = "/var/lib/cobbler/distro_mirror/centos7/initrd.img"
+	distro.Breed = "redhat"
+	distro.KernelOptions.Data = map[string]interface{}{"console": "ttyS0"}
+	if _, err := c.CreateDistro(distro); err != nil {
+		t.Fatal(err)
+	}
+	profile := cobbler.NewProfile()
+	profile.Name = "centos7-x86_64"
+	profile.Distro = distro.Name
+	if _, err := c.CreateProfile(profile); err != nil {
+		t.Fatal(err)
+	}
+	system := cobbler.NewSystem()
+	system.Name = "test"
+	system.Profile = profile.Name
+	system.Hostname = "test.example.org"
+	system.Interfaces = cobbler.Interfaces{"eth0": {MACAddress: "aa:bb:cc:dd:ee:ff", IPAddress: "10.0.0.2"}}
+	created, err := c.CreateSystem(system)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return created
+}
+
+func TestIntegrationSystemLifecycle(t *testing.T) {
+	// Arrange
+	c, _ := fakeClient(t, cobbler.ClientConfig{})
+	system := createSystem(t, c)
+
+	// Act
+	system.Comment = "updated"
+	errUpdate := c.UpdateSystem(system)
+	handle, errHandle := c.GetSystemHandle("test")
+	errCopy := c.CopySystem(handle, "test-copy")
+	errRename := c.RenameSystem(handle, "renamed")
+	names, errNames := c.ListSystemNames()
+	errDelete := c.DeleteSystem("renamed")
+	_, errGet := c.GetSystem("renamed", false, false)
+
+	// Assert
+	for _, err := range []error{errUpdate, errHandle, errCopy, errRename, errNames, errDelete} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if system.Interfaces["eth0"].MACAddress != "aa:bb:cc:dd:ee:ff" || system.Interfaces["eth0"].IPAddress != "10.0.0.2" {
+		t.Errorf("expected the interface to be stored, got %+v", system.Interfaces)
+	}
+	if len(names) != 2 || names[0] != "renamed" || names[1] != "test-copy" {
+		t.Errorf("expected the renamed system and its copy, got %v", names)
+	}
+	if !errors.Is(errGet, cobbler.ErrNotFound) {
+		t.Errorf("expected the deleted system not to be found, got %v", errGet)
+	}
+}
+
+func TestIntegrationUpdateSystem(t *testing.T) {
+	// Arrange
+	c, _ := fakeClient(t, cobbler.ClientConfig{BatchUpdates: true})
+	system := createSystem(t, c)
+	system.Comment = "updated"
+	system.Interfaces["eth1"] = cobbler.Interface{MACAddress: "aa:bb:cc:dd:ee:00"}
+
+	// Act
+	err := c.UpdateSystem(system)
+	updated, errGet := c.GetSystem("test", false, false)
+
+	// Assert
+	FailOnError(t, err)
+	FailOnError(t, errGet)
+	if updated.Comment != "updated" || updated.Interfaces["eth1"].MACAddress != "aa:bb:cc:dd:ee:00" {
+		t.Errorf("expected the update to be visible, got %+v", updated)
+	}
+	if updated.MTime <= updated.CTime {
+		t.Errorf("expected the modification time to move forward")
+	}
+}
+
+func TestIntegrationUpdateKeepsConcurrentChanges(t *testing.T) {
+	// Arrange
+	c, _ := fakeClient(t, cobbler.ClientConfig{})
+	createSystem(t, c)
+	first, errFirst := c.GetSystem("test", false, false)
+	second, errSecond := c.GetSystem("test", false, false)
+	FailOnError(t, errFirst)
+	FailOnError(t, errSecond)
+	first.Comment = "updated"
+	second.Hostname = "renamed.example.org"
+	delete(second.Interfaces, "eth0")
+
+	// Act
+	errUpdateFirst := c.UpdateSystem(first)
+	errUpdateSecond := c.UpdateSystem(second)
+	updated, errGet := c.GetSystem("test", false, false)
+
+	// Assert
+	FailOnError(t, errUpdateFirst)
+	FailOnError(t, errUpdateSecond)
+	FailOnError(t, errGet)
+	if updated.Comment != "updated" || updated.Hostname != "renamed.example.org" {
+		t.Errorf("expected both updates to be kept, got %q and %q", updated.Comment, updated.Hostname)
+	}
+	if len(updated.Interfaces) != 0 {
+		t.Errorf("expected the removed interface to be deleted, got %+v", updated.Interfaces)
+	}
+}
+
+func TestIntegrationEditSystem(t *testing.T) {
+	// Arrange
+	c, _ := fakeClient(t, cobbler.ClientConfig{})
+	createSystem(t, c)
+	session, err := c.EditSystem("test")
+	FailOnError(t, err)
+	system := session.Item()
+	system.Comment = "edited"
+	system.KernelOptions.IsInherited = false
+	system.KernelOptions.Data["console"] = "ttyS1"
+	delete(system.Interfaces, "eth0")
+	system.Interfaces["eth1"] = cobbler.Interface{MACAddress: "aa:bb:cc:dd:ee:00"}
+
+	// Act
+	errSave := session.Save(cobbler.EditModeBypass)
+	saved, errGet := c.GetSystem("test", false, false)
+
+	// Assert
+	FailOnError(t, errSave)
+	FailOnError(t, errGet)
+	if saved.Comment != "edited" || saved.KernelOptions.Data["console"] != "ttyS1" {
+		t.Errorf("expected the attributes to be saved, got %+v", saved)
+	}
+	if _, ok := saved.Interfaces["eth1"]; !ok || len(saved.Interfaces) != 1 {
+		t.Errorf("expected only eth1, got %+v", saved.Interfaces)
+	}
+}
+
+func TestIntegrationUpdateConflict(t *testing.T) {
+	// Arrange
+	c, _ := fakeClient(t, cobbler.ClientConfig{ConflictCheck: true})
+	createSystem(t, c)
+	stale, err := c.GetProfile("centos7-x86_64", false, false)
+	FailOnError(t, err)
+	current, err := c.GetProfile("centos7-x86_64", false, false)
+	FailOnError(t, err)
+	current.Comment = "first"
+	FailOnError(t, c.UpdateProfile(current))
+	stale.Comment = "second"
+
+	// Act
+	errConflict := c.UpdateProfile(stale)
+	errRetry := cobbler.RetryOnConflict(3, func() error {
+		profile, err := c.GetProfile("centos7-x86_64", false, false)
+		if err != nil {
+			return err
+		}
+		profile.Comment += ", second"
+		return c.UpdateProfile(profile)
+	})
+	updated, errGet := c.GetProfile("centos7-x86_64", false, false)
+
+	// Assert
+	if !errors.Is(errConflict, cobbler.ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", errConflict)
+	}
+	FailOnError(t, errRetry)
+	FailOnError(t, errGet)
+	if updated.Comment != "first, second" {
+		t.Errorf("expected both comments, got %q", updated.Comment)
+	}
+}
+
+func TestIntegrationResolvedSystem(t *testing.T) {
+	// Arrange
+	c, _ := fakeClient(t, cobbler.ClientConfig{})
+	createSystem(t, c)
+
+	// Act
+	raw, errRaw := c.GetSystem("test", false, false)
+	resolved, errResolved := c.GetSystem("test", false, true)
+
+	// Assert
+	FailOnError(t, errRaw)
+	FailOnError(t, errResolved)
+	if !raw.KernelOptions.IsInherited {
+		t.Errorf("expected the kernel options of the system to be inherited")
+	}
+	if resolved.KernelOptions.Data["console"] != "ttyS0" {
+		t.Errorf("expected the kernel options of the distro, got %+v", resolved.KernelOptions)
+	}
+	if resolved.Server != "127.0.0.1" {
+		t.Errorf("expected the server of the settings, got %s", resolved.Server)
+	}
+}
+
+func TestIntegrationFindSystem(t *testing.T) {
+	// Arrange
+	c, _ := fakeClient(t, cobbler.ClientConfig{})
+	createSystem(t, c)
+
+	// Act
+	byMAC, errMAC := c.FindSystemNames(map[string]interface{}{"mac_address": "aa:bb:cc:dd:ee:ff"})
+	byPattern, errPattern := c.FindSystem(map[string]interface{}{"hostname": "*.example.org"})
+	none, errNone := c.FindSystemNames(map[string]interface{}{"name": "other*"})
+
+	// Assert
+	FailOnError(t, errMAC)
+	FailOnError(t, errPattern)
+	FailOnError(t, errNone)
+	if len(byMAC) != 1 || byMAC[0] != "test" {
+		t.Errorf("expected the system to be found by the MAC of its interface, got %v", byMAC)
+	}
+	if len(byPattern) != 1 || byPattern[0].Name != "test" {
+		t.Errorf("expected the system to be found by a pattern, got %v", byPattern)
+	}
+	if len(none) != 0 {
+		t.Errorf("expected no match, got %v", none)
+	}
+}
+
+func TestIntegrationCreateExistingSystem(t *testing.T) {
+	// Arrange
+	c, _ := fakeClient(t, cobbler.ClientConfig{})
+	system := createSystem(t, c)
+
+	// Act
+	_, err := c.CreateSystem(*system)
+
+	// Assert
+	if !errors.Is(err, cobbler.ErrAlreadyExists) {
+		t.Errorf("expected ErrAlreadyExists, got %v", err)
+	}
+}
+
+func TestIntegrationDeleteProfileWithChildren(t *testing.T) {
+	// Arrange
+	c, _ := fakeClient(t, cobbler.ClientConfig{})
+	createSystem(t, c)
+
+	// Act
+	err := c.DeleteProfile("centos7-x86_64")
+	errRecursive := c.DeleteProfileRecursive("centos7-x86_64", true)
+	systems, errSystems := c.GetSystems()
+
+	// Assert
+	if err == nil {
+		t.Errorf("expected the profile with a system not to be deleted")
+	}
+	FailOnError(t, errRecursive)
+	FailOnError(t, errSystems)
+	if len(systems) != 0 {
+		t.Errorf("expected the system to be deleted with its profile, got %d systems", len(systems))
+	}
+}
+
+func TestIntegrationBackgroundSync(t *testing.T) {
+	// Arrange
+	c, _ := fakeClient(t, cobbler.ClientConfig{})
+
+	// Act
+	id, err := c.BackgroundSync(cobbler.BackgroundSyncOptions{})
+	status, errStatus := c.GetTaskStatus(id)
+	events, errEvents := c.GetEvents("")
+	log, errLog := c.GetEventLog(id)
+
+	// Assert
+	FailOnError(t, err)
+	FailOnError(t, errStatus)
+	FailOnError(t, errEvents)
+	FailOnError(t, errLog)
+	if status.Name != "Sync" || status.State != "complete" {
+		t.Errorf("expected a completed sync, got %+v", status)
+	}
+	if len(events) != 1 || events[0].ID != id {
+		t.Errorf("expected the event of the sync, got %+v", events)
+	}
+	if log == "" {
+		t.Errorf("expected a log for the event")
+	}
+}
+
+func TestIntegrationInvalidToken(t *testing.T) {
+	// Arrange
+	c, _ := fakeClient(t, cobbler.ClientConfig{})
+	c.SetToken("invalid")
+
+	// Act
+	_, err := c.CreateDistro(cobbler.NewDistro())
+
+	// Assert
+	if !errors.Is(err, cobbler.ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestIntegrationAutoRelogin(t *testing.T) {
+	// Arrange
+	c, fake := fakeClient(t, cobbler.ClientConfig{AutoRelogin: true})
+	fake.ExpireTokens()
+
+	// Act
+	id, err := c.BackgroundSync(cobbler.BackgroundSyncOptions{})
+
+	// Assert
+	FailOnError(t, err)
+	if id == "" {
+		t.Errorf("expected an event id after logging in again")
+	}
+}
+
+func TestIntegrationLoginFailed(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(cobblerfake.NewServer())
+	defer server.Close()
+	c := cobbler.NewClient(http.DefaultClient, cobbler.ClientConfig{URL: server.URL, Username: "cobbler", Password: "wrong"})
+
+	// Act
+	_, err := c.Login()
+
+	// Assert
+	if !errors.Is(err, cobbler.ErrLoginFailed) {
+		t.Errorf("expected ErrLoginFailed, got %v", err)
+	}
+}
+
+// FailOnError stops the test if err isn't nil. It is repeated here since the helper of the package isn't visible to
+// external tests.
+func FailOnError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,129 @@
+package cobblerclient
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RPCCall describes a single XML-RPC call as seen by an [Interceptor]. Interceptors may modify the method and the
+// arguments before they pass the call on.
+type RPCCall struct {
+	// Method is the name of the XML-RPC method.
+	Method string
+	// Args are the arguments of the call before they are encoded to XML.
+	Args []interface{}
+	// Endpoint is the URL of the server that answered the call. It is set once the next [Invoker] returned and stays
+	// empty if no server could be reached or an interceptor answered the call itself.
+	Endpoint string
+
+	// sink receives the elements of the result if the call is streamed.
+	sink func(interface{}) error
+}
+
+// Invoker executes a call and returns the decoded result.
+type Invoker func(ctx context.Context, call *RPCCall) (interface{}, error)
+
+// Interceptor is a middleware around [Client.Call]. It receives the call and the next [Invoker] of the chain. An
+// interceptor can short-circuit a call by returning a result without calling next.
+type Interceptor func(ctx context.Context, call *RPCCall, next Invoker) (interface{}, error)
+
+// chainInterceptors wraps the invoker with the given interceptors. The first interceptor is the outermost one.
+func chainInterceptors(interceptors []Interceptor, invoker Invoker) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, call *RPCCall) (interface{}, error) {
+			return interceptor(ctx, call, next)
+		}
+	}
+	return invoker
+}
+
+// LoggingInterceptor logs every call with its duration and error. For "modify_*" calls, including those batched in a
+// "system.multicall", the modified attribute and the new value are logged as well. Tokens are never logged, the values
+// of sensitive attributes like "power_pass" are redacted. If logger is nil, the standard logger of the "log" package is
+// used.
+func LoggingInterceptor(logger *log.Logger) Interceptor {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return func(ctx context.Context, call *RPCCall, next Invoker) (interface{}, error) {
+		logModifications(logger, call)
+		start := time.Now()
+		result, err := next(ctx, call)
+		var endpoint string
+		if call.Endpoint != "" {
+			endpoint = " on " + call.Endpoint
+		}
+		if err != nil {
+			logger.Printf("[DEBUG] Cobblerclient: %s failed after %s%s: %s", call.Method, time.Since(start), endpoint, err)
+		} else {
+			logger.Printf("[DEBUG] Cobblerclient: %s succeeded after %s%s", call.Method, time.Since(start), endpoint)
+		}
+		return result, err
+	}
+}
+
+// sensitiveAttributes are the attributes whose values are redacted in the log. Attributes whose name contains "pass"
+// or "secret" are redacted as well.
+var sensitiveAttributes = []string{"default_password_crypted", "redhat_management_key"}
+
+// redactedValue replaces the values of sensitive attributes in the log.
+const redactedValue = "REDACTED"
+
+// logModifications logs the attributes that are set by a "modify_*" call or by the "modify_*" calls of a
+// "system.multicall".
+func logModifications(logger *log.Logger, call *RPCCall) {
+	if call.Method == "system.multicall" && len(call.Args) == 1 {
+		calls, _ := call.Args[0].([]interface{})
+		for _, rawCall := range calls {
+			nested, _ := rawCall.(map[string]interface{})
+			method, _ := nested["methodName"].(string)
+			params, _ := nested["params"].([]interface{})
+			logModification(logger, method, params)
+		}
+		return
+	}
+	logModification(logger, call.Method, call.Args)
+}
+
+// logModification logs the attribute that is set by a single "modify_*" call. The arguments of these calls are the
+// object handle, the attribute, the value and the token.
+func logModification(logger *log.Logger, method string, args []interface{}) {
+	if !strings.HasPrefix(method, "modify_") || len(args) < 3 {
+		return
+	}
+	attribute, ok := args[1].(string)
+	if !ok {
+		return
+	}
+	if attribute != "modify_interface" {
+		logger.Printf("[DEBUG] Cobblerclient: setting attr %s to %v", attribute, loggedValue(attribute, args[2]))
+		return
+	}
+	nic, ok := args[2].(map[string]interface{})
+	if !ok {
+		return
+	}
+	attrNames := make([]string, 0, len(nic))
+	for attrName := range nic {
+		attrNames = append(attrNames, attrName)
+	}
+	sort.Strings(attrNames)
+	for _, attrName := range attrNames {
+		logger.Printf("[DEBUG] Cobblerclient: setting interface attr %s to %v", attrName, loggedValue(attrName,
+			nic[attrName]))
+	}
+}
+
+// loggedValue returns the value of an attribute as it is logged. The attributes of interfaces are suffixed with the
+// interface name, e.g. "mac_address-eth0".
+func loggedValue(attribute string, value interface{}) interface{} {
+	name := strings.ToLower(attribute)
+	if strings.Contains(name, "pass") || strings.Contains(name, "secret") || stringInSlice(name, sensitiveAttributes) {
+		return redactedValue
+	}
+	return value
+}
@@ -0,0 +1,132 @@
+package cobblerclient
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/cobbler/cobblerclient/cobblertest"
+)
+
+func TestInterceptorsOrderAndResult(t *testing.T) {
+	// Arrange
+	var order []string
+	record := func(name string) Interceptor {
+		return func(ctx context.Context, call *RPCCall, next Invoker) (interface{}, error) {
+			order = append(order, name+":"+call.Method)
+			result, err := next(ctx, call)
+			order = append(order, name+":done")
+			return result, err
+		}
+	}
+	c := createStubHTTPClientSingle(t, "ping")
+	c.config.Interceptors = []Interceptor{record("outer"), record("inner")}
+
+	// Act
+	res, err := c.Ping()
+
+	// Assert
+	FailOnError(t, err)
+	if !res {
+		t.Fatalf("Expected ping to return true")
+	}
+	expected := "outer:ping inner:ping inner:done outer:done"
+	if strings.Join(order, " ") != expected {
+		t.Fatalf("expected %q, got %q", expected, strings.Join(order, " "))
+	}
+}
+
+func TestInterceptorShortCircuit(t *testing.T) {
+	// Arrange
+	hc := cobblertest.NewStub(t)
+	cfg := config
+	cfg.Interceptors = []Interceptor{
+		func(ctx context.Context, call *RPCCall, next Invoker) (interface{}, error) {
+			if call.Method == "get_event_log" && call.Args[0] == "event-1" {
+				return "cached log", nil
+			}
+			return next(ctx, call)
+		},
+	}
+	c := NewClient(hc, cfg)
+
+	// Act
+	res, err := c.GetEventLog("event-1")
+
+	// Assert
+	FailOnError(t, err)
+	if res != "cached log" {
+		t.Fatalf("expected the result of the interceptor, got %q", res)
+	}
+}
+
+func TestLoggingInterceptor(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", 0)
+	hc := cobblertest.NewStub(t)
+	cfg := config
+	cfg.Interceptors = []Interceptor{
+		LoggingInterceptor(logger),
+		func(ctx context.Context, call *RPCCall, next Invoker) (interface{}, error) {
+			return true, nil
+		},
+	}
+	c := NewClient(hc, cfg)
+	c.SetToken("securetoken99")
+
+	// Act
+	err := c.ModifyInterface("system::1", makeInterfaceOptionsMap("eth0", Interface{MACAddress: "aa:bb:cc:dd:ee:ff"}))
+
+	// Assert
+	FailOnError(t, err)
+	output := buf.String()
+	if !strings.Contains(output, "setting interface attr mac_address-eth0 to aa:bb:cc:dd:ee:ff") {
+		t.Errorf("expected the interface attribute to be logged, got:\n%s", output)
+	}
+	if !strings.Contains(output, "modify_system succeeded") {
+		t.Errorf("expected the result to be logged, got:\n%s", output)
+	}
+	if strings.Contains(output, "securetoken99") {
+		t.Errorf("the token must not be logged")
+	}
+}
+
+func TestLoggingInterceptorMulticall(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", 0)
+	cfg := config
+	cfg.Interceptors = []Interceptor{
+		LoggingInterceptor(logger),
+		func(ctx context.Context, call *RPCCall, next Invoker) (interface{}, error) {
+			return []interface{}{}, nil
+		},
+	}
+	c := NewClient(cobblertest.NewStub(t), cfg)
+	calls := []interface{}{
+		map[string]interface{}{
+			"methodName": "modify_system",
+			"params":     []interface{}{"system::1", "comment", "rack 4", "securetoken99"},
+		},
+		map[string]interface{}{
+			"methodName": "modify_system",
+			"params":     []interface{}{"system::1", "power_pass", "hunter2", "securetoken99"},
+		},
+	}
+
+	// Act
+	_, err := c.Call("system.multicall", calls)
+
+	// Assert
+	FailOnError(t, err)
+	output := buf.String()
+	if !strings.Contains(output, "setting attr comment to rack 4") {
+		t.Errorf("expected the batched attribute to be logged, got:\n%s", output)
+	}
+	if !strings.Contains(output, "setting attr power_pass to REDACTED") || strings.Contains(output, "hunter2") {
+		t.Errorf("expected the password to be redacted, got:\n%s", output)
+	}
+}
@@ -2,6 +2,8 @@ package cobblerclient
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
 	"strings"
 )
 
@@ -28,6 +30,16 @@ type ItemMeta struct {
 	IsResolved  bool
 	// This flag signals if the item was modified by a called method server-side.
 	IsDirty bool
+
+	// snapshot holds the attributes as they were read from the server or last sent to it. Updates only send the
+	// attributes that differ from it. Items that weren't read from the server don't have a snapshot.
+	snapshot fieldSnapshot
+}
+
+// ForceFullUpdate drops the state of the item as read from the server, thus the next update sends all attributes
+// instead of the modified ones. This overwrites modifications that other clients made in the meantime.
+func (m *ItemMeta) ForceFullUpdate() {
+	m.snapshot = nil
 }
 
 // Item general fields
@@ -91,10 +103,96 @@ func NewItem() Item {
 	}
 }
 
+// sanitizeItem cleans the [Value] attributes of a decoded item struct, including those of embedded structs like [Item].
+// Maps that the server didn't send are replaced by empty ones.
+func sanitizeItem(item reflect.Value) error {
+	itemType := item.Type()
+	for i := 0; i < item.NumField(); i++ {
+		field := item.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+		var err error
+		switch value := field.Addr().Interface().(type) {
+		case *Value[map[string]interface{}]:
+			err = sanitizeValueMapStruct(value)
+		case *Value[[]string]:
+			err = sanitizeValueSliceStruct(value)
+		default:
+			if itemType.Field(i).Anonymous && field.Kind() == reflect.Struct {
+				err = sanitizeItem(field)
+			} else if field.Kind() == reflect.Map && field.IsNil() {
+				field.Set(reflect.MakeMap(field.Type()))
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sanitizeValueSliceStruct(value *Value[[]string]) error {
+	if value.IsInherited {
+		value.Data = make([]string, 0)
+		value.FlattenedValue = ""
+		value.RawData = make([]string, 0)
+	} else {
+		rawSlice, isSlice := value.RawData.([]interface{})
+		kopts, err := convertToStringSlice(rawSlice)
+		if isSlice && err == nil {
+			value.Data = kopts
+		} else {
+			kopts, ok := value.RawData.(string)
+			if ok {
+				value.Data = make([]string, 0)
+				value.FlattenedValue = kopts
+			} else {
+				if value.RawData == nil {
+					value.Data = make([]string, 0)
+					value.FlattenedValue = ""
+					value.RawData = make([]string, 0)
+				} else {
+					return fmt.Errorf("error converting raw list value")
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func sanitizeValueMapStruct(value *Value[map[string]interface{}]) error {
+	if value.IsInherited {
+		value.Data = make(map[string]interface{})
+		value.FlattenedValue = ""
+		value.RawData = make(map[string]interface{})
+	} else {
+		kopts, ok := value.RawData.(map[string]interface{})
+		if ok {
+			value.Data = kopts
+		} else {
+			kopts, ok := value.RawData.(string)
+			if ok {
+				value.Data = make(map[string]interface{})
+				value.FlattenedValue = kopts
+			} else {
+				if value.RawData == nil {
+					value.Data = make(map[string]interface{})
+					value.FlattenedValue = ""
+					value.RawData = make(map[string]interface{})
+				} else {
+					return fmt.Errorf("error converting raw map value")
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // ModifyItem is a generic method to modify items. Changes made with this method are not persisted until a call to
 // SaveItem or one of its other concrete methods.
 func (c *Client) ModifyItem(what, objectId, attribute string, arg interface{}) error {
-	_, err := c.Call("modify_item", what, objectId, attribute, arg, c.Token)
+	_, err := c.Call("modify_item", what, objectId, attribute, arg, c.Token())
 	return err
 }
 
@@ -111,8 +209,10 @@ func (c *Client) ModifyItemInPlace(what, name, attribute string, value map[strin
 		"params",
 	}
 	if !stringInSlice(attribute, itemKey) {
-		return errors.New("invalid attribute for in-place modification")
+		return &ValidationError{What: what, Field: attribute, Message: "invalid attribute for in-place modification"}
 	}
+	// The item is modified based on its current value, thus it must not be read from a replica.
+	c = c.onPrimary()
 	rawItem, err := c.GetItem(what, name, false, false)
 	if err != nil {
 		return err
@@ -140,13 +240,13 @@ func (c *Client) ModifyItemInPlace(what, name, attribute string, value map[strin
 	if err != nil {
 		return err
 	}
-	return c.SaveItem(what, itemHandle, c.Token, "bypass")
+	return c.SaveItem(what, itemHandle, c.Token(), EditModeBypass)
 }
 
 // GetItemNames returns the list of names for a specified object type present inside Cobbler.
 func (c *Client) GetItemNames(what string) ([]string, error) {
 	resultUnmarshalled, err := c.Call("get_item_names", what)
-	return returnStringSlice(resultUnmarshalled, err)
+	return returnStringSlice("get_item_names", resultUnmarshalled, err)
 }
 
 // GetItemResolvedValue retrieves the value of a single attribute of a single item which was passed through the
@@ -162,34 +262,29 @@ func (c *Client) GetItem(what string, name string, flatten, resolved bool) (map[
 	if err != nil {
 		return nil, err
 	}
-	marshalledResult, marshallSuccessful := unmarshalledResult.(map[string]interface{})
-	if !marshallSuccessful {
-		notFoundMarker, marshallSuccessful := unmarshalledResult.(string)
-		if !marshallSuccessful {
-			return nil, errors.New("marshall to map unsuccessful and not-found marker not detected")
-		}
-		if notFoundMarker == "~" {
-			return make(map[string]interface{}), nil
-		}
+	if notFoundMarker, ok := unmarshalledResult.(string); ok && notFoundMarker == "~" {
+		return make(map[string]interface{}), nil
+	}
+	marshalledResult, ok := unmarshalledResult.(map[string]interface{})
+	if !ok {
+		return nil, &DecodeError{Method: "get_item", Expected: "struct or not-found marker", Value: unmarshalledResult}
 	}
 	return marshalledResult, nil
 }
 
 func (c *Client) getConcreteItem(method, name string, flattened, resolved bool) (interface{}, error) {
-	// Verify CachedVersion is set
-	err := c.setCachedVersion()
+	withResolved, err := c.Supports(CapabilityResolvedItems)
 	if err != nil {
 		return nil, err
 	}
 
-	// resolved was added with 3.3.3
 	var result interface{}
-	if c.CachedVersion.GreaterThan(&CobblerVersion{3, 3, 3}) {
+	if withResolved {
 		// name, flatten, resolved, token
-		result, err = c.Call(method, name, flattened, resolved, c.Token)
+		result, err = c.Call(method, name, flattened, resolved, c.Token())
 	} else {
 		// name, flatten, token
-		result, err = c.Call(method, name, flattened, c.Token)
+		result, err = c.Call(method, name, flattened, c.Token())
 	}
 
 	return result, err
@@ -198,12 +293,15 @@ func (c *Client) getConcreteItem(method, name string, flattened, resolved bool)
 // FindItems searches for one or more items by any of its attributes.
 func (c *Client) FindItems(what string, criteria map[string]interface{}, sortField string, expand bool) ([]interface{}, error) {
 	unmarshalledResult, err := c.Call("find_items", what, criteria, sortField, expand)
-	return unmarshalledResult.([]interface{}), err
+	if err != nil {
+		return nil, err
+	}
+	return asSlice("find_items", unmarshalledResult)
 }
 
 func (c *Client) FindItemNames(what string, criteria map[string]interface{}, sortField string) ([]string, error) {
 	unmarshalledResult, err := c.Call("find_items", what, criteria, sortField, false)
-	return returnStringSlice(unmarshalledResult, err)
+	return returnStringSlice("find_items", unmarshalledResult, err)
 }
 
 type PageInfo struct {
@@ -227,7 +325,7 @@ type PagedSearchResult struct {
 // FindItemsPaged searches for items with the given criteria and returning
 func (c *Client) FindItemsPaged(what string, criteria map[string]interface{}, sortField string, page, itemsPerPage int32) (*PagedSearchResult, error) {
 	var pagedSearchResult PagedSearchResult
-	unmarshalledResult, err := c.Call("find_items_paged", what, criteria, sortField, page, itemsPerPage, c.Token)
+	unmarshalledResult, err := c.Call("find_items_paged", what, criteria, sortField, page, itemsPerPage, c.Token())
 	if err != nil {
 		return nil, err
 	}
@@ -240,29 +338,26 @@ func (c *Client) FindItemsPaged(what string, criteria map[string]interface{}, so
 
 // HasItem checks if an item with the given name exists.
 func (c *Client) HasItem(what string, name string) (bool, error) {
-	result, err := c.Call("has_item", what, name, c.Token)
-	return result.(bool), err
+	result, err := c.Call("has_item", what, name, c.Token())
+	return returnBool("has_item", result, err)
 }
 
 // GetItemHandle gets the internal ID of a Cobbler item.
 func (c *Client) GetItemHandle(what, name string) (string, error) {
-	result, err := c.Call("get_item_handle", what, name, c.Token)
-	if err != nil {
-		return "", err
-	}
-	return result.(string), err
+	result, err := c.Call("get_item_handle", what, name, c.Token())
+	return returnString("get_item_handle", result, err)
 }
 
 // RenameItem renames an item.
 func (c *Client) RenameItem(what, objectId, newName string) error {
-	_, err := c.Call("rename_item", what, objectId, newName, c.Token)
+	_, err := c.Call("rename_item", what, objectId, newName, c.Token())
 	return err
 }
 
 // NewItem creates a new empty item that has to be filled with data. The item does not exist in the database
 // before [Client.SaveItem] was called.
 func (c *Client) NewItem(what string, isSubobject bool) error {
-	_, err := c.Call("new_item", what, c.Token, isSubobject)
+	_, err := c.Call("new_item", what, c.Token(), isSubobject)
 	return err
 }
 
@@ -274,12 +369,12 @@ func (c *Client) SaveItem(what, objectId, token, editmode string) error {
 
 // RemoveItem deletes an item from the Cobbler database.
 func (c *Client) RemoveItem(what, name string, recursive bool) error {
-	_, err := c.Call("remove_item", what, name, c.Token, recursive)
+	_, err := c.Call("remove_item", what, name, c.Token(), recursive)
 	return err
 }
 
 // CopyItem duplicates an item on the server with a new name.
 func (c *Client) CopyItem(what, objectId, newName string) error {
-	_, err := c.Call("copy_item", what, objectId, newName, c.Token)
+	_, err := c.Call("copy_item", what, objectId, newName, c.Token())
 	return err
 }
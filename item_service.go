@@ -0,0 +1,292 @@
+package cobblerclient
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ItemService provides the operations that all Cobbler item types share. T is the struct of the item type, which must
+// embed [Item] directly or through another struct like [Resource]. The methods of [Client] for the built-in item types
+// wrap an ItemService, other item types of the server are supported by declaring their struct:
+//
+//	type Network struct {
+//		cobbler.Item `mapstructure:",squash"`
+//		CIDR         string `mapstructure:"cidr"`
+//	}
+//
+//	networks := cobbler.NewItemService[Network](&client, "network")
+//	network, err := networks.Get("lan", false, false)
+type ItemService[T any] struct {
+	client *Client
+	// what is the item type as used in method names like "get_system", plural as used in "get_systems".
+	what   string
+	plural string
+	// prepare validates a new item and fills in defaults before it is created. It is optional.
+	prepare func(item *T) error
+	// page is the first argument of "get_<plural>". It is "-1" for all types except systems, which use an empty string.
+	page string
+}
+
+// NewItemService returns the service for the item type what, e.g. "system". It panics if T doesn't embed [Item].
+func NewItemService[T any](c *Client, what string) *ItemService[T] {
+	itemType := reflect.TypeOf((*T)(nil)).Elem()
+	if field, ok := itemType.FieldByName("Item"); !ok || field.Type != reflect.TypeOf(Item{}) {
+		panic(fmt.Sprintf("cobblerclient: %s doesn't embed Item", itemType))
+	}
+	plural := what + "s"
+	if strings.HasSuffix(what, "s") {
+		plural = what + "es"
+	}
+	return &ItemService[T]{client: c, what: what, plural: plural, page: "-1"}
+}
+
+// What returns the item type of the service.
+func (s *ItemService[T]) What() string {
+	return s.what
+}
+
+// GetAll returns all items of the type.
+func (s *ItemService[T]) GetAll() ([]*T, error) {
+	return s.cache().list()
+}
+
+// Each calls fn for every item of the type. The items are decoded one by one while the response is read, thus only a
+// single item is held in memory at a time. The iteration stops at the first error returned by fn, which is returned
+// unchanged.
+func (s *ItemService[T]) Each(fn func(*T) error) error {
+	c := s.client
+	return eachItem(c, "get_"+s.plural, s.convert, fn, s.page, c.Token())
+}
+
+// Get returns a single item obtained by its name.
+func (s *ItemService[T]) Get(name string, flattened, resolved bool) (*T, error) {
+	return s.cache().get(name, flattened, resolved)
+}
+
+// get reads a single item from the server.
+func (s *ItemService[T]) get(name string, flattened, resolved bool) (*T, error) {
+	result, err := s.client.getConcreteItem("get_"+s.what, name, flattened, resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.convert(name, result)
+	if err != nil {
+		return nil, err
+	}
+	meta := &itemOf(item).Meta
+	meta.IsFlattened = flattened
+	meta.IsResolved = resolved
+	return item, nil
+}
+
+// cache describes how the items are cached if [ClientConfig.ReadCache] is enabled.
+func (s *ItemService[T]) cache() itemCache[T] {
+	return itemCache[T]{
+		client: s.client,
+		what:   s.what,
+		all: func() ([]*T, error) {
+			return collectItems(s.Each)
+		},
+		since: s.Since,
+		one:   s.get,
+	}
+}
+
+// Create creates an item and returns it as stored by the server.
+func (s *ItemService[T]) Create(item T) (*T, error) {
+	s = s.onPrimary()
+	c := s.client
+	name := itemOf(&item).Name
+	// Make sure an item with the same name does not already exist
+	if _, err := s.Get(name, false, false); err == nil {
+		return nil, alreadyExistsError(s.what, name)
+	}
+
+	if s.prepare != nil {
+		if err := s.prepare(&item); err != nil {
+			return nil, err
+		}
+	}
+
+	// To create an item via the Cobbler API, first call new_<what> to obtain an ID
+	result, err := c.Call("new_"+s.what, c.Token())
+	if err != nil {
+		return nil, err
+	}
+	newID, err := asString("new_"+s.what, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.updateCobblerFields(s.what, reflect.ValueOf(&item).Elem(), newID); err != nil {
+		return nil, err
+	}
+
+	if err := s.Save(newID, EditModeNew); err != nil {
+		return nil, err
+	}
+
+	// Return a clean copy of the item
+	return s.Get(name, false, false)
+}
+
+// Update sends the attributes of an existing item that were modified since it was read from the server and saves it.
+// Network interfaces that were removed from a system are deleted. Items without a known server state, like those
+// created locally or after [ItemMeta.ForceFullUpdate], are sent with all attributes. Nothing is sent if no attribute
+// was modified. Use [ItemService.Edit] to be able to discard the modifications. A [RefreshError] signals that the item
+// was saved although an error is returned.
+func (s *ItemService[T]) Update(item *T) error {
+	s = s.onPrimary()
+	c := s.client
+	meta := &itemOf(item).Meta
+	all := collectFieldUpdates(s.what, reflect.ValueOf(item).Elem())
+	updates := all
+	if meta.snapshot != nil {
+		if updates = changedFieldUpdates(all, meta.snapshot); len(updates) == 0 {
+			return nil
+		}
+	}
+
+	if err := c.checkConflict(s.what, itemOf(item)); err != nil {
+		return err
+	}
+
+	id, err := c.GetItemHandle(s.what, itemOf(item).Name)
+	if err != nil {
+		return err
+	}
+
+	if err := c.applyFieldUpdates(s.what, id, updates); err != nil {
+		return err
+	}
+
+	if err := s.Save(id, EditModeBypass); err != nil {
+		return err
+	}
+	meta.snapshot = newFieldSnapshot(all)
+	return c.refreshMTime(s.what, itemOf(item))
+}
+
+// onPrimary returns a copy of the service whose calls are all sent to the primary server (see [Client.onPrimary]).
+func (s *ItemService[T]) onPrimary() *ItemService[T] {
+	s2 := *s
+	s2.client = s.client.onPrimary()
+	return &s2
+}
+
+// Save saves all changes performed via XML-RPC to disk on the server side.
+func (s *ItemService[T]) Save(objectId, editmode string) error {
+	_, err := s.client.Call("save_"+s.what, objectId, s.client.Token(), editmode)
+	return err
+}
+
+// Copy duplicates an item on the server with a new name.
+func (s *ItemService[T]) Copy(objectId, newName string) error {
+	_, err := s.client.Call("copy_"+s.what, objectId, newName, s.client.Token())
+	return err
+}
+
+// Rename renames an item with a given object id.
+func (s *ItemService[T]) Rename(objectId, newName string) error {
+	_, err := s.client.Call("rename_"+s.what, objectId, newName, s.client.Token())
+	return err
+}
+
+// Delete deletes a single item by its name with the option to do so recursively.
+func (s *ItemService[T]) Delete(name string, recursive bool) error {
+	_, err := s.client.Call("remove_"+s.what, name, s.client.Token(), recursive)
+	return err
+}
+
+// ListNames returns the names of all items of the type.
+func (s *ItemService[T]) ListNames() ([]string, error) {
+	return s.client.GetItemNames(s.what)
+}
+
+// Find searches for one or more items by any of their attributes.
+func (s *ItemService[T]) Find(criteria map[string]interface{}) ([]*T, error) {
+	method := "find_" + s.what
+	result, err := s.client.Call(method, criteria, true, s.client.Token())
+	if err != nil {
+		return nil, err
+	}
+	return s.convertList(method, result)
+}
+
+// FindNames searches for one or more items by any of their attributes and returns their names.
+func (s *ItemService[T]) FindNames(criteria map[string]interface{}) ([]string, error) {
+	method := "find_" + s.what
+	result, err := s.client.Call(method, criteria, false, s.client.Token())
+	return returnStringSlice(method, result, err)
+}
+
+// Since returns all items which were modified after the specified date.
+func (s *ItemService[T]) Since(mtime time.Time) ([]*T, error) {
+	method := "get_" + s.plural + "_since"
+	result, err := s.client.Call(method, float64(mtime.Unix()))
+	if err != nil {
+		return nil, err
+	}
+	return s.convertList(method, result)
+}
+
+// Handle gets the internal ID of an item.
+func (s *ItemService[T]) Handle(name string) (string, error) {
+	method := "get_" + s.what + "_handle"
+	result, err := s.client.Call(method, name, s.client.Token())
+	return returnString(method, result, err)
+}
+
+// AsRendered returns the attributes of an item after they have passed through the inheritance chain of Cobbler.
+func (s *ItemService[T]) AsRendered(name string) (map[string]interface{}, error) {
+	method := "get_" + s.what + "_as_rendered"
+	result, err := s.client.Call(method, name, s.client.Token())
+	if err != nil {
+		return nil, err
+	}
+	return asMap(method, result)
+}
+
+// convert decodes a single item and sanitizes its [Value] attributes.
+func (s *ItemService[T]) convert(name string, xmlrpcResult interface{}) (*T, error) {
+	if xmlrpcResult == "~" {
+		return nil, notFoundError(s.what, name)
+	}
+
+	var item T
+	if _, err := decodeCobblerItem(xmlrpcResult, &item); err != nil {
+		return nil, err
+	}
+	if err := sanitizeItem(reflect.ValueOf(&item).Elem()); err != nil {
+		return nil, err
+	}
+	itemOf(&item).Meta.snapshot = snapshotItem(s.what, reflect.ValueOf(&item).Elem())
+	return &item, nil
+}
+
+// convertList decodes the items of a list returned by method.
+func (s *ItemService[T]) convertList(method string, xmlrpcResult interface{}) ([]*T, error) {
+	var items []*T
+
+	rawItems, err := asSlice(method, xmlrpcResult)
+	if err != nil {
+		return nil, err
+	}
+	for _, rawItem := range rawItems {
+		item, err := s.convert("unknown", rawItem)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// itemOf returns the embedded [Item] of a pointer to an item struct.
+func itemOf(item interface{}) *Item {
+	return reflect.ValueOf(item).Elem().FieldByName("Item").Addr().Interface().(*Item)
+}
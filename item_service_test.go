@@ -0,0 +1,193 @@
+package cobblerclient
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cobbler/cobblerclient/cobblertest"
+)
+
+// network is an item type that the client doesn't know.
+type network struct {
+	Item `mapstructure:",squash"`
+
+	CIDR    string            `mapstructure:"cidr"`
+	Options map[string]string `mapstructure:"options"`
+}
+
+// networkModifications is the number of "modify_network" calls for all attributes of a network.
+const networkModifications = 13
+
+func networkService(t *testing.T) (*ItemService[network], *cobblertest.Stub) {
+	stub := cobblertest.NewStub(t)
+	c := NewClient(stub, config)
+	c.SetToken(cobblertest.Token)
+	c.setCachedVersion(CobblerVersion{3, 3, 4})
+	return NewItemService[network](&c, "network"), stub
+}
+
+func TestNewItemServiceWithoutItem(t *testing.T) {
+	// Arrange
+	defer func() {
+		// Assert
+		if recover() == nil {
+			t.Errorf("expected a panic for a struct without Item")
+		}
+	}()
+	c := NewClient(nil, config)
+
+	// Act
+	NewItemService[PageInfo](&c, "pageinfo")
+}
+
+func TestItemServiceGet(t *testing.T) {
+	// Arrange
+	s, stub := networkService(t)
+	stub.Expect("get_network").WithArgs("lan", false, true, cobblertest.Token).Reply(cobblertest.Item("lan",
+		map[string]interface{}{
+			"cidr":           "10.0.0.0/24",
+			"kernel_options": cobblertest.Inherit,
+			"owners":         []string{"admin"},
+		}))
+
+	// Act
+	lan, err := s.Get("lan", false, true)
+
+	// Assert
+	FailOnError(t, err)
+	if lan.Name != "lan" || lan.CIDR != "10.0.0.0/24" || !lan.Meta.IsResolved {
+		t.Errorf("unexpected network %+v", lan)
+	}
+	if !lan.KernelOptions.IsInherited || lan.KernelOptions.Data == nil {
+		t.Errorf("expected the inherited kernel options to be sanitized, got %+v", lan.KernelOptions)
+	}
+	if len(lan.Owners.Data) != 1 || lan.Owners.Data[0] != "admin" {
+		t.Errorf("expected the owners to be sanitized, got %+v", lan.Owners)
+	}
+	if lan.Options == nil {
+		t.Errorf("expected the missing options to be an empty map")
+	}
+}
+
+func TestItemServiceGetNotFound(t *testing.T) {
+	// Arrange
+	s, stub := networkService(t)
+	stub.Expect("get_network").Reply(cobblertest.NotFound)
+
+	// Act
+	_, err := s.Get("wan", false, false)
+
+	// Assert
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestItemServiceCreate(t *testing.T) {
+	// Arrange
+	s, stub := networkService(t)
+	stub.Expect("get_network").Reply(cobblertest.NotFound)
+	stub.Expect("new_network").WithArgs(cobblertest.Token).Reply(cobblertest.NewHandle("network"))
+	stub.Expect("modify_network").Times(networkModifications)
+	stub.Expect("save_network").WithArgs(cobblertest.NewHandle("network"), cobblertest.Token, "new")
+	stub.Expect("get_network").Reply(cobblertest.Item("lan", map[string]interface{}{"cidr": "10.0.0.0/24"}))
+	lan := network{Item: NewItem(), CIDR: "10.0.0.0/24"}
+	lan.Name = "lan"
+
+	// Act
+	created, err := s.Create(lan)
+
+	// Assert
+	FailOnError(t, err)
+	if created.CIDR != "10.0.0.0/24" {
+		t.Errorf("expected the created network, got %+v", created)
+	}
+}
+
+func TestItemServiceUpdate(t *testing.T) {
+	// Arrange
+	s, stub := networkService(t)
+	stub.Expect("get_item_handle").WithArgs("network", "lan", cobblertest.Token).
+		Reply(cobblertest.Handle("network", "lan"))
+	stub.Expect("modify_network").Times(networkModifications)
+	stub.Expect("save_network").WithArgs(cobblertest.Handle("network", "lan"), cobblertest.Token, "bypass")
+	lan := network{Item: NewItem()}
+	lan.Name = "lan"
+
+	// Act
+	err := s.Update(&lan)
+
+	// Assert
+	FailOnError(t, err)
+}
+
+func TestItemServiceMethodNames(t *testing.T) {
+	// Arrange
+	stub := cobblertest.NewStub(t)
+	c := NewClient(stub, config)
+	c.SetToken(cobblertest.Token)
+	s := NewItemService[MgmtClass](&c, "mgmtclass")
+	stub.Expect("get_mgmtclasses_since").WithArgs(0.0).Reply(cobblertest.Items())
+	stub.Expect("find_mgmtclass").WithArgs(map[string]interface{}{"name": "web*"}, false, cobblertest.Token).
+		Reply([]string{"webserver"})
+	stub.Expect("get_mgmtclass_as_rendered").WithArgs("webserver", cobblertest.Token).
+		Reply(map[string]interface{}{"name": "webserver"})
+	stub.Expect("remove_mgmtclass").WithArgs("webserver", cobblertest.Token, true)
+
+	// Act
+	since, errSince := s.Since(time.Unix(0, 0))
+	names, errNames := s.FindNames(map[string]interface{}{"name": "web*"})
+	rendered, errRendered := s.AsRendered("webserver")
+	errDelete := s.Delete("webserver", true)
+
+	// Assert
+	FailOnError(t, errSince)
+	FailOnError(t, errNames)
+	FailOnError(t, errRendered)
+	FailOnError(t, errDelete)
+	if len(since) != 0 || len(names) != 1 || rendered["name"] != "webserver" {
+		t.Errorf("unexpected results %v %v %v", since, names, rendered)
+	}
+}
+
+func TestItemServiceUpdateChanged(t *testing.T) {
+	// Arrange
+	s, stub := networkService(t)
+	stub.Expect("get_network").Reply(cobblertest.Item("lan", map[string]interface{}{"cidr": "10.0.0.0/24"}))
+	stub.Expect("get_item_handle").Reply(cobblertest.Handle("network", "lan"))
+	stub.Expect("modify_network").WithArgs(cobblertest.Handle("network", "lan"), "kernel_options",
+		map[string]interface{}{"console": "ttyS0"}, cobblertest.Token)
+	stub.Expect("modify_network").WithArgs(cobblertest.Handle("network", "lan"), "cidr", "10.0.1.0/24", cobblertest.Token)
+	stub.Expect("save_network").WithArgs(cobblertest.Handle("network", "lan"), cobblertest.Token, "bypass")
+	lan, err := s.Get("lan", false, false)
+	FailOnError(t, err)
+	lan.CIDR = "10.0.1.0/24"
+	lan.KernelOptions.Data["console"] = "ttyS0"
+
+	// Act
+	err = s.Update(lan)
+	errUnchanged := s.Update(lan)
+
+	// Assert
+	FailOnError(t, err)
+	FailOnError(t, errUnchanged)
+}
+
+func TestItemServiceUpdateForceFull(t *testing.T) {
+	// Arrange
+	s, stub := networkService(t)
+	stub.Expect("get_network").Reply(cobblertest.Item("lan", nil))
+	stub.Expect("get_item_handle").Reply(cobblertest.Handle("network", "lan"))
+	stub.Expect("modify_network").Times(networkModifications)
+	stub.Expect("save_network")
+	lan, err := s.Get("lan", false, false)
+	FailOnError(t, err)
+
+	// Act
+	lan.Meta.ForceFullUpdate()
+	err = s.Update(lan)
+
+	// Assert
+	FailOnError(t, err)
+}
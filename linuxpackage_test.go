@@ -39,7 +39,7 @@ func TestGetPackages(t *testing.T) {
 func TestGetPackage(t *testing.T) {
 	// Arrange
 	c := createStubHTTPClientSingle(t, "get-package")
-	c.CachedVersion = CobblerVersion{3, 3, 2}
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
 
 	// Act
 	linuxpackage, err := c.GetPackage("testpackage", false, false)
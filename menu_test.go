@@ -28,6 +28,7 @@ func TestNewMenu(t *testing.T) {
 
 func TestGetMenus(t *testing.T) {
 	c := createStubHTTPClientSingle(t, "get-menus")
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
 	menus, err := c.GetMenus()
 	FailOnError(t, err)
 
@@ -39,7 +40,7 @@ func TestGetMenus(t *testing.T) {
 func TestGetMenu(t *testing.T) {
 	// Arrange
 	c := createStubHTTPClientSingle(t, "get-menu")
-	c.CachedVersion = CobblerVersion{3, 3, 2}
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
 
 	// Act
 	menu, err := c.GetMenu("testmenu", false, false)
@@ -53,18 +54,21 @@ func TestGetMenu(t *testing.T) {
 
 func TestDeleteMenu(t *testing.T) {
 	c := createStubHTTPClientSingle(t, "delete-menu")
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
 	err := c.DeleteMenu("test")
 	FailOnError(t, err)
 }
 
 func TestDeleteMenuRecursive(t *testing.T) {
 	c := createStubHTTPClientSingle(t, "delete-menu")
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
 	err := c.DeleteMenuRecursive("test", false)
 	FailOnError(t, err)
 }
 
 func TestListMenuNames(t *testing.T) {
 	c := createStubHTTPClientSingle(t, "get-item-names-menu")
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
 	menus, err := c.ListMenuNames()
 	FailOnError(t, err)
 
@@ -75,6 +79,7 @@ func TestListMenuNames(t *testing.T) {
 
 func TestGetMenusSince(t *testing.T) {
 	c := createStubHTTPClientSingle(t, "get-menus-since")
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
 	menus, err := c.GetMenusSince(time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC))
 	FailOnError(t, err)
 
@@ -85,6 +90,7 @@ func TestGetMenusSince(t *testing.T) {
 
 func TestFindMenu(t *testing.T) {
 	c := createStubHTTPClientSingle(t, "find-menu")
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
 	criteria := make(map[string]interface{}, 1)
 	criteria["name"] = "testmenu"
 	menus, err := c.FindMenu(criteria)
@@ -97,6 +103,7 @@ func TestFindMenu(t *testing.T) {
 
 func TestFindMenuNames(t *testing.T) {
 	c := createStubHTTPClientSingle(t, "find-menu-names")
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
 	criteria := make(map[string]interface{}, 1)
 	criteria["name"] = "testmenu"
 	menus, err := c.FindMenuNames(criteria)
@@ -109,24 +116,28 @@ func TestFindMenuNames(t *testing.T) {
 
 func TestSaveMenu(t *testing.T) {
 	c := createStubHTTPClientSingle(t, "save-menu")
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
 	err := c.SaveMenu("menu::testmenu", "bypass")
 	FailOnError(t, err)
 }
 
 func TestCopyMenu(t *testing.T) {
 	c := createStubHTTPClientSingle(t, "copy-menu")
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
 	err := c.CopyMenu("menu::testmenu", "testmenu2")
 	FailOnError(t, err)
 }
 
 func TestRenameMenu(t *testing.T) {
 	c := createStubHTTPClientSingle(t, "rename-menu")
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
 	err := c.RenameMenu("menu::testmenu2", "testmenu1")
 	FailOnError(t, err)
 }
 
 func TestGetMenuHandle(t *testing.T) {
 	c := createStubHTTPClientSingle(t, "get-menu-handle")
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
 	res, err := c.GetMenuHandle("testmenu")
 	FailOnError(t, err)
 
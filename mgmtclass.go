@@ -1,10 +1,6 @@
 package cobblerclient
 
-import (
-	"fmt"
-	"reflect"
-	"time"
-)
+import "time"
 
 type MgmtClass struct {
 	Item `mapstructure:",squash"`
@@ -26,217 +22,95 @@ func NewMgmtClass() MgmtClass {
 	}
 }
 
-func convertRawMgmtClass(name string, xmlrpcResult interface{}) (*MgmtClass, error) {
-	var mgmtclass MgmtClass
-
-	if xmlrpcResult == "~" {
-		return nil, fmt.Errorf("mgmtclass %s not found", name)
-	}
-
-	decodeResult, err := decodeCobblerItem(xmlrpcResult, &mgmtclass)
-	if err != nil {
-		return nil, err
-	}
-
-	// Now clean the Value structs
-	decodedMgmtClass := decodeResult.(*MgmtClass)
-	err = sanitizeValueMapStruct(&decodedMgmtClass.KernelOptions)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedMgmtClass.KernelOptionsPost)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedMgmtClass.AutoinstallMeta)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedMgmtClass.FetchableFiles)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedMgmtClass.BootFiles)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedMgmtClass.TemplateFiles)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedMgmtClass.MgmtParameters)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueSliceStruct(&decodedMgmtClass.Owners)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueSliceStruct(&decodedMgmtClass.MgmtClasses)
-	return decodedMgmtClass, nil
-}
-
-func convertRawMgmtClassList(xmlrpcResult interface{}) ([]*MgmtClass, error) {
-	var mgmtclasses []*MgmtClass
-
-	for _, d := range xmlrpcResult.([]interface{}) {
-		mgmtclass, err := convertRawMgmtClass("unknown", d)
-		if err != nil {
-			return nil, err
-		}
-		mgmtclass.Meta = ItemMeta{
-			IsFlattened: false,
-			IsResolved:  false,
-		}
-		mgmtclasses = append(mgmtclasses, mgmtclass)
-	}
-
-	return mgmtclasses, nil
+// mgmtClasses returns the service for management classes.
+func (c *Client) mgmtClasses() *ItemService[MgmtClass] {
+	return NewItemService[MgmtClass](c, "mgmtclass")
 }
 
-// GetMgmtClasses returns all mgmtclasses in Cobbler.
+// GetMgmtClasses returns all management classes in Cobbler.
 func (c *Client) GetMgmtClasses() ([]*MgmtClass, error) {
-	result, err := c.Call("get_mgmtclasses", "-1", c.Token)
-	if err != nil {
-		return nil, err
-	}
+	return c.mgmtClasses().GetAll()
+}
 
-	return convertRawMgmtClassList(result)
+// EachMgmtClass calls fn for every management class in Cobbler. The management classes are decoded one by one while the
+// response is read, thus only a single management class is held in memory at a time. The iteration stops at the first
+// error returned by fn, which is returned unchanged.
+func (c *Client) EachMgmtClass(fn func(*MgmtClass) error) error {
+	return c.mgmtClasses().Each(fn)
 }
 
-// GetMgmtClass returns a single mgmtclass obtained by its name.
+// GetMgmtClass returns a single management class obtained by its name.
 func (c *Client) GetMgmtClass(name string, flattened, resolved bool) (*MgmtClass, error) {
-	result, err := c.getConcreteItem("get_mgmtclass", name, flattened, resolved)
-	if err != nil {
-		return nil, err
-	}
-
-	mgmtclass, err := convertRawMgmtClass(name, result)
-	if err != nil {
-		return nil, err
-	}
-	mgmtclass.Meta = ItemMeta{
-		IsFlattened: flattened,
-		IsResolved:  resolved,
-	}
-	return mgmtclass, nil
+	return c.mgmtClasses().Get(name, flattened, resolved)
 }
 
-// CreateMgmtClass creates a mgmtclass.
+// CreateMgmtClass creates a management class.
 func (c *Client) CreateMgmtClass(mgmtclass MgmtClass) (*MgmtClass, error) {
-	// Make sure a mgmtclass with the same name does not already exist
-	if _, err := c.GetMgmtClass(mgmtclass.Name, false, false); err == nil {
-		return nil, fmt.Errorf("a MgmtClass with the name %s already exists", mgmtclass.Name)
-	}
-
-	result, err := c.Call("new_mgmtclass", c.Token)
-	if err != nil {
-		return nil, err
-	}
-	newID := result.(string)
-
-	item := reflect.ValueOf(&mgmtclass).Elem()
-	if err := c.updateCobblerFields("mgmtclass", item, newID); err != nil {
-		return nil, err
-	}
-
-	if err := c.SaveMgmtClass(newID, "new"); err != nil {
-		return nil, err
-	}
-
-	return c.GetMgmtClass(mgmtclass.Name, false, false)
+	return c.mgmtClasses().Create(mgmtclass)
 }
 
-// UpdateMgmtClass updates a single MgmtClass.
+// UpdateMgmtClass updates a single management class.
 func (c *Client) UpdateMgmtClass(mgmtclass *MgmtClass) error {
-	item := reflect.ValueOf(mgmtclass).Elem()
-	id, err := c.GetItemHandle("mgmtclass", mgmtclass.Name)
-	if err != nil {
-		return err
-	}
+	return c.mgmtClasses().Update(mgmtclass)
+}
 
-	if err := c.updateCobblerFields("mgmtclass", item, id); err != nil {
-		return err
-	}
+// EditMgmtClass starts an edit session for the management class with the given name. All modifications are saved at
+// once.
+func (c *Client) EditMgmtClass(name string) (*EditSession[MgmtClass], error) {
+	return c.mgmtClasses().Edit(name)
+}
 
-	if err := c.SaveMgmtClass(id, "bypass"); err != nil {
-		return err
-	}
+// SaveMgmtClass saves all changes performed via XML-RPC to disk on the server side.
+func (c *Client) SaveMgmtClass(objectId, editmode string) error {
+	return c.mgmtClasses().Save(objectId, editmode)
+}
+
+// CopyMgmtClass duplicates a management class on the server with a new name.
+func (c *Client) CopyMgmtClass(objectId, newName string) error {
+	return c.mgmtClasses().Copy(objectId, newName)
+}
 
-	return nil
+// RenameMgmtClass renames a management class with a given object id.
+func (c *Client) RenameMgmtClass(objectId, newName string) error {
+	return c.mgmtClasses().Rename(objectId, newName)
 }
 
-// DeleteMgmtClass deletes a single MgmtClass by its name.
+// DeleteMgmtClass deletes a single management class by its name.
 func (c *Client) DeleteMgmtClass(name string) error {
-	return c.DeleteMgmtClassRecursive(name, false)
+	return c.mgmtClasses().Delete(name, false)
 }
 
-// DeleteMgmtClassRecursive deletes a single MgmtClass by its name with the option to do so recursively.
+// DeleteMgmtClassRecursive deletes a single management class by its name with the option to do so recursively.
 func (c *Client) DeleteMgmtClassRecursive(name string, recursive bool) error {
-	_, err := c.Call("remove_mgmtclass", name, c.Token, recursive)
-	return err
+	return c.mgmtClasses().Delete(name, recursive)
 }
 
-// ListMgmtClassNames returns a list of all managementclass names currently available in Cobbler.
+// ListMgmtClassNames returns a list of all management class names currently available in Cobbler.
 func (c *Client) ListMgmtClassNames() ([]string, error) {
-	return c.GetItemNames("mgmtclass")
+	return c.mgmtClasses().ListNames()
 }
 
-// FindMgmtClass searches for one or more managementclasses by any of its attributes.
+// FindMgmtClass searches for one or more management classes by any of its attributes.
 func (c *Client) FindMgmtClass(criteria map[string]interface{}) ([]*MgmtClass, error) {
-	result, err := c.Call("find_mgmtclass", criteria, true, c.Token)
-	if err != nil {
-		return nil, err
-	}
-
-	return convertRawMgmtClassList(result)
+	return c.mgmtClasses().Find(criteria)
 }
 
-// FindMgmtClassNames searches for one or more managementclasses by any of its attributes.
+// FindMgmtClassNames searches for one or more management classes by any of its attributes.
 func (c *Client) FindMgmtClassNames(criteria map[string]interface{}) ([]string, error) {
-	resultUnmarshalled, err := c.Call("find_mgmtclass", criteria, false, c.Token)
-	return returnStringSlice(resultUnmarshalled, err)
-}
-
-// GetMgmtClassHandle gets the internal ID of a Cobbler item.
-func (c *Client) GetMgmtClassHandle(name string) (string, error) {
-	res, err := c.Call("get_mgmtclass_handle", name, c.Token)
-	return returnString(res, err)
+	return c.mgmtClasses().FindNames(criteria)
 }
 
-// CopyMgmtClass copies a given managementclass server side with a new name.
-func (c *Client) CopyMgmtClass(objectId, newName string) error {
-	_, err := c.Call("copy_mgmtclass", objectId, newName, c.Token)
-	return err
-}
-
-// RenameMgmtClass renames a managementclass with a given object id.
-func (c *Client) RenameMgmtClass(objectId, newName string) error {
-	_, err := c.Call("rename_mgmtclass", objectId, newName, c.Token)
-	return err
-}
-
-// GetMgmtClassesSince returns all managementclasses which were created after the specified date.
+// GetMgmtClassesSince returns all management classes which were modified after the specified date.
 func (c *Client) GetMgmtClassesSince(mtime time.Time) ([]*MgmtClass, error) {
-	result, err := c.Call("get_mgmtclasses_since", float64(mtime.Unix()))
-	if err != nil {
-		return nil, err
-	}
+	return c.mgmtClasses().Since(mtime)
+}
 
-	return convertRawMgmtClassList(result)
+// GetMgmtClassHandle gets the internal ID of a Cobbler item.
+func (c *Client) GetMgmtClassHandle(name string) (string, error) {
+	return c.mgmtClasses().Handle(name)
 }
 
 // GetMgmtClassAsRendered returns the datastructure after it has passed through Cobblers inheritance structure.
 func (c *Client) GetMgmtClassAsRendered(name string) (map[string]interface{}, error) {
-	result, err := c.Call("get_mgmtclass_as_rendered", name, c.Token)
-	if err != nil {
-		return nil, err
-	}
-	return result.(map[string]interface{}), err
-}
-
-// SaveMgmtClass saves all changes performed via XML-RPC to disk on the server side.
-func (c *Client) SaveMgmtClass(objectId, editmode string) error {
-	_, err := c.Call("save_mgmtclass", objectId, c.Token, editmode)
-	return err
+	return c.mgmtClasses().AsRendered(name)
 }
@@ -39,7 +39,7 @@ func TestGetMgmtclasses(t *testing.T) {
 func TestGetMgmtclass(t *testing.T) {
 	// Arrange
 	c := createStubHTTPClientSingle(t, "get-mgmtclass")
-	c.CachedVersion = CobblerVersion{3, 3, 2}
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
 
 	// Act
 	mgmtclass, err := c.GetMgmtClass("testmgmtclass", false, false)
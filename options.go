@@ -1,75 +1,127 @@
 package cobblerclient
 
+import (
+	"reflect"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
 // BuildisoOptions is a struct which describes the options one can set for the buildiso action of Cobbler.
 type BuildisoOptions struct {
-	Iso           string   `mapstructure:"iso"`
-	Profiles      []string `mapstructure:"profiles"`
-	Systems       []string `mapstructure:"systems"`
-	BuildisoDir   string   `mapstructure:"buildisodir"`
-	Distro        string   `mapstructure:"distro"`
-	Standalone    bool     `mapstructure:"standalone"`
-	Airgapped     bool     `mapstructure:"airgapped"`
-	Source        string   `mapstructure:"source"`
-	ExcludeDns    bool     `mapstructure:"exclude_dns"`
-	XorrisofsOpts string   `mapstructure:"xorrisofs_opts"`
+	Iso           string   `mapstructure:"iso,omitempty"`
+	Profiles      []string `mapstructure:"profiles,omitempty"`
+	Systems       []string `mapstructure:"systems,omitempty"`
+	BuildisoDir   string   `mapstructure:"buildisodir,omitempty"`
+	Distro        string   `mapstructure:"distro,omitempty"`
+	Standalone    bool     `mapstructure:"standalone,omitempty"`
+	Airgapped     bool     `mapstructure:"airgapped,omitempty"`
+	Source        string   `mapstructure:"source,omitempty"`
+	ExcludeDns    bool     `mapstructure:"exclude_dns,omitempty"`
+	XorrisofsOpts string   `mapstructure:"xorrisofs_opts,omitempty"`
 }
 
 // AclSetupOptions is a struct which describes the options one can set for the actlsetup action of Cobbler.
 type AclSetupOptions struct {
-	AddUser     string `mapstructure:"adduser"`
-	AddGroup    string `mapstructure:"addgroup"`
-	RemoveUser  string `mapstructure:"removeuser"`
-	RemoveGroup string `mapstructure:"removegroup"`
+	AddUser     string `mapstructure:"adduser,omitempty"`
+	AddGroup    string `mapstructure:"addgroup,omitempty"`
+	RemoveUser  string `mapstructure:"removeuser,omitempty"`
+	RemoveGroup string `mapstructure:"removegroup,omitempty"`
 }
 
 // ReplicateOptions is a struct which descibres the options one can set for the replicate action of Cobbler.
 type ReplicateOptions struct {
-	Master            string `mapstructure:"master"`
-	Port              string `mapstructure:"port"`
-	DistroPatterns    string `mapstructure:"distro_patterns"`
-	ProfilePatterns   string `mapstructure:"profile_patterns"`
-	SystemPatterns    string `mapstructure:"system_patterns"`
-	RepoPatterns      string `mapstructure:"repo_patterns"`
-	Imagepatterns     string `mapstructure:"image_patterns"`
-	MgmtclassPatterns string `mapstructure:"mgmtclass_patterns"`
-	PackagePatterns   string `mapstructure:"package_patterns"`
-	FilePatterns      string `mapstructure:"file_patterns"`
-	Prune             bool   `mapstructure:"prune"`
-	OmitData          bool   `mapstructure:"omit_data"`
-	SyncAll           bool   `mapstructure:"sync_all"`
-	UseSsl            bool   `mapstructure:"use_ssl"`
+	Master            string `mapstructure:"master,omitempty"`
+	Port              string `mapstructure:"port,omitempty"`
+	DistroPatterns    string `mapstructure:"distro_patterns,omitempty"`
+	ProfilePatterns   string `mapstructure:"profile_patterns,omitempty"`
+	SystemPatterns    string `mapstructure:"system_patterns,omitempty"`
+	RepoPatterns      string `mapstructure:"repo_patterns,omitempty"`
+	Imagepatterns     string `mapstructure:"image_patterns,omitempty"`
+	MgmtclassPatterns string `mapstructure:"mgmtclass_patterns,omitempty"`
+	PackagePatterns   string `mapstructure:"package_patterns,omitempty"`
+	FilePatterns      string `mapstructure:"file_patterns,omitempty"`
+	Prune             bool   `mapstructure:"prune,omitempty"`
+	OmitData          bool   `mapstructure:"omit_data,omitempty"`
+	SyncAll           bool   `mapstructure:"sync_all,omitempty"`
+	UseSsl            bool   `mapstructure:"use_ssl,omitempty"`
 }
 
+// BackgroundSyncOptions is a struct which describes the options one can set for the sync action of Cobbler.
 type BackgroundSyncOptions struct {
-	Dhcp    bool `mapstructure:"dhcp"`
-	Dns     bool `mapstructure:"dns"`
-	Verbose bool `mapstructure:"verbose"`
+	Dhcp    bool `mapstructure:"dhcp,omitempty"`
+	Dns     bool `mapstructure:"dns,omitempty"`
+	Verbose bool `mapstructure:"verbose,omitempty"`
 }
 
+// BackgroundSyncSystemsOptions is a struct which describes the options one can set for the syncsystems action of
+// Cobbler.
 type BackgroundSyncSystemsOptions struct {
 	Systems []string `mapstructure:"systems"`
-	Verbose bool     `mapstructure:"verbose"`
+	Verbose bool     `mapstructure:"verbose,omitempty"`
 }
 
+// BackgroundImportOptions is a struct which describes the options one can set for the import action of Cobbler.
 type BackgroundImportOptions struct {
 	Path            string `mapstructure:"path"`
 	Name            string `mapstructure:"name"`
-	AvailableAs     string `mapstructure:"available_as"`
-	AutoinstallFile string `mapstructure:"autoinstall_file"`
-	RsyncFlags      string `mapstructure:"rsync_flags"`
-	Arch            string `mapstructure:"arch"`
-	Breed           string `mapstructure:"breed"`
-	OsVersion       string `mapstructure:"os_version"`
+	AvailableAs     string `mapstructure:"available_as,omitempty"`
+	AutoinstallFile string `mapstructure:"autoinstall_file,omitempty"`
+	RsyncFlags      string `mapstructure:"rsync_flags,omitempty"`
+	Arch            string `mapstructure:"arch,omitempty"`
+	Breed           string `mapstructure:"breed,omitempty"`
+	OsVersion       string `mapstructure:"os_version,omitempty"`
 }
 
+// BackgroundReposyncOptions is a struct which describes the options one can set for the reposync action of Cobbler.
+// If Tries is zero, the default of the server is used.
 type BackgroundReposyncOptions struct {
-	Repos  []string `mapstructure:"repos"`
-	Only   string   `mapstructure:"only"`
-	Nofail bool     `mapstructure:"nofail"`
-	Tries  int      `mapstructure:"tries"`
+	Repos  []string `mapstructure:"repos,omitempty"`
+	Only   string   `mapstructure:"only,omitempty"`
+	Nofail bool     `mapstructure:"nofail,omitempty"`
+	Tries  int      `mapstructure:"tries,omitempty"`
 }
 
+// BackgroundPowerSystemOptions is a struct which describes the options one can set for the power action of Cobbler.
 type BackgroundPowerSystemOptions struct {
 	Systems []string `mapstructure:"systems"`
 	Power   string   `mapstructure:"power"`
 }
+
+// encodeArgs converts all arguments of a call that are structs with "mapstructure" tags into maps which use the wire
+// names of the fields, because the encoder of kolo/xmlrpc would use the Go field names instead. Options tagged with
+// "omitempty" are left out when they have their zero value, so the server applies its own default for them. All other
+// arguments are returned unmodified.
+func encodeArgs(args []interface{}) ([]interface{}, error) {
+	encoded := make([]interface{}, len(args))
+	for i, arg := range args {
+		if !hasWireNames(reflect.TypeOf(arg)) {
+			encoded[i] = arg
+			continue
+		}
+		var options map[string]interface{}
+		if err := mapstructure.Decode(arg, &options); err != nil {
+			return nil, err
+		}
+		encoded[i] = options
+	}
+	return encoded, nil
+}
+
+// hasWireNames checks if t is a struct, or a pointer to one, with at least one field that has a "mapstructure" tag.
+func hasWireNames(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if _, ok := t.Field(i).Tag.Lookup("mapstructure"); ok {
+			return true
+		}
+	}
+	return false
+}
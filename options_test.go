@@ -0,0 +1,52 @@
+package cobblerclient
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEncodeArgs(t *testing.T) {
+	// Arrange
+	now := time.Now()
+	args := []interface{}{
+		BackgroundReposyncOptions{Repos: []string{"testrepo"}, Nofail: true},
+		&BackgroundPowerSystemOptions{Power: "on"},
+		"securetoken99",
+		now,
+	}
+
+	// Act
+	encoded, err := encodeArgs(args)
+
+	// Assert
+	FailOnError(t, err)
+	expectedReposync := map[string]interface{}{
+		"repos":  []string{"testrepo"},
+		"nofail": true,
+	}
+	if !reflect.DeepEqual(encoded[0], expectedReposync) {
+		t.Errorf("%v expected; got %v", expectedReposync, encoded[0])
+	}
+	expectedPower := map[string]interface{}{
+		"systems": []string(nil),
+		"power":   "on",
+	}
+	if !reflect.DeepEqual(encoded[1], expectedPower) {
+		t.Errorf("%v expected; got %v", expectedPower, encoded[1])
+	}
+	if encoded[2] != "securetoken99" || encoded[3] != now {
+		t.Errorf("arguments without wire names must not be modified")
+	}
+}
+
+func TestEncodeArgsNil(t *testing.T) {
+	// Arrange & Act
+	encoded, err := encodeArgs([]interface{}{nil})
+
+	// Assert
+	FailOnError(t, err)
+	if len(encoded) != 1 || encoded[0] != nil {
+		t.Errorf("[<nil>] expected; got %v", encoded)
+	}
+}
@@ -16,11 +16,7 @@ limitations under the License.
 
 package cobblerclient
 
-import (
-	"fmt"
-	"reflect"
-	"time"
-)
+import "time"
 
 // Profile is a created profile.
 // Get the fields from cobbler/items/profile.py
@@ -54,8 +50,6 @@ type Profile struct {
 	VirtPath            string          `mapstructure:"virt_path"`
 	VirtRAM             Value[int]      `mapstructure:"virt_ram"`
 	VirtType            string          `mapstructure:"virt_type"`
-
-	Client
 }
 
 func NewProfile() Profile {
@@ -123,232 +117,112 @@ func NewProfile() Profile {
 	return profile
 }
 
-func convertRawProfile(name string, xmlrpcResult interface{}) (*Profile, error) {
-	var profile Profile
-
-	if xmlrpcResult == "~" {
-		return nil, fmt.Errorf("profile %s not found", name)
-	}
-
-	decodeResult, err := decodeCobblerItem(xmlrpcResult, &profile)
-	if err != nil {
-		return nil, err
+// prepareProfile ensures that a distro is set for a new profile and then sets other default values.
+func prepareProfile(profile *Profile) error {
+	if profile.Distro == "" {
+		return &ValidationError{What: "profile", Field: "distro", Message: "a profile must have a distro set"}
 	}
 
-	// Now clean the Value structs
-	decodedProfile := decodeResult.(*Profile)
-	err = sanitizeValueMapStruct(&decodedProfile.KernelOptions)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedProfile.KernelOptionsPost)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedProfile.AutoinstallMeta)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedProfile.FetchableFiles)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedProfile.BootFiles)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedProfile.TemplateFiles)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedProfile.MgmtParameters)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueSliceStruct(&decodedProfile.Owners)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueSliceStruct(&decodedProfile.MgmtClasses)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueSliceStruct(&decodedProfile.BootLoaders)
-	if err != nil {
-		return nil, err
+	if profile.VirtType == "" {
+		profile.VirtType = inherit
 	}
-	err = sanitizeValueSliceStruct(&decodedProfile.NameServers)
-	if err != nil {
-		return nil, err
+	if profile.VirtDiskDriver == "" {
+		profile.VirtDiskDriver = inherit
 	}
-	err = sanitizeValueSliceStruct(&decodedProfile.NameServersSearch)
-	return decodedProfile, nil
+	return nil
 }
 
-func convertRawProfilesList(xmlrpcResult interface{}) ([]*Profile, error) {
-	var profiles []*Profile
-
-	for _, p := range xmlrpcResult.([]interface{}) {
-		profile, err := convertRawProfile("unknown", p)
-		if err != nil {
-			return nil, err
-		}
-		profile.Meta = ItemMeta{
-			IsFlattened: false,
-			IsResolved:  false,
-		}
-		profiles = append(profiles, profile)
-	}
-
-	return profiles, nil
+// profiles returns the service for profiles.
+func (c *Client) profiles() *ItemService[Profile] {
+	s := NewItemService[Profile](c, "profile")
+	s.prepare = prepareProfile
+	return s
 }
 
 // GetProfiles returns all profiles in Cobbler.
 func (c *Client) GetProfiles() ([]*Profile, error) {
-	result, err := c.Call("get_profiles", "-1", c.Token)
-	if err != nil {
-		return nil, err
-	}
+	return c.profiles().GetAll()
+}
 
-	return convertRawProfilesList(result)
+// EachProfile calls fn for every profile in Cobbler. The profiles are decoded one by one while the response is read,
+// thus only a single profile is held in memory at a time. The iteration stops at the first error returned by fn, which
+// is returned unchanged.
+func (c *Client) EachProfile(fn func(*Profile) error) error {
+	return c.profiles().Each(fn)
 }
 
 // GetProfile returns a single profile obtained by its name.
 func (c *Client) GetProfile(name string, flattened, resolved bool) (*Profile, error) {
-	result, err := c.getConcreteItem("get_profile", name, flattened, resolved)
-
-	if err != nil {
-		return nil, err
-	}
-
-	profile, err := convertRawProfile(name, result)
-	if err != nil {
-		return nil, err
-	}
-	profile.Meta = ItemMeta{
-		IsFlattened: flattened,
-		IsResolved:  resolved,
-	}
-	return profile, nil
+	return c.profiles().Get(name, flattened, resolved)
 }
 
 // CreateProfile creates a profile.
 // It ensures that a Distro is set and then sets other default values.
 func (c *Client) CreateProfile(profile Profile) (*Profile, error) {
-	// Check if a profile with the same name already exists
-	if _, err := c.GetProfile(profile.Name, false, false); err == nil {
-		return nil, fmt.Errorf("a profile with the name %s already exists", profile.Name)
-	}
-
-	if profile.Distro == "" {
-		return nil, fmt.Errorf("a profile must have a distro set")
-	}
-
-	if profile.VirtType == "" {
-		profile.VirtType = inherit
-	}
-	if profile.VirtDiskDriver == "" {
-		profile.VirtDiskDriver = inherit
-	}
-
-	// To create a profile via the Cobbler API, first call new_profile to obtain an ID
-	result, err := c.Call("new_profile", c.Token)
-	if err != nil {
-		return nil, err
-	}
-	newID := result.(string)
-	// Set the value of all fields
-	item := reflect.ValueOf(&profile).Elem()
-	if err := c.updateCobblerFields("profile", item, newID); err != nil {
-		return nil, err
-	}
-
-	// Save the final profile
-	err = c.SaveProfile(newID, "bypass")
-	if err != nil {
-		return nil, err
-	}
-
-	// Return a clean copy of the profile
-	return c.GetProfile(profile.Name, false, false)
+	return c.profiles().Create(profile)
 }
 
 // UpdateProfile updates a single profile.
 func (c *Client) UpdateProfile(profile *Profile) error {
-	item := reflect.ValueOf(profile).Elem()
-	id, err := c.GetItemHandle("profile", profile.Name)
-	if err != nil {
-		return err
-	}
-
-	if err := c.updateCobblerFields("profile", item, id); err != nil {
-		return err
-	}
+	return c.profiles().Update(profile)
+}
 
-	return c.SaveProfile(id, "bypass")
+// EditProfile starts an edit session for the profile with the given name. All modifications are saved at once.
+func (c *Client) EditProfile(name string) (*EditSession[Profile], error) {
+	return c.profiles().Edit(name)
 }
 
 // SaveProfile saves all changes performed via XML-RPC to disk on the server side.
 func (c *Client) SaveProfile(objectId, editmode string) error {
-	_, err := c.Call("save_profile", objectId, c.Token, editmode)
-	return err
+	return c.profiles().Save(objectId, editmode)
 }
 
-// CopyProfile duplicates a given profile on the server with a new name.
+// CopyProfile duplicates a profile on the server with a new name.
 func (c *Client) CopyProfile(objectId, newName string) error {
-	_, err := c.Call("copy_profile", objectId, newName, c.Token)
-	return err
+	return c.profiles().Copy(objectId, newName)
+}
+
+// RenameProfile renames a profile with a given object id.
+func (c *Client) RenameProfile(objectId, newName string) error {
+	return c.profiles().Rename(objectId, newName)
 }
 
 // DeleteProfile deletes a single profile by its name.
 func (c *Client) DeleteProfile(name string) error {
-	return c.DeleteProfileRecursive(name, false)
+	return c.profiles().Delete(name, false)
 }
 
-// DeleteProfileRecursive deletes a single profile by its name.
+// DeleteProfileRecursive deletes a single profile by its name with the option to do so recursively.
 func (c *Client) DeleteProfileRecursive(name string, recursive bool) error {
-	_, err := c.Call("remove_profile", name, c.Token, recursive)
-	return err
+	return c.profiles().Delete(name, recursive)
 }
 
 // ListProfileNames returns a list of all profile names currently available in Cobbler.
 func (c *Client) ListProfileNames() ([]string, error) {
-	return c.GetItemNames("profile")
+	return c.profiles().ListNames()
 }
 
 // FindProfile searches for one or more profiles by any of its attributes.
 func (c *Client) FindProfile(criteria map[string]interface{}) ([]*Profile, error) {
-	result, err := c.Call("find_profile", criteria, true, c.Token)
-	if err != nil {
-		return nil, err
-	}
-	return convertRawProfilesList(result)
+	return c.profiles().Find(criteria)
 }
 
 // FindProfileNames searches for one or more profiles by any of its attributes.
 func (c *Client) FindProfileNames(criteria map[string]interface{}) ([]string, error) {
-	resultUnmarshalled, err := c.Call("find_profile", criteria, false, c.Token)
-	return returnStringSlice(resultUnmarshalled, err)
+	return c.profiles().FindNames(criteria)
 }
 
-// GetProfilesSince returns all profiles which were created after the specified date.
+// GetProfilesSince returns all profiles which were modified after the specified date.
 func (c *Client) GetProfilesSince(mtime time.Time) ([]*Profile, error) {
-	result, err := c.Call("get_profiles_since", float64(mtime.Unix()))
-	if err != nil {
-		return nil, err
-	}
-
-	return convertRawProfilesList(result)
-}
-
-// RenameProfile renames a profile with a given object id.
-func (c *Client) RenameProfile(objectId, newName string) error {
-	_, err := c.Call("rename_profile", objectId, newName, c.Token)
-	return err
+	return c.profiles().Since(mtime)
 }
 
 // GetProfileHandle gets the internal ID of a Cobbler item.
 func (c *Client) GetProfileHandle(name string) (string, error) {
-	res, err := c.Call("get_profile_handle", name, c.Token)
-	return returnString(res, err)
+	return c.profiles().Handle(name)
+}
+
+// GetProfileAsRendered returns the datastructure after it has passed through Cobblers inheritance structure.
+func (c *Client) GetProfileAsRendered(name string) (map[string]interface{}, error) {
+	return c.profiles().AsRendered(name)
 }
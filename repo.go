@@ -16,11 +16,7 @@ limitations under the License.
 
 package cobblerclient
 
-import (
-	"fmt"
-	"reflect"
-	"time"
-)
+import "time"
 
 // Repo is a created repo.
 // Get the fileds from cobbler/items/repo.py
@@ -41,7 +37,7 @@ type Repo struct {
 	MirrorLocally   bool              `mapstructure:"mirror_locally"`
 	MirrorType      string            `mapstructure:"mirror_type"`
 	Priority        int               `mapstructure:"priority"`
-	Proxy           Value[string]     `mapstructure:"proxy" cobbler:"newfield"`
+	Proxy           Value[string]     `mapstructure:"proxy"`
 	RsyncOpts       map[string]string `mapstructure:"rsyncopts"`
 	RpmList         []string          `mapstructure:"rpm_list"`
 	YumOpts         map[string]string `mapstructure:"yumopts"`
@@ -68,202 +64,94 @@ func NewRepo() Repo {
 	}
 }
 
-func convertRawRepo(name string, xmlrpcResult interface{}) (*Repo, error) {
-	var repo Repo
-
-	if xmlrpcResult == "~" {
-		return nil, fmt.Errorf("profile %s not found", name)
-	}
-
-	decodeResult, err := decodeCobblerItem(xmlrpcResult, &repo)
-	if err != nil {
-		return nil, err
-	}
-
-	// Now clean the Value structs
-	decodedRepo := decodeResult.(*Repo)
-	err = sanitizeValueMapStruct(&decodedRepo.KernelOptions)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedRepo.KernelOptionsPost)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedRepo.AutoinstallMeta)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedRepo.FetchableFiles)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedRepo.BootFiles)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedRepo.TemplateFiles)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&decodedRepo.MgmtParameters)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueSliceStruct(&decodedRepo.Owners)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueSliceStruct(&decodedRepo.MgmtClasses)
-	return decodedRepo, nil
-}
-
-func convertRawReposList(xmlrpcResult interface{}) ([]*Repo, error) {
-	var repos []*Repo
-
-	for _, r := range xmlrpcResult.([]interface{}) {
-		repo, err := convertRawRepo("unknown", r)
-		if err != nil {
-			return nil, err
-		}
-		repo.Meta = ItemMeta{
-			IsFlattened: false,
-			IsResolved:  false,
-		}
-		repos = append(repos, repo)
-	}
-
-	return repos, nil
+// repos returns the service for repos.
+func (c *Client) repos() *ItemService[Repo] {
+	return NewItemService[Repo](c, "repo")
 }
 
 // GetRepos returns all repos in Cobbler.
 func (c *Client) GetRepos() ([]*Repo, error) {
-	result, err := c.Call("get_repos", "-1", c.Token)
-	if err != nil {
-		return nil, err
-	}
+	return c.repos().GetAll()
+}
 
-	return convertRawReposList(result)
+// EachRepo calls fn for every repo in Cobbler. The repos are decoded one by one while the response is read, thus
+// only a single repo is held in memory at a time. The iteration stops at the first error returned by fn, which is
+// returned unchanged.
+func (c *Client) EachRepo(fn func(*Repo) error) error {
+	return c.repos().Each(fn)
 }
 
 // GetRepo returns a single repo obtained by its name.
 func (c *Client) GetRepo(name string, flattened, resolved bool) (*Repo, error) {
-	result, err := c.getConcreteItem("get_repo", name, flattened, resolved)
-	if err != nil {
-		return nil, err
-	}
-
-	repo, err := convertRawRepo(name, result)
-	if err != nil {
-		return nil, err
-	}
-	repo.Meta = ItemMeta{
-		IsFlattened: flattened,
-		IsResolved:  resolved,
-	}
-	return repo, nil
+	return c.repos().Get(name, flattened, resolved)
 }
 
 // CreateRepo creates a repo.
 func (c *Client) CreateRepo(repo Repo) (*Repo, error) {
-	// Make sure a repo with the same name does not already exist
-	if _, err := c.GetRepo(repo.Name, false, false); err == nil {
-		return nil, fmt.Errorf("a Repo with the name %s already exists", repo.Name)
-	}
-
-	result, err := c.Call("new_repo", c.Token)
-	if err != nil {
-		return nil, err
-	}
-	newID := result.(string)
-
-	item := reflect.ValueOf(&repo).Elem()
-	if err := c.updateCobblerFields("repo", item, newID); err != nil {
-		return nil, err
-	}
-
-	if err := c.SaveRepo(newID, "new"); err != nil {
-		return nil, err
-	}
-
-	return c.GetRepo(repo.Name, false, false)
+	return c.repos().Create(repo)
 }
 
 // UpdateRepo updates a single repo.
 func (c *Client) UpdateRepo(repo *Repo) error {
-	item := reflect.ValueOf(repo).Elem()
-	id, err := c.GetItemHandle("repo", repo.Name)
-	if err != nil {
-		return err
-	}
-
-	if err := c.updateCobblerFields("repo", item, id); err != nil {
-		return err
-	}
+	return c.repos().Update(repo)
+}
 
-	return c.SaveRepo(id, "bypass")
+// EditRepo starts an edit session for the repo with the given name. All modifications are saved at once.
+func (c *Client) EditRepo(name string) (*EditSession[Repo], error) {
+	return c.repos().Edit(name)
 }
 
 // SaveRepo saves all changes performed via XML-RPC to disk on the server side.
 func (c *Client) SaveRepo(objectId, editmode string) error {
-	_, err := c.Call("save_repo", objectId, c.Token, editmode)
-	return err
+	return c.repos().Save(objectId, editmode)
 }
 
-// CopyRepo duplicates a given repository on the server with a new name.
+// CopyRepo duplicates a repo on the server with a new name.
 func (c *Client) CopyRepo(objectId, newName string) error {
-	_, err := c.Call("copy_repo", objectId, newName, c.Token)
-	return err
+	return c.repos().Copy(objectId, newName)
+}
+
+// RenameRepo renames a repo with a given object id.
+func (c *Client) RenameRepo(objectId, newName string) error {
+	return c.repos().Rename(objectId, newName)
 }
 
-// DeleteRepo deletes a single Repo by its name.
+// DeleteRepo deletes a single repo by its name.
 func (c *Client) DeleteRepo(name string) error {
-	return c.DeleteRepoRecursive(name, false)
+	return c.repos().Delete(name, false)
 }
 
-// DeleteRepoRecursive deletes a single Repo by its name with the option to do so recursively.
+// DeleteRepoRecursive deletes a single repo by its name with the option to do so recursively.
 func (c *Client) DeleteRepoRecursive(name string, recursive bool) error {
-	_, err := c.Call("remove_repo", name, c.Token, recursive)
-	return err
+	return c.repos().Delete(name, recursive)
 }
 
-// ListRepoNames returns the names of all repositories that exist in Cobbler.
+// ListRepoNames returns a list of all repo names currently available in Cobbler.
 func (c *Client) ListRepoNames() ([]string, error) {
-	return c.GetItemNames("repo")
+	return c.repos().ListNames()
 }
 
-// FindRepo searches for one or more repositories by any of its attributes.
+// FindRepo searches for one or more repos by any of its attributes.
 func (c *Client) FindRepo(criteria map[string]interface{}) ([]*Repo, error) {
-	result, err := c.Call("find_repo", criteria, true, c.Token)
-	if err != nil {
-		return nil, err
-	}
-	return convertRawReposList(result)
+	return c.repos().Find(criteria)
 }
 
-// FindRepoNames searches for one or more repositories by any of its attributes.
+// FindRepoNames searches for one or more repos by any of its attributes.
 func (c *Client) FindRepoNames(criteria map[string]interface{}) ([]string, error) {
-	resultUnmarshalled, err := c.Call("find_repo", criteria, false, c.Token)
-	return returnStringSlice(resultUnmarshalled, err)
+	return c.repos().FindNames(criteria)
 }
 
-// GetReposSince returns all repositories which were created after the specified date.
+// GetReposSince returns all repos which were modified after the specified date.
 func (c *Client) GetReposSince(mtime time.Time) ([]*Repo, error) {
-	result, err := c.Call("get_repos_since", float64(mtime.Unix()))
-	if err != nil {
-		return nil, err
-	}
-	return convertRawReposList(result)
-}
-
-// RenameRepo renames a repository with a given object id.
-func (c *Client) RenameRepo(objectId, newName string) error {
-	_, err := c.Call("rename_repo", objectId, newName, c.Token)
-	return err
+	return c.repos().Since(mtime)
 }
 
 // GetRepoHandle gets the internal ID of a Cobbler item.
 func (c *Client) GetRepoHandle(name string) (string, error) {
-	res, err := c.Call("get_repo_handle", name, c.Token)
-	return returnString(res, err)
+	return c.repos().Handle(name)
+}
+
+// GetRepoAsRendered returns the datastructure after it has passed through Cobblers inheritance structure.
+func (c *Client) GetRepoAsRendered(name string) (map[string]interface{}, error) {
+	return c.repos().AsRendered(name)
 }
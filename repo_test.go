@@ -38,7 +38,7 @@ func TestGetRepos(t *testing.T) {
 func TestGetRepo(t *testing.T) {
 	// Arrange
 	c := createStubHTTPClientSingle(t, "get-repo")
-	c.CachedVersion = CobblerVersion{3, 3, 2}
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
 
 	// Act
 	repo, err := c.GetRepo("rhel-7-x86_64", false, false)
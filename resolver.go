@@ -0,0 +1,362 @@
+package cobblerclient
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// settingsAliases maps attributes to the setting they are inherited from if the setting has neither the name of the
+// attribute nor the name with the "default_" prefix.
+var settingsAliases = map[string]string{
+	"owners": "default_ownership",
+	"proxy":  "proxy_url_int",
+}
+
+// Resolver computes the effective values of inherited attributes without asking the server, like it does for items
+// that are read with resolved set. The parents of the items have to be passed to [NewResolver]: the profile or image
+// of a system, the parent profile or distro of a profile. Attributes that no parent has are taken from the settings.
+//
+// The merge semantics of Cobbler are followed: maps like KernelOptions contain the entries of the parent overridden by
+// the own entries, and an entry whose key starts with "!" removes the entry of that key. All other values, including
+// lists, are replaced by the value of the parent if they are inherited. Attributes without a source, e.g. the boot
+// loaders of a distro, which the server derives from the signatures, stay inherited.
+//
+// The items must not be flattened. Like items that are read with resolved set, the resolved copies must not be used
+// for updates, since the inherited attributes would become own values. A Resolver doesn't modify the items and can be
+// used concurrently.
+type Resolver struct {
+	settings map[string]interface{}
+	distros  map[string]*Distro
+	profiles map[string]*Profile
+	images   map[string]*Image
+}
+
+// NewResolver creates a resolver for the given parents. settings may be nil, in which case attributes that are
+// inherited from the settings stay inherited.
+func NewResolver(settings *Settings, distros []*Distro, profiles []*Profile, images []*Image) *Resolver {
+	r := &Resolver{
+		settings: make(map[string]interface{}),
+		distros:  make(map[string]*Distro, len(distros)),
+		profiles: make(map[string]*Profile, len(profiles)),
+		images:   make(map[string]*Image, len(images)),
+	}
+	if settings != nil {
+		value := reflect.ValueOf(settings).Elem()
+		for i := 0; i < value.NumField(); i++ {
+			if name := attributeName(value.Type().Field(i)); name != "" {
+				r.settings[name] = value.Field(i).Interface()
+			}
+		}
+	}
+	for _, distro := range distros {
+		r.distros[distro.Name] = distro
+	}
+	for _, profile := range profiles {
+		r.profiles[profile.Name] = profile
+	}
+	for _, image := range images {
+		r.images[image.Name] = image
+	}
+	return r
+}
+
+// ResolveSystem returns a copy of the system with the effective values of all inherited attributes.
+func (r *Resolver) ResolveSystem(system *System) (*System, error) {
+	return resolveItem(r, "system", system)
+}
+
+// ResolveProfile returns a copy of the profile with the effective values of all inherited attributes.
+func (r *Resolver) ResolveProfile(profile *Profile) (*Profile, error) {
+	return resolveItem(r, "profile", profile)
+}
+
+// ResolveDistro returns a copy of the distro with the effective values of all inherited attributes.
+func (r *Resolver) ResolveDistro(distro *Distro) (*Distro, error) {
+	return resolveItem(r, "distro", distro)
+}
+
+// ResolveImage returns a copy of the image with the effective values of all inherited attributes.
+func (r *Resolver) ResolveImage(image *Image) (*Image, error) {
+	return resolveItem(r, "image", image)
+}
+
+// resolverNode is an item in the inheritance chain.
+type resolverNode struct {
+	what string
+	name string
+	// item is the struct of the item.
+	item reflect.Value
+}
+
+// resolveItem copies the item and replaces the inherited attributes of the copy by their effective values.
+func resolveItem[T any](r *Resolver, what string, item *T) (*T, error) {
+	resolved := copyItem(item)
+	node := resolverNode{what: what, name: itemOf(item).Name, item: reflect.ValueOf(item).Elem()}
+	var err error
+	walkAttributes(reflect.ValueOf(resolved).Elem(), func(name string, field reflect.Value) {
+		if err != nil {
+			return
+		}
+		var value interface{}
+		var ok bool
+		if value, ok, err = r.resolve(node, name, 0); err == nil && ok {
+			assignResolved(field, value)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	itemOf(resolved).Meta.IsResolved = true
+	return resolved, nil
+}
+
+// resolve returns the effective value of the attribute of the node. ok is false if the node doesn't have the attribute
+// or it is inherited but no source is known.
+func (r *Resolver) resolve(node resolverNode, attribute string, depth int) (value interface{}, ok bool, err error) {
+	field, found := attributeField(node.item, attribute)
+	if !found {
+		return nil, false, nil
+	}
+	own, inherited := ownValue(field)
+	if depth > len(r.profiles)+2 {
+		return nil, false, &ValidationError{
+			What:    node.what,
+			Field:   "parent",
+			Message: fmt.Sprintf("inheritance cycle at %s %s", node.what, node.name),
+		}
+	}
+	parent, err := r.parentOf(node)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if isMapAttribute(field) {
+		merged := make(map[string]interface{})
+		if parentValue, ok, err := r.inheritedValue(parent, attribute, depth, false); err != nil {
+			return nil, false, err
+		} else if ok {
+			mergeMap(merged, parentValue)
+		}
+		if !inherited {
+			mergeMap(merged, own)
+		}
+		annihilate(merged)
+		return merged, true, nil
+	}
+
+	if !inherited {
+		return own, true, nil
+	}
+	return r.inheritedValue(parent, attribute, depth, true)
+}
+
+// inheritedValue returns the value of the attribute that is passed down by parent, or by the settings if parent is
+// nil or doesn't have the attribute. Only map attributes are looked up in the settings under their name alone.
+func (r *Resolver) inheritedValue(parent *resolverNode, attribute string, depth int, withDefaults bool) (
+	interface{}, bool, error) {
+	if parent != nil {
+		if _, found := attributeField(parent.item, attribute); found {
+			return r.resolve(*parent, attribute, depth+1)
+		}
+	}
+	names := []string{attribute}
+	if withDefaults {
+		names = append(names, "default_"+attribute, settingsAliases[attribute])
+	}
+	for _, name := range names {
+		if value, ok := r.settings[name]; ok {
+			return value, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// parentOf returns the item the node inherits from or nil if it inherits from the settings.
+func (r *Resolver) parentOf(node resolverNode) (*resolverNode, error) {
+	switch node.what {
+	case "system":
+		system := node.item.Addr().Interface().(*System)
+		if system.Profile != "" && system.Profile != none {
+			return r.profileNode(system.Profile)
+		}
+		if system.Image != "" && system.Image != none {
+			image, ok := r.images[system.Image]
+			if !ok {
+				return nil, notFoundError("image", system.Image)
+			}
+			return &resolverNode{what: "image", name: image.Name, item: reflect.ValueOf(image).Elem()}, nil
+		}
+	case "profile":
+		profile := node.item.Addr().Interface().(*Profile)
+		if profile.Parent != "" {
+			return r.profileNode(profile.Parent)
+		}
+		if profile.Distro != "" {
+			distro, ok := r.distros[profile.Distro]
+			if !ok {
+				return nil, notFoundError("distro", profile.Distro)
+			}
+			return &resolverNode{what: "distro", name: distro.Name, item: reflect.ValueOf(distro).Elem()}, nil
+		}
+	}
+	return nil, nil
+}
+
+// profileNode returns the node of the profile with the given name.
+func (r *Resolver) profileNode(name string) (*resolverNode, error) {
+	profile, ok := r.profiles[name]
+	if !ok {
+		return nil, notFoundError("profile", name)
+	}
+	return &resolverNode{what: "profile", name: profile.Name, item: reflect.ValueOf(profile).Elem()}, nil
+}
+
+// walkAttributes calls fn for all attributes of an item struct that may be inherited: the [Value] attributes and the
+// strings.
+func walkAttributes(item reflect.Value, fn func(name string, field reflect.Value)) {
+	itemType := item.Type()
+	for i := 0; i < item.NumField(); i++ {
+		field := item.Field(i)
+		structField := itemType.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+		if structField.Anonymous && field.Kind() == reflect.Struct {
+			walkAttributes(field, fn)
+			continue
+		}
+		name := attributeName(structField)
+		if name != "" && (isValueType(field.Type()) || field.Kind() == reflect.String) {
+			fn(name, field)
+		}
+	}
+}
+
+// attributeField returns the field of an item struct for the attribute with the given name.
+func attributeField(item reflect.Value, attribute string) (reflect.Value, bool) {
+	itemType := item.Type()
+	for i := 0; i < item.NumField(); i++ {
+		structField := itemType.Field(i)
+		if structField.Anonymous && item.Field(i).Kind() == reflect.Struct {
+			if field, found := attributeField(item.Field(i), attribute); found {
+				return field, true
+			}
+			continue
+		}
+		if attributeName(structField) == attribute {
+			return item.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// attributeName returns the name of the attribute of a struct field from its mapstructure tag.
+func attributeName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+	return name
+}
+
+// isValueType checks if t is an instance of [Value].
+func isValueType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.PkgPath() == reflect.TypeOf(Item{}).PkgPath() &&
+		strings.HasPrefix(t.Name(), "Value[")
+}
+
+// isMapAttribute checks if the attribute of field is merged with the one of the parent.
+func isMapAttribute(field reflect.Value) bool {
+	return isValueType(field.Type()) && field.FieldByName("Data").Kind() == reflect.Map
+}
+
+// ownValue returns the value of an attribute and if it is inherited.
+func ownValue(field reflect.Value) (value interface{}, inherited bool) {
+	if isValueType(field.Type()) {
+		return field.FieldByName("Data").Interface(), field.FieldByName("IsInherited").Bool()
+	}
+	if field.Kind() == reflect.String {
+		return field.String(), field.String() == inherit
+	}
+	return field.Interface(), false
+}
+
+// assignResolved sets the field to the resolved value. Values that can't be converted to the type of the field, like
+// the name servers of the settings for a profile that is stored as string, are left as they are.
+func assignResolved(field reflect.Value, value interface{}) {
+	target := field
+	if isValueType(field.Type()) {
+		target = field.FieldByName("Data")
+	}
+	converted, ok := convertResolved(reflect.ValueOf(value), target.Type())
+	if !ok {
+		return
+	}
+	target.Set(converted)
+	if isValueType(field.Type()) {
+		field.FieldByName("IsInherited").SetBool(false)
+		field.FieldByName("RawData").Set(reflect.ValueOf(deepCopy(converted).Interface()))
+	}
+}
+
+// convertResolved converts a resolved value to the type of the field it is assigned to. Maps and slices are copied.
+func convertResolved(value reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	if !value.IsValid() {
+		return reflect.Value{}, false
+	}
+	switch {
+	case value.Type().AssignableTo(t):
+		return deepCopy(value), true
+	case value.Kind() == reflect.Map && t.Kind() == reflect.Map && value.Type().Key() == t.Key():
+		converted := reflect.MakeMapWithSize(t, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			element, ok := convertResolved(iter.Value(), t.Elem())
+			if !ok {
+				return reflect.Value{}, false
+			}
+			converted.SetMapIndex(iter.Key(), element)
+		}
+		return converted, true
+	case value.Kind() == reflect.Slice && t.Kind() == reflect.Slice:
+		converted := reflect.MakeSlice(t, value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			element, ok := convertResolved(value.Index(i), t.Elem())
+			if !ok {
+				return reflect.Value{}, false
+			}
+			converted.Index(i).Set(element)
+		}
+		return converted, true
+	case value.Kind() == reflect.Interface:
+		return convertResolved(value.Elem(), t)
+	case isNumber(value.Kind()) && isNumber(t.Kind()):
+		return value.Convert(t), true
+	}
+	return reflect.Value{}, false
+}
+
+// isNumber checks if kind is an integer or floating point type.
+func isNumber(kind reflect.Kind) bool {
+	return (kind >= reflect.Int && kind <= reflect.Uint64) || kind == reflect.Float32 || kind == reflect.Float64
+}
+
+// mergeMap copies the entries of the map value into merged.
+func mergeMap(merged map[string]interface{}, value interface{}) {
+	source := reflect.ValueOf(value)
+	if source.Kind() != reflect.Map {
+		return
+	}
+	iter := source.MapRange()
+	for iter.Next() {
+		merged[fmt.Sprint(iter.Key().Interface())] = deepCopy(iter.Value()).Interface()
+	}
+}
+
+// annihilate removes the entries whose key starts with "!" together with the entry they name.
+func annihilate(merged map[string]interface{}) {
+	for key := range merged {
+		if strings.HasPrefix(key, "!") {
+			delete(merged, strings.TrimPrefix(key, "!"))
+			delete(merged, key)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package cobblerclient
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// inheritanceChain returns the settings, a distro, a profile with a sub-profile and an image for the resolver tests.
+func inheritanceChain() (*Settings, *Distro, []*Profile, *Image) {
+	settings := &Settings{
+		KernelOptions:      map[string]string{"audit": "0"},
+		DefaultNameServers: []string{"10.0.0.53"},
+		DefaultOwnership:   []string{"admin"},
+		DefaultVirtRam:     512,
+		ProxyUrlInternal:   "http://proxy.example.org:3128",
+	}
+
+	distro := NewDistro()
+	distro.Name = "centos7"
+	distro.KernelOptions = Value[map[string]interface{}]{Data: map[string]interface{}{"console": "ttyS0", "quiet": ""}}
+
+	base := NewProfile()
+	base.Name = "base"
+	base.Distro = distro.Name
+	base.KernelOptions = Value[map[string]interface{}]{Data: map[string]interface{}{"!quiet": "", "splash": ""}}
+	base.VirtRAM = Value[int]{Data: 2048}
+
+	web := NewProfile()
+	web.Name = "web"
+	web.Parent = base.Name
+	web.KernelOptions = Value[map[string]interface{}]{IsInherited: true}
+	web.VirtRAM = Value[int]{IsInherited: true}
+
+	image := NewImage()
+	image.Name = "appliance"
+	image.VirtRam = Value[int]{IsInherited: true}
+
+	return settings, &distro, []*Profile{&base, &web}, &image
+}
+
+func TestResolverSystem(t *testing.T) {
+	// Arrange
+	settings, distro, profiles, image := inheritanceChain()
+	r := NewResolver(settings, []*Distro{distro}, profiles, []*Image{image})
+	system := NewSystem()
+	system.Name = "www1"
+	system.Profile = "web"
+	system.KernelOptions = Value[map[string]interface{}]{Data: map[string]interface{}{"console": "ttyS1"}}
+	system.VirtRAM = Value[int]{IsInherited: true}
+	system.Proxy = inherit
+
+	// Act
+	resolved, err := r.ResolveSystem(&system)
+
+	// Assert
+	FailOnError(t, err)
+	expectedOptions := map[string]interface{}{"audit": "0", "console": "ttyS1", "splash": ""}
+	if !reflect.DeepEqual(resolved.KernelOptions.Data, expectedOptions) || resolved.KernelOptions.IsInherited {
+		t.Errorf("expected the kernel options %v, got %+v", expectedOptions, resolved.KernelOptions)
+	}
+	if resolved.VirtRAM.Data != 2048 || resolved.VirtRAM.IsInherited {
+		t.Errorf("expected the RAM of the base profile, got %+v", resolved.VirtRAM)
+	}
+	if !reflect.DeepEqual(resolved.Owners.Data, []string{"admin"}) {
+		t.Errorf("expected the default owners, got %+v", resolved.Owners)
+	}
+	if resolved.Proxy != "http://proxy.example.org:3128" {
+		t.Errorf("expected the internal proxy, got %q", resolved.Proxy)
+	}
+	if !resolved.Meta.IsResolved || system.Proxy != inherit || !system.VirtRAM.IsInherited {
+		t.Errorf("expected a resolved copy of the unmodified system")
+	}
+}
+
+func TestResolverProfile(t *testing.T) {
+	// Arrange
+	settings, distro, profiles, _ := inheritanceChain()
+	r := NewResolver(settings, []*Distro{distro}, profiles, nil)
+
+	// Act
+	resolved, err := r.ResolveProfile(profiles[1])
+
+	// Assert
+	FailOnError(t, err)
+	if !reflect.DeepEqual(resolved.NameServers.Data, []string{"10.0.0.53"}) {
+		t.Errorf("expected the default name servers, got %+v", resolved.NameServers)
+	}
+	if _, ok := resolved.KernelOptions.Data["quiet"]; ok {
+		t.Errorf("expected quiet to be removed, got %v", resolved.KernelOptions.Data)
+	}
+}
+
+func TestResolverImageSystem(t *testing.T) {
+	// Arrange
+	settings, _, _, image := inheritanceChain()
+	r := NewResolver(settings, nil, nil, []*Image{image})
+	system := NewSystem()
+	system.Name = "appliance1"
+	system.Image = image.Name
+	system.VirtRAM = Value[int]{IsInherited: true}
+
+	// Act
+	resolved, err := r.ResolveSystem(&system)
+
+	// Assert
+	FailOnError(t, err)
+	if resolved.VirtRAM.Data != 512 {
+		t.Errorf("expected the default RAM, got %+v", resolved.VirtRAM)
+	}
+}
+
+func TestResolverDistroWithoutSettings(t *testing.T) {
+	// Arrange
+	_, distro, _, _ := inheritanceChain()
+	r := NewResolver(nil, nil, nil, nil)
+
+	// Act
+	resolved, err := r.ResolveDistro(distro)
+
+	// Assert
+	FailOnError(t, err)
+	if !resolved.BootLoaders.IsInherited || resolved.RedhatManagementKey != inherit {
+		t.Errorf("expected attributes without a source to stay inherited, got %+v", resolved)
+	}
+}
+
+func TestResolverMissingParent(t *testing.T) {
+	// Arrange
+	r := NewResolver(nil, nil, nil, nil)
+	system := NewSystem()
+	system.Profile = "missing"
+
+	// Act
+	_, err := r.ResolveSystem(&system)
+
+	// Assert
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestResolverCycle(t *testing.T) {
+	// Arrange
+	first := NewProfile()
+	first.Name = "first"
+	first.Parent = "second"
+	second := NewProfile()
+	second.Name = "second"
+	second.Parent = "first"
+	r := NewResolver(nil, nil, []*Profile{&first, &second}, nil)
+
+	// Act
+	_, err := r.ResolveProfile(&first)
+
+	// Assert
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("expected ErrValidation for the inheritance cycle, got %v", err)
+	}
+}
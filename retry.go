@@ -0,0 +1,219 @@
+package cobblerclient
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy describes how the client retries calls that failed because of a transient transport problem, e.g.
+// while the Cobbler daemon is restarted. Read-only methods (see [IsReadOnlyMethod]) are retried by default. Methods
+// that modify data on the server are only retried if they are listed in SafeMethods.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts per call including the first one. Set it to 1 to disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the second attempt.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the delay grows with after each attempt.
+	Multiplier float64
+	// Jitter is the fraction (between 0 and 1) of each delay that is randomized to avoid synchronized retries of many
+	// clients.
+	Jitter float64
+	// Retryable decides if an error is transient. If nil, [IsRetryableError] is used.
+	Retryable func(error) bool
+	// SafeMethods lists XML-RPC methods that modify data on the server but can safely be sent more than once.
+	SafeMethods []string
+}
+
+// DefaultRetryPolicy returns the policy that is used for all fields of [ClientConfig.RetryPolicy] that are not set.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		Retryable:      IsRetryableError,
+	}
+}
+
+// withDefaults fills all unset fields of the policy with the values of [DefaultRetryPolicy].
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	defaults := DefaultRetryPolicy()
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaults.MaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = defaults.InitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaults.MaxBackoff
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = defaults.Multiplier
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		p.Jitter = defaults.Jitter
+	}
+	if p.Retryable == nil {
+		p.Retryable = defaults.Retryable
+	}
+	return p
+}
+
+// allowsRetry checks if the given XML-RPC method may be sent more than once.
+func (p RetryPolicy) allowsRetry(method string) bool {
+	return IsReadOnlyMethod(method) || stringInSlice(method, p.SafeMethods)
+}
+
+// backoff calculates the delay after the given (1-based) attempt.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(attempt-1))
+	if delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		delay -= delay * p.Jitter * rand.Float64()
+	}
+	return time.Duration(delay)
+}
+
+// readOnlyMethodPrefixes are the prefixes of XML-RPC methods that never modify data on the server.
+var readOnlyMethodPrefixes = []string{"get_", "find_", "has_", "is_", "read_", "generate_", "check_access"}
+
+// readOnlyMethods are XML-RPC methods without a common prefix that never modify data on the server.
+var readOnlyMethods = []string{"ping", "version", "extended_version", "last_modified_time", "token_check"}
+
+// IsReadOnlyMethod checks if the given XML-RPC method only reads data from the server.
+func IsReadOnlyMethod(method string) bool {
+	if stringInSlice(method, readOnlyMethods) {
+		return true
+	}
+	for _, prefix := range readOnlyMethodPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// HTTPStatusError is returned when the server answers with an HTTP error status instead of an XML-RPC response.
+type HTTPStatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *HTTPStatusError) Error() string {
+	if e.Status != "" {
+		return fmt.Sprintf("cobblerclient: unexpected HTTP status %s", e.Status)
+	}
+	return fmt.Sprintf("cobblerclient: unexpected HTTP status %d", e.StatusCode)
+}
+
+// IsRetryableError is the default classifier of [RetryPolicy]. It treats timeouts, failures to connect to or read
+// from the server, connections that were closed unexpectedly and the HTTP status codes 429, 502, 503 and 504 as
+// transient. Cancelled calls, failed TLS handshakes, unknown hosts and invalid URLs are never retried.
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if isTLSError(err) {
+		return false
+	}
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	// Name resolution fails with an *net.OpError for "dial" as well, but only a temporary failure may resolve.
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTemporary
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && (opErr.Op == "dial" || opErr.Op == "read")
+}
+
+// isTLSError checks if the server certificate was rejected or the server aborted the TLS handshake, e.g. because
+// the client certificate is missing. Both won't resolve by retrying the call.
+func isTLSError(err error) bool {
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	if errors.As(err, &unknownAuthority) || errors.As(err, &hostname) || errors.As(err, &invalid) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "remote error"
+}
+
+// roundTripWithRetry performs the HTTP exchange and retries it according to the [RetryPolicy] of the client. Once the
+// retries are exhausted, read-only calls fail over to the next healthy endpoint. The URL of the endpoint that answered
+// is returned with the body.
+func (c *Client) roundTripWithRetry(ctx context.Context, method string, reqBody []byte) ([]byte, string, error) {
+	var body []byte
+	endpoint, err := c.failover(ctx, method, func(url string) error {
+		return c.retry(ctx, method, func() (err error) {
+			body, err = c.roundTrip(ctx, url, reqBody)
+			return err
+		})
+	})
+	return body, endpoint, err
+}
+
+// retry runs attempt until it succeeds or the [RetryPolicy] of the client doesn't allow another attempt.
+func (c *Client) retry(ctx context.Context, method string, attempt func() error) error {
+	policy := c.config.RetryPolicy.withDefaults()
+	maxAttempts := 1
+	if policy.allowsRetry(method) {
+		maxAttempts = policy.MaxAttempts
+	}
+
+	for i := 1; ; i++ {
+		err := attempt()
+		if err == nil {
+			return nil
+		}
+		if i >= maxAttempts || !policy.Retryable(err) {
+			return err
+		}
+		if sleepErr := sleepContext(ctx, policy.backoff(i)); sleepErr != nil {
+			return sleepErr
+		}
+	}
+}
+
+// sleepContext waits for the given duration or until the context is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
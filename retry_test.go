@@ -0,0 +1,209 @@
+package cobblerclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/cobbler/cobblerclient/cobblertest"
+)
+
+// timeoutError is a [net.Error] like the one of a request that timed out.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// flakyHTTPClient fails the first "failures" requests with the given error before passing requests to the stub.
+type flakyHTTPClient struct {
+	stub     *cobblertest.Stub
+	failures int
+	err      error
+	attempts int
+}
+
+func (f *flakyHTTPClient) Post(uri, bodyType string, req io.Reader) (*http.Response, error) {
+	f.attempts++
+	if f.attempts <= f.failures {
+		return nil, f.err
+	}
+	return f.stub.Post(uri, bodyType, req)
+}
+
+func createFlakyStubHTTPClient(t *testing.T, fixture string, failures int, policy RetryPolicy) (Client, *flakyHTTPClient) {
+	// The fixture is only reached if the client retries often enough.
+	stub := cobblertest.NewStub(t)
+	stub.ExpectFixture(fixture).Optional()
+	hc := &flakyHTTPClient{
+		stub:     stub,
+		failures: failures,
+		err:      syscall.ECONNREFUSED,
+	}
+	cfg := config
+	cfg.RetryPolicy = policy
+	c := NewClient(hc, cfg)
+	c.SetToken("securetoken99")
+	return c, hc
+}
+
+func TestRetryReadOnlyMethod(t *testing.T) {
+	// Arrange
+	c, hc := createFlakyStubHTTPClient(t, "ping", 2, RetryPolicy{InitialBackoff: time.Millisecond})
+
+	// Act
+	res, err := c.Ping()
+
+	// Assert
+	FailOnError(t, err)
+	if !res {
+		t.Fatalf("Expected ping to return true")
+	}
+	if hc.attempts != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", hc.attempts)
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	// Arrange
+	c, hc := createFlakyStubHTTPClient(t, "ping", 5, RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond})
+
+	// Act
+	_, err := c.Ping()
+
+	// Assert
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		t.Fatalf("Expected ECONNREFUSED, got %v", err)
+	}
+	if hc.attempts != 2 {
+		t.Fatalf("Expected 2 attempts, got %d", hc.attempts)
+	}
+}
+
+func TestRetryMutatingMethod(t *testing.T) {
+	// Arrange
+	c, hc := createFlakyStubHTTPClient(t, "new-system", 1, RetryPolicy{InitialBackoff: time.Millisecond})
+
+	// Act
+	_, err := c.Call("new_system", c.Token())
+
+	// Assert
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		t.Fatalf("Expected ECONNREFUSED, got %v", err)
+	}
+	if hc.attempts != 1 {
+		t.Fatalf("Expected a single attempt, got %d", hc.attempts)
+	}
+}
+
+func TestRetrySafeMethod(t *testing.T) {
+	// Arrange
+	c, hc := createFlakyStubHTTPClient(t, "new-system", 1, RetryPolicy{
+		InitialBackoff: time.Millisecond,
+		SafeMethods:    []string{"new_system"},
+	})
+
+	// Act
+	_, err := c.Call("new_system", c.Token())
+
+	// Assert
+	FailOnError(t, err)
+	if hc.attempts != 2 {
+		t.Fatalf("Expected 2 attempts, got %d", hc.attempts)
+	}
+}
+
+func TestRetryCancelledDuringBackoff(t *testing.T) {
+	// Arrange
+	c, hc := createFlakyStubHTTPClient(t, "ping", 1, RetryPolicy{InitialBackoff: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// Act
+	_, err := c.CallContext(ctx, "ping")
+
+	// Assert
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if hc.attempts != 1 {
+		t.Fatalf("Expected a single attempt, got %d", hc.attempts)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     3,
+	}.withDefaults()
+	p.Jitter = 0
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: 100 * time.Millisecond},
+		{attempt: 2, want: 300 * time.Millisecond},
+		{attempt: 3, want: 900 * time.Millisecond},
+		{attempt: 4, want: time.Second},
+	}
+	for _, tt := range tests {
+		if got := p.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "refused", err: syscall.ECONNREFUSED, want: true},
+		{name: "eof", err: io.ErrUnexpectedEOF, want: true},
+		{name: "unavailable", err: &HTTPStatusError{StatusCode: http.StatusServiceUnavailable}, want: true},
+		{name: "not-found", err: &HTTPStatusError{StatusCode: http.StatusNotFound}, want: false},
+		{name: "cancelled", err: context.Canceled, want: false},
+		{name: "other", err: errors.New("other"), want: false},
+		{name: "timeout", err: &url.Error{Op: "Post", URL: "http://cobbler", Err: timeoutError{}}, want: true},
+		{name: "dial", err: &url.Error{Op: "Post", URL: "http://cobbler", Err: &net.OpError{Op: "dial", Net: "tcp",
+			Err: errors.New("network is unreachable")}}, want: true},
+		{name: "unsupported-scheme", err: &url.Error{Op: "Post", URL: "ftp://cobbler",
+			Err: errors.New("unsupported protocol scheme \"ftp\"")}, want: false},
+		{name: "no-such-host", err: &url.Error{Op: "Post", URL: "http://cobbler", Err: &net.OpError{Op: "dial",
+			Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "cobbler", IsNotFound: true}}}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryableError(tt.err); got != tt.want {
+				t.Errorf("IsRetryableError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsReadOnlyMethod(t *testing.T) {
+	tests := map[string]bool{
+		"get_systems":           true,
+		"find_profile":          true,
+		"is_autoinstall_in_use": true,
+		"ping":                  true,
+		"modify_system":         false,
+		"save_system":           false,
+		"new_system":            false,
+	}
+	for method, want := range tests {
+		if got := IsReadOnlyMethod(method); got != want {
+			t.Errorf("IsReadOnlyMethod(%s) = %v, want %v", method, got, want)
+		}
+	}
+}
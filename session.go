@@ -0,0 +1,195 @@
+package cobblerclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultTokenCheckInterval is the interval used for the proactive token validation in case
+// [ClientConfig.AutoRelogin] is enabled and [ClientConfig.TokenCheckInterval] is zero.
+const DefaultTokenCheckInterval = time.Minute
+
+// session holds the state that is shared between all copies of a [Client] created from the same [NewClient] call. The
+// copies hold a pointer to the same session, thus a new token or version is visible to all of them. All methods of the
+// session are safe for concurrent use.
+type session struct {
+	// loginMu serializes token checks and logins, so only a single login is performed when the token needs to be
+	// renewed.
+	loginMu sync.Mutex
+	// mu protects the fields below.
+	mu sync.RWMutex
+	// token is the token of the session as obtained via a login.
+	token string
+	// lastCheck is the point in time when the token was last known to be valid.
+	lastCheck time.Time
+	// version is the cached version of the server. The zero value means that it wasn't fetched yet.
+	version CobblerVersion
+	// multicallUnsupported is set to 1 once the server rejected a "system.multicall" request.
+	multicallUnsupported int32
+}
+
+func (s *session) getToken() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.token
+}
+
+// setToken replaces the token. checked is the point in time when the token was last known to be valid.
+func (s *session) setToken(token string, checked time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+	s.lastCheck = checked
+}
+
+func (s *session) sinceLastCheck() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return time.Since(s.lastCheck)
+}
+
+func (s *session) markChecked(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token == token {
+		s.lastCheck = time.Now()
+	}
+}
+
+func (s *session) getVersion() CobblerVersion {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.version
+}
+
+func (s *session) setVersion(version CobblerVersion) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version = version
+}
+
+// sessionExemptMethods are the XML-RPC methods that must never trigger an automatic login.
+var sessionExemptMethods = []string{"login", "logout", "token_check"}
+
+// usesSession returns true if the automatic session handling applies to the given call.
+func (c *Client) usesSession(method string) bool {
+	return c.config.AutoRelogin && c.session != nil && !stringInSlice(method, sessionExemptMethods)
+}
+
+// tokenCheckInterval returns the effective interval for the proactive token validation. A negative interval disables
+// the proactive check.
+func (c *Client) tokenCheckInterval() time.Duration {
+	if c.config.TokenCheckInterval == 0 {
+		return DefaultTokenCheckInterval
+	}
+	return c.config.TokenCheckInterval
+}
+
+// ensureSession proactively validates the token of the session with "token_check" once per check interval. In case
+// the token expired a new login is performed. Concurrent callers wait for the running check instead of logging in on
+// their own.
+func (c *Client) ensureSession(ctx context.Context) error {
+	token := c.Token()
+	if token == "" || c.tokenCheckInterval() < 0 {
+		return nil
+	}
+	c.session.loginMu.Lock()
+	defer c.session.loginMu.Unlock()
+
+	if c.Token() != token {
+		// Another caller renewed the session while we were waiting.
+		return nil
+	}
+	if c.session.sinceLastCheck() < c.tokenCheckInterval() {
+		return nil
+	}
+	res, _, err := c.invoke(ctx, "token_check", []interface{}{token}, nil)
+	valid, err := returnBool("token_check", res, err)
+	if err != nil && !errors.Is(err, ErrInvalidToken) {
+		return err
+	}
+	if valid {
+		c.session.markChecked(token)
+		return nil
+	}
+	return c.loginLocked(ctx)
+}
+
+// renewSession is called after the server rejected staleToken. A new login is only performed in case no other caller
+// renewed the session in the meantime.
+func (c *Client) renewSession(ctx context.Context, staleToken string) error {
+	c.session.loginMu.Lock()
+	defer c.session.loginMu.Unlock()
+
+	if token := c.Token(); token != "" && token != staleToken {
+		return nil
+	}
+	return c.loginLocked(ctx)
+}
+
+// loginLocked performs the login and records the new token in the session. The caller must hold the login lock of the
+// session.
+func (c *Client) loginLocked(ctx context.Context) error {
+	res, _, err := c.invoke(ctx, "login", []interface{}{c.config.Username, c.config.Password}, nil)
+	token, err := returnString("login", res, err)
+	if err != nil {
+		return err
+	}
+	c.session.setToken(token, time.Now())
+	return nil
+}
+
+// replaceToken returns a copy of args where every argument that is equal to oldToken is replaced by newToken. The
+// parameters of nested calls, as used by "system.multicall", are replaced as well.
+func replaceToken(args []interface{}, oldToken, newToken string) []interface{} {
+	replaced := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch typedArg := arg.(type) {
+		case string:
+			if typedArg == oldToken {
+				replaced[i] = newToken
+			} else {
+				replaced[i] = arg
+			}
+		case []interface{}:
+			replaced[i] = replaceToken(typedArg, oldToken, newToken)
+		case map[string]interface{}:
+			if params, ok := typedArg["params"].([]interface{}); ok {
+				call := make(map[string]interface{}, len(typedArg))
+				for key, value := range typedArg {
+					call[key] = value
+				}
+				call["params"] = replaceToken(params, oldToken, newToken)
+				replaced[i] = call
+			} else {
+				replaced[i] = arg
+			}
+		default:
+			replaced[i] = arg
+		}
+	}
+	return replaced
+}
+
+// containsToken checks if token is one of the arguments of a call or of its nested calls.
+func containsToken(args []interface{}, token string) bool {
+	for _, arg := range args {
+		switch typedArg := arg.(type) {
+		case string:
+			if typedArg == token {
+				return true
+			}
+		case []interface{}:
+			if containsToken(typedArg, token) {
+				return true
+			}
+		case map[string]interface{}:
+			if params, ok := typedArg["params"].([]interface{}); ok && containsToken(params, token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package cobblerclient
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cobbler/cobblerclient/cobblertest"
+)
+
+const renewedToken = "sa/1EWr40BWU+Pq3VEOOpD4cQtxkeMuFUw=="
+
+func TestAutoReloginOnInvalidToken(t *testing.T) {
+	// Arrange
+	c := createStubHTTPClient(t, []string{
+		"session-invalid-token",
+		"login",
+		"session-get-systems",
+	})
+	c.config.AutoRelogin = true
+	c.config.TokenCheckInterval = -1
+
+	// Act
+	systems, err := c.GetSystems()
+
+	// Assert
+	FailOnError(t, err)
+	if len(systems) != 1 {
+		t.Errorf("Wrong number of systems returned.")
+	}
+	if c.Token() != renewedToken {
+		t.Errorf(`"%s" expected; got "%s"`, renewedToken, c.Token())
+	}
+}
+
+func TestAutoReloginDisabled(t *testing.T) {
+	// Arrange
+	c := createStubHTTPClientSingle(t, "session-invalid-token")
+
+	// Act
+	_, err := c.GetSystems()
+
+	// Assert
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected invalid token fault, got %v", err)
+	}
+}
+
+func TestAutoReloginProactiveTokenCheck(t *testing.T) {
+	// Arrange
+	c := createStubHTTPClient(t, []string{
+		"session-token-check",
+		"login",
+		"session-get-systems",
+	})
+	c.config.AutoRelogin = true
+
+	// Act
+	systems, err := c.GetSystems()
+
+	// Assert
+	FailOnError(t, err)
+	if len(systems) != 1 {
+		t.Errorf("Wrong number of systems returned.")
+	}
+	if c.Token() != renewedToken {
+		t.Errorf(`"%s" expected; got "%s"`, renewedToken, c.Token())
+	}
+}
+
+func TestAutoReloginSharedBetweenCopies(t *testing.T) {
+	// Arrange
+	c := createStubHTTPClient(t, []string{
+		"session-token-check-valid",
+		"get-systems",
+		"session-invalid-token",
+		"login",
+		"session-get-systems",
+		"session-get-systems",
+	})
+	c.config.AutoRelogin = true
+	c.config.TokenCheckInterval = 0
+	stale := c
+
+	// Act & Assert
+	_, err := c.GetSystems()
+	FailOnError(t, err)
+	c.session.lastCheck = c.session.lastCheck.Add(-2 * DefaultTokenCheckInterval)
+	c.config.TokenCheckInterval = -1
+	_, err = c.GetSystems()
+	FailOnError(t, err)
+	// The copy must use the renewed token without a second login.
+	_, err = stale.GetSystems()
+	FailOnError(t, err)
+	if stale.Token() != renewedToken {
+		t.Errorf(`"%s" expected; got "%s"`, renewedToken, stale.Token())
+	}
+}
+
+func TestSessionConcurrentRelogin(t *testing.T) {
+	// Arrange
+	var logins int32
+	hc := funcHTTPClient(func(call *cobblertest.Call) string {
+		switch call.Method {
+		case "login":
+			atomic.AddInt32(&logins, 1)
+			return xmlrpcParams("<string>" + renewedToken + "</string>")
+		case "get_systems":
+			if call.Params[len(call.Params)-1] != renewedToken {
+				return xmlrpcFault("&lt;class 'cobbler.cexceptions.CX'&gt;:'invalid token: expired'")
+			}
+		}
+		return xmlrpcParams("<array><data></data></array>")
+	})
+	cfg := config
+	cfg.AutoRelogin = true
+	cfg.TokenCheckInterval = -1
+	c := NewClient(hc, cfg)
+	c.SetToken("expired")
+
+	// Act
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(copied Client) {
+			defer wg.Done()
+			_, err := copied.GetSystems()
+			errs <- err
+		}(c)
+	}
+	wg.Wait()
+	close(errs)
+
+	// Assert
+	for err := range errs {
+		FailOnError(t, err)
+	}
+	if logins != 1 {
+		t.Errorf("expected a single login, got %d", logins)
+	}
+	if c.Token() != renewedToken {
+		t.Errorf(`"%s" expected; got "%s"`, renewedToken, c.Token())
+	}
+}
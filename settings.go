@@ -195,10 +195,10 @@ func decodeCobblerSettings(raw interface{}, result interface{}) (interface{}, er
 // GetSettings returns the currently active settings.
 func (c *Client) GetSettings() (*Settings, error) {
 	var settings Settings
-	resultUnmarshalled, err := c.Call("get_settings", c.Token)
+	resultUnmarshalled, err := c.Call("get_settings", c.Token())
 
 	if resultUnmarshalled == "~" {
-		return nil, fmt.Errorf("settings not found")
+		return nil, fmt.Errorf("settings %w", ErrNotFound)
 	}
 
 	decodeResult, err := decodeCobblerSettings(resultUnmarshalled, &settings)
@@ -211,7 +211,7 @@ func (c *Client) GetSettings() (*Settings, error) {
 
 // ModifySetting modifies a settings if "allow_dynamic_settings" is turned on server side.
 func (c *Client) ModifySetting(name string, value interface{}) (int, error) {
-	result, err := c.Call("modify_setting", name, value, c.Token)
+	result, err := c.Call("modify_setting", name, value, c.Token())
 	if err != nil {
 		return -1, err
 	} else {
@@ -84,7 +84,7 @@ func decodeCobblerSignatures(raw interface{}, result interface{}) (interface{},
 // GetSignatures retrieves the complete signatures that are loaded by Cobbler.
 func (c *Client) GetSignatures() (*DistroSignatures, error) {
 	var distroSignatures DistroSignatures
-	rawSignatures, err := c.Call("get_signatures", c.Token)
+	rawSignatures, err := c.Call("get_signatures", c.Token())
 	if err != nil {
 		return &distroSignatures, err
 	}
@@ -97,30 +97,30 @@ func (c *Client) GetSignatures() (*DistroSignatures, error) {
 
 // GetValidBreeds retrieves all valid OS breeds that a distro can have.
 func (c *Client) GetValidBreeds() ([]string, error) {
-	resultUnmarshalled, err := c.Call("get_valid_breeds", c.Token)
-	return returnStringSlice(resultUnmarshalled, err)
+	resultUnmarshalled, err := c.Call("get_valid_breeds", c.Token())
+	return returnStringSlice("get_valid_breeds", resultUnmarshalled, err)
 }
 
 // GetValidOsVersionsForBreed retrieves all valid OS versions for a given breed.
 func (c *Client) GetValidOsVersionsForBreed(breed string) ([]string, error) {
-	resultUnmarshalled, err := c.Call("get_valid_os_versions_for_breed", breed, c.Token)
-	return returnStringSlice(resultUnmarshalled, err)
+	resultUnmarshalled, err := c.Call("get_valid_os_versions_for_breed", breed, c.Token())
+	return returnStringSlice("get_valid_os_versions_for_breed", resultUnmarshalled, err)
 }
 
 // GetValidOsVersions retrieves all valid OS versions that a distro can have.
 func (c *Client) GetValidOsVersions() ([]string, error) {
-	resultUnmarshalled, err := c.Call("get_valid_os_versions", c.Token)
-	return returnStringSlice(resultUnmarshalled, err)
+	resultUnmarshalled, err := c.Call("get_valid_os_versions", c.Token())
+	return returnStringSlice("get_valid_os_versions", resultUnmarshalled, err)
 }
 
 // GetValidArchs retrieves all valid architectures that Cobbler is offering.
 func (c *Client) GetValidArchs() ([]string, error) {
-	resultUnmarshalled, err := c.Call("get_valid_archs", c.Token)
-	return returnStringSlice(resultUnmarshalled, err)
+	resultUnmarshalled, err := c.Call("get_valid_archs", c.Token())
+	return returnStringSlice("get_valid_archs", resultUnmarshalled, err)
 }
 
 // BackgroundSignatureUpdate runs a signatures update in the background on the server.
 func (c *Client) BackgroundSignatureUpdate() (string, error) {
-	res, err := c.Call("background_signature_update", map[string]string{}, c.Token)
-	return returnString(res, err)
+	res, err := c.Call("background_signature_update", map[string]string{}, c.Token())
+	return returnString("background_signature_update", res, err)
 }
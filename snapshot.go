@@ -0,0 +1,101 @@
+package cobblerclient
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// fieldSnapshot maps the keys of field updates to the values the server is known to have. The values are deep copies,
+// thus modifications of the maps and slices of an item don't show up in its snapshot.
+type fieldSnapshot map[string]interface{}
+
+// interfaceKeyPrefix starts the snapshot keys of network interfaces, which are followed by the interface name.
+const interfaceKeyPrefix = "modify_interface/"
+
+// key identifies the update in a snapshot. Since all interfaces are set with "modify_interface", they are told apart
+// by their name.
+func (u fieldUpdate) key() string {
+	if u.field == "modify_interface" {
+		return interfaceKeyPrefix + u.nic
+	}
+	return u.field
+}
+
+// newFieldSnapshot records the values of the updates.
+func newFieldSnapshot(updates []fieldUpdate) fieldSnapshot {
+	snapshot := make(fieldSnapshot, len(updates))
+	for _, update := range updates {
+		snapshot.set(update)
+	}
+	return snapshot
+}
+
+// set records the value of a single update.
+func (s fieldSnapshot) set(update fieldUpdate) {
+	if update.value == nil {
+		s[update.key()] = nil
+		return
+	}
+	s[update.key()] = deepCopy(reflect.ValueOf(update.value)).Interface()
+}
+
+// with returns a copy of the snapshot that records the value of the update. Snapshots are shared between the copies of
+// an item, thus they are never modified once the item was returned. A nil snapshot stays nil.
+func (s fieldSnapshot) with(update fieldUpdate) fieldSnapshot {
+	if s == nil {
+		return nil
+	}
+	copied := s.clone()
+	copied.set(update)
+	return copied
+}
+
+// without returns a copy of the snapshot without the given key. A nil snapshot stays nil.
+func (s fieldSnapshot) without(key string) fieldSnapshot {
+	if s == nil {
+		return nil
+	}
+	copied := s.clone()
+	delete(copied, key)
+	return copied
+}
+
+// clone returns a shallow copy of the snapshot. The values themselves are never modified.
+func (s fieldSnapshot) clone() fieldSnapshot {
+	copied := make(fieldSnapshot, len(s))
+	for key, value := range s {
+		copied[key] = value
+	}
+	return copied
+}
+
+// snapshotItem records the state of an item that was read from the server.
+func snapshotItem(what string, item reflect.Value) fieldSnapshot {
+	return newFieldSnapshot(collectFieldUpdates(what, item))
+}
+
+// changedFieldUpdates returns the updates whose value differs from the snapshot in their original order. Interfaces
+// that are part of the snapshot but not of the updates were removed from the item, thus they are deleted afterwards.
+func changedFieldUpdates(updates []fieldUpdate, snapshot fieldSnapshot) []fieldUpdate {
+	var changed []fieldUpdate
+	present := make(map[string]bool, len(updates))
+	for _, update := range updates {
+		present[update.key()] = true
+		if original, ok := snapshot[update.key()]; ok && reflect.DeepEqual(original, update.value) {
+			continue
+		}
+		changed = append(changed, update)
+	}
+	var removed []string
+	for key := range snapshot {
+		if nic := strings.TrimPrefix(key, interfaceKeyPrefix); nic != key && !present[key] {
+			removed = append(removed, nic)
+		}
+	}
+	sort.Strings(removed)
+	for _, nic := range removed {
+		changed = append(changed, fieldUpdate{field: "delete_interface", value: nic, nic: nic})
+	}
+	return changed
+}
@@ -0,0 +1,65 @@
+package cobblerclient
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChangedFieldUpdates(t *testing.T) {
+	// Arrange
+	system := NewSystem()
+	system.Name = "test"
+	system.Interfaces = Interfaces{"eth0": NewInterface(), "eth1": NewInterface(), "eth2": NewInterface()}
+	snapshot := snapshotItem("system", reflect.ValueOf(&system).Elem())
+	system.Hostname = "test.example.org"
+	system.KernelOptions = Value[map[string]interface{}]{Data: map[string]interface{}{"console": "ttyS0"}}
+	system.Interfaces["eth1"] = Interface{MACAddress: "aa:bb:cc:dd:ee:ff"}
+	delete(system.Interfaces, "eth2")
+
+	// Act
+	changed := changedFieldUpdates(collectFieldUpdates("system", reflect.ValueOf(&system).Elem()), snapshot)
+
+	// Assert
+	var keys []string
+	for _, update := range changed {
+		keys = append(keys, update.field+":"+update.nic)
+	}
+	expected := []string{"kernel_options:", "modify_interface:eth1", "hostname:", "delete_interface:eth2"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected %v, got %v", expected, keys)
+	}
+	if changed[3].value != "eth2" {
+		t.Errorf("expected the interface name as value, got %v", changed[3].value)
+	}
+}
+
+func TestFieldSnapshotIsCopied(t *testing.T) {
+	// Arrange
+	options := map[string]interface{}{"console": "ttyS0"}
+	snapshot := newFieldSnapshot([]fieldUpdate{{field: "kernel_options", value: options}})
+
+	// Act
+	options["quiet"] = ""
+	extended := snapshot.with(fieldUpdate{field: "comment", value: "new"})
+
+	// Assert
+	if len(snapshot["kernel_options"].(map[string]interface{})) != 1 {
+		t.Errorf("expected the snapshot not to see modifications of the item")
+	}
+	if _, ok := snapshot["comment"]; ok || extended["comment"] != "new" {
+		t.Errorf("expected with to return a modified copy")
+	}
+}
+
+func TestFieldSnapshotNil(t *testing.T) {
+	// Arrange
+	var snapshot fieldSnapshot
+
+	// Act
+	extended := snapshot.with(fieldUpdate{field: "comment", value: "new"}).without("comment")
+
+	// Assert
+	if extended != nil {
+		t.Errorf("expected an untracked item to stay untracked, got %v", extended)
+	}
+}
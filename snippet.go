@@ -26,7 +26,7 @@ type Snippet struct {
 // Takes a Snippet struct as input
 // Returns true/false and error if creation failed.
 func (c *Client) CreateSnippet(s Snippet) error {
-	_, err := c.Call("write_autoinstall_snippet", s.Name, s.Body, c.Token)
+	_, err := c.Call("write_autoinstall_snippet", s.Name, s.Body, c.Token())
 	return err
 }
 
@@ -34,15 +34,19 @@ func (c *Client) CreateSnippet(s Snippet) error {
 // Takes a snippet file name as input.
 // Returns *Snippet and error if read failed.
 func (c *Client) GetSnippet(name string) (*Snippet, error) {
-	result, err := c.Call("read_autoinstall_snippet", name, c.Token)
+	result, err := c.Call("read_autoinstall_snippet", name, c.Token())
 
+	if err != nil {
+		return nil, err
+	}
+	body, err := asString("read_autoinstall_snippet", result)
 	if err != nil {
 		return nil, err
 	}
 
 	snippet := Snippet{
 		Name: name,
-		Body: result.(string),
+		Body: body,
 	}
 
 	return &snippet, nil
@@ -52,6 +56,6 @@ func (c *Client) GetSnippet(name string) (*Snippet, error) {
 // Takes a snippet file name as input.
 // Returns error if delete failed.
 func (c *Client) DeleteSnippet(name string) error {
-	_, err := c.Call("remove_autoinstall_snippet", name, c.Token)
+	_, err := c.Call("remove_autoinstall_snippet", name, c.Token())
 	return err
 }
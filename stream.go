@@ -0,0 +1,451 @@
+package cobblerclient
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kolo/xmlrpc"
+)
+
+// xmlrpcTimeLayouts are the formats of "dateTime.iso8601" values that are accepted by [xmlrpc.Response] as well.
+var xmlrpcTimeLayouts = []string{
+	"20060102T15:04:05",
+	"20060102T15:04:05Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05Z07:00",
+}
+
+// errInvalidXMLRPC is returned if the response body is well-formed XML but not a valid XML-RPC response.
+var errInvalidXMLRPC = errors.New("invalid XML-RPC response")
+
+// stream calls a method that returns an array and passes every element of the array to fn while the response is still
+// being read. In contrast to [Client.Call], neither the response body nor the complete result is kept in memory. If
+// fn returns an error, the rest of the response is discarded and the error is returned unchanged.
+//
+// The call passes through all interceptors. An interceptor that answers the call itself returns the complete array,
+// whose elements are passed to fn afterwards.
+func (c *Client) stream(method string, fn func(interface{}) error, args ...interface{}) error {
+	result, err := c.callContext(c.Context(), method, args, fn)
+	if err != nil || result == nil {
+		return err
+	}
+	rawItems, err := asSlice(method, result)
+	if err != nil {
+		return err
+	}
+	for _, rawItem := range rawItems {
+		if err := fn(rawItem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eachItem streams the result of a collection getter. Every element is converted with convert before it is passed to
+// fn, thus only a single raw item is held in memory at a time.
+func eachItem[T any](c *Client, method string, convert func(string, interface{}) (*T, error), fn func(*T) error,
+	args ...interface{}) error {
+	return c.stream(method, func(raw interface{}) error {
+		item, err := convert("unknown", raw)
+		if err != nil {
+			return err
+		}
+		return fn(item)
+	}, args...)
+}
+
+// collectItems returns an each-function as a slice.
+func collectItems[T any](each func(func(*T) error) error) ([]*T, error) {
+	var items []*T
+	err := each(func(item *T) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// invokeStream sends the encoded request and decodes the response with a [responseDecoder]. Only sending the request
+// is retried or failed over, since the elements that were already passed to sink can't be taken back.
+func (c *Client) invokeStream(ctx context.Context, method string, reqBody []byte, sink func(interface{}) error) (
+	endpoint string, err error) {
+	var res *http.Response
+	endpoint, err = c.failover(ctx, method, func(url string) error {
+		return c.retry(ctx, method, func() (err error) {
+			res, err = c.send(ctx, url, reqBody)
+			return err
+		})
+	})
+	if err != nil {
+		return endpoint, err
+	}
+
+	defer func() {
+		if closeErr := res.Body.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	return endpoint, newResponseDecoder(res.Body).decodeArray(method, sink)
+}
+
+// responseDecoder reads an XML-RPC response token by token. Values are decoded into the same types as
+// [xmlrpc.Response.Unmarshal] uses for an interface{}, thus the result can be passed to the same converters.
+type responseDecoder struct {
+	*xml.Decoder
+	// names interns the member names of structs, since all items of a collection share the same keys.
+	names map[string]string
+}
+
+func newResponseDecoder(r io.Reader) *responseDecoder {
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = xmlrpc.CharsetReader
+	return &responseDecoder{Decoder: decoder, names: make(map[string]string)}
+}
+
+// decodeArray decodes a response whose single parameter is an array and passes the elements to sink one by one. A
+// fault is returned as [CobblerFault] and any other parameter as [DecodeError].
+func (d *responseDecoder) decodeArray(method string, sink func(interface{}) error) error {
+	if err := d.expect("methodResponse"); err != nil {
+		return err
+	}
+	kind, err := d.nextStart()
+	if err != nil {
+		return err
+	}
+	switch kind.Name.Local {
+	case "fault":
+		return d.decodeFault(method)
+	case "params":
+	default:
+		return fmt.Errorf("%w: unexpected element <%s>", errInvalidXMLRPC, kind.Name.Local)
+	}
+	if err = d.expect("param"); err != nil {
+		return err
+	}
+	if err = d.expect("value"); err != nil {
+		return err
+	}
+
+	start, value, err := d.valueStart()
+	if err != nil {
+		return err
+	}
+	if start == nil || start.Name.Local != "array" {
+		if start != nil {
+			value, err = d.typedValue(start.Name.Local)
+			if err != nil {
+				return err
+			}
+		}
+		return &DecodeError{Method: method, Expected: "array", Value: value}
+	}
+
+	if err = d.expect("data"); err != nil {
+		return err
+	}
+	for {
+		element, err := d.nextStartOrEnd()
+		if err != nil {
+			return err
+		}
+		if element == nil {
+			return nil
+		}
+		if element.Name.Local != "value" {
+			return fmt.Errorf("%w: unexpected element <%s>", errInvalidXMLRPC, element.Name.Local)
+		}
+		item, err := d.value()
+		if err != nil {
+			return err
+		}
+		if err = sink(item); err != nil {
+			return err
+		}
+	}
+}
+
+// decodeFault converts the value of a <fault> element.
+func (d *responseDecoder) decodeFault(method string) error {
+	if err := d.expect("value"); err != nil {
+		return err
+	}
+	value, err := d.value()
+	if err != nil {
+		return err
+	}
+	members, err := asMap(method, value)
+	if err != nil {
+		return err
+	}
+	fault := xmlrpc.FaultError{}
+	if code, ok := members["faultCode"].(int64); ok {
+		fault.Code = int(code)
+	}
+	if message, ok := members["faultString"].(string); ok {
+		fault.String = message
+	}
+	return newCobblerFault(method, fault)
+}
+
+// value decodes the content of a <value> element whose start was already read, including the end of the element.
+func (d *responseDecoder) value() (interface{}, error) {
+	start, value, err := d.valueStart()
+	if err != nil || start == nil {
+		return value, err
+	}
+	if value, err = d.typedValue(start.Name.Local); err != nil {
+		return nil, err
+	}
+	return value, d.skipTo("value")
+}
+
+// valueStart reads the content of a <value> element up to the start of the type element. If the value has no type
+// element, the untyped value is returned as string (or nil if it is empty) and the end of the <value> was consumed.
+func (d *responseDecoder) valueStart() (*xml.StartElement, interface{}, error) {
+	for {
+		token, err := d.token()
+		if err != nil {
+			return nil, nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			return &t, nil, nil
+		case xml.EndElement:
+			return nil, nil, nil
+		case xml.CharData:
+			if text := strings.TrimSpace(string(t)); text != "" {
+				return nil, text, d.skipTo("value")
+			}
+		}
+	}
+}
+
+// typedValue decodes the content of a type element, e.g. <string>, whose start was already read, including the end of
+// the element.
+func (d *responseDecoder) typedValue(kind string) (interface{}, error) {
+	switch kind {
+	case "struct":
+		return d.structValue()
+	case "array":
+		return d.arrayValue()
+	case "nil":
+		return nil, d.skip()
+	}
+
+	text, empty, err := d.text()
+	if err != nil || empty {
+		return nil, err
+	}
+	switch kind {
+	case "string", "base64":
+		return text, nil
+	case "int", "i4", "i8":
+		return strconv.ParseInt(text, 10, 64)
+	case "boolean":
+		return strconv.ParseBool(text)
+	case "double":
+		return strconv.ParseFloat(text, 64)
+	case "dateTime.iso8601":
+		for _, layout := range xmlrpcTimeLayouts {
+			if t, err := time.Parse(layout, text); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: invalid dateTime.iso8601 value %q", errInvalidXMLRPC, text)
+	}
+	return nil, fmt.Errorf("%w: unsupported type <%s>", errInvalidXMLRPC, kind)
+}
+
+// structValue decodes the members of a <struct> element. Like [xmlrpc.Response.Unmarshal], an empty struct is decoded
+// as nil map.
+func (d *responseDecoder) structValue() (interface{}, error) {
+	var members map[string]interface{}
+	for {
+		member, err := d.nextStartOrEnd()
+		if err != nil {
+			return nil, err
+		}
+		if member == nil {
+			return members, nil
+		}
+		if member.Name.Local != "member" {
+			return nil, fmt.Errorf("%w: unexpected element <%s>", errInvalidXMLRPC, member.Name.Local)
+		}
+		if err = d.expect("name"); err != nil {
+			return nil, err
+		}
+		name, err := d.name()
+		if err != nil {
+			return nil, err
+		}
+		if err = d.expect("value"); err != nil {
+			return nil, err
+		}
+		if members == nil {
+			members = make(map[string]interface{})
+		}
+		if members[name], err = d.value(); err != nil {
+			return nil, err
+		}
+		if err = d.skipTo("member"); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// arrayValue decodes the values of an <array> element.
+func (d *responseDecoder) arrayValue() (interface{}, error) {
+	values := make([]interface{}, 0)
+	if err := d.expect("data"); err != nil {
+		return nil, err
+	}
+	for {
+		element, err := d.nextStartOrEnd()
+		if err != nil {
+			return nil, err
+		}
+		if element == nil {
+			break
+		}
+		if element.Name.Local != "value" {
+			return nil, fmt.Errorf("%w: unexpected element <%s>", errInvalidXMLRPC, element.Name.Local)
+		}
+		value, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, d.skipTo("array")
+}
+
+// name reads the content of a <name> element and returns the interned string.
+func (d *responseDecoder) name() (string, error) {
+	text, _, err := d.text()
+	if err != nil {
+		return "", err
+	}
+	if name, ok := d.names[text]; ok {
+		return name, nil
+	}
+	d.names[text] = text
+	return text, nil
+}
+
+// text reads the character data up to the end of the current element. empty reports if the element had no content.
+func (d *responseDecoder) text() (text string, empty bool, err error) {
+	var builder strings.Builder
+	empty = true
+	for {
+		token, err := d.token()
+		if err != nil {
+			return "", false, err
+		}
+		switch t := token.(type) {
+		case xml.CharData:
+			empty = false
+			builder.Write(t)
+		case xml.EndElement:
+			return builder.String(), empty, nil
+		case xml.StartElement:
+			return "", false, fmt.Errorf("%w: unexpected element <%s>", errInvalidXMLRPC, t.Name.Local)
+		}
+	}
+}
+
+// token returns the next raw token. Since the decoder checks the structure of the response itself, the name space
+// handling and the allocations of [xml.Decoder.Token] aren't needed. The end of the input is always unexpected.
+func (d *responseDecoder) token() (xml.Token, error) {
+	token, err := d.RawToken()
+	if errors.Is(err, io.EOF) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return token, err
+}
+
+// skip consumes all tokens up to the end of the element whose start was read last.
+func (d *responseDecoder) skip() error {
+	for depth := 1; depth > 0; {
+		token, err := d.token()
+		if err != nil {
+			return err
+		}
+		switch token.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+	return nil
+}
+
+// expect reads up to the next start element and checks its name.
+func (d *responseDecoder) expect(name string) error {
+	start, err := d.nextStart()
+	if err != nil {
+		return err
+	}
+	if start.Name.Local != name {
+		return fmt.Errorf("%w: expected <%s>, got <%s>", errInvalidXMLRPC, name, start.Name.Local)
+	}
+	return nil
+}
+
+// nextStart skips everything up to the next start element.
+func (d *responseDecoder) nextStart() (*xml.StartElement, error) {
+	for {
+		token, err := d.token()
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return &start, nil
+		}
+	}
+}
+
+// nextStartOrEnd returns the next start element or nil if the current element ends first.
+func (d *responseDecoder) nextStartOrEnd() (*xml.StartElement, error) {
+	for {
+		token, err := d.token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			return &t, nil
+		case xml.EndElement:
+			return nil, nil
+		}
+	}
+}
+
+// skipTo consumes all tokens up to and including the end of the enclosing element with the given name.
+func (d *responseDecoder) skipTo(name string) error {
+	for {
+		token, err := d.token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if err = d.skip(); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			if t.Name.Local == name {
+				return nil
+			}
+		}
+	}
+}
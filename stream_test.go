@@ -0,0 +1,221 @@
+package cobblerclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/kolo/xmlrpc"
+)
+
+func TestResponseDecoderMatchesUnmarshal(t *testing.T) {
+	fixtures := []string{"get-distros", "get-files", "get-images", "get-menus", "get-mgmtclasses", "get-packages",
+		"get-profiles", "get-repos", "get-systems", "get-events", "get-settings", "get-signatures", "version"}
+	for _, fixture := range fixtures {
+		t.Run(fixture, func(t *testing.T) {
+			// Arrange
+			body, err := Fixture(fixture + "-res.xml")
+			FailOnError(t, err)
+			var expected interface{}
+			FailOnError(t, xmlrpc.Response(body).Unmarshal(&expected))
+
+			// Act
+			streamed := make([]interface{}, 0)
+			err = newResponseDecoder(bytes.NewReader(body)).decodeArray(fixture, func(item interface{}) error {
+				streamed = append(streamed, item)
+				return nil
+			})
+
+			// Assert
+			var actual interface{} = streamed
+			var decodeErr *DecodeError
+			if errors.As(err, &decodeErr) {
+				actual = decodeErr.Value
+			} else {
+				FailOnError(t, err)
+			}
+			if !reflect.DeepEqual(expected, actual) {
+				t.Errorf("streamed result differs from the unmarshalled one: %v", deep.Equal(expected, actual))
+			}
+		})
+	}
+}
+
+func TestResponseDecoderFault(t *testing.T) {
+	// Arrange
+	body := "<methodResponse>" + xmlrpcFault("&lt;class 'cobbler.cexceptions.CX'&gt;:'invalid token: expired'") +
+		"</methodResponse>"
+
+	// Act
+	err := newResponseDecoder(strings.NewReader(body)).decodeArray("get_systems", func(interface{}) error {
+		t.Error("no element expected")
+		return nil
+	})
+
+	// Assert
+	var fault *CobblerFault
+	if !errors.As(err, &fault) || fault.Method != "get_systems" {
+		t.Fatalf("expected a CobblerFault, got %v", err)
+	}
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestResponseDecoderInvalid(t *testing.T) {
+	bodies := map[string]string{
+		"proxy-error-page": malformedResponses["proxy-error-page"],
+		"truncated":        "<methodResponse>" + xmlrpcParams("<array><data><value><struct>"),
+		"unsupported-type": "<methodResponse>" + xmlrpcParams("<array><data><value><float>1</float></value></data></array>") +
+			"</methodResponse>",
+	}
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			// Arrange & Act
+			err := newResponseDecoder(strings.NewReader(body)).decodeArray("get_systems", func(interface{}) error {
+				return nil
+			})
+
+			// Assert
+			if err == nil {
+				t.Errorf("expected an error for an invalid response")
+			}
+		})
+	}
+}
+
+func TestStreamStopsOnCallbackError(t *testing.T) {
+	// Arrange
+	stop := errors.New("stop")
+	c := NewClient(rawHTTPClient(systemsResponse(t, 3)), config)
+	c.SetToken("securetoken99")
+	calls := 0
+
+	// Act
+	err := c.EachSystem(func(*System) error {
+		calls++
+		return stop
+	})
+
+	// Assert
+	if err != stop {
+		t.Errorf("expected the error of the callback, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected a single call of the callback, got %d", calls)
+	}
+}
+
+func TestStreamInterceptorResult(t *testing.T) {
+	// Arrange
+	var raw interface{}
+	body, err := Fixture("get-systems-res.xml")
+	FailOnError(t, err)
+	FailOnError(t, xmlrpc.Response(body).Unmarshal(&raw))
+	cfg := config
+	cfg.Interceptors = []Interceptor{func(ctx context.Context, call *RPCCall, next Invoker) (interface{}, error) {
+		return raw, nil
+	}}
+	c := NewClient(rawHTTPClient(malformedResponses["proxy-error-page"]), cfg)
+
+	// Act
+	systems, err := c.GetSystems()
+
+	// Assert
+	FailOnError(t, err)
+	if len(systems) != 1 || systems[0].Name != "test" {
+		t.Errorf("expected the system returned by the interceptor, got %v", systems)
+	}
+}
+
+// systemsResponse returns a "get_systems" response with n copies of the system of the "get-systems" fixture.
+func systemsResponse(tb testing.TB, n int) string {
+	names := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		names = append(names, fmt.Sprintf("system-%d", i))
+	}
+	return "<methodResponse>" + xmlrpcParams(xmlrpcValue(tb, rawSystems(tb, names...))) + "</methodResponse>"
+}
+
+// rawSystems returns copies of the system of the "get-systems" fixture with the given names as returned by the
+// server.
+func rawSystems(tb testing.TB, names ...string) []interface{} {
+	body, err := Fixture("get-systems-res.xml")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	var raw []interface{}
+	if err = xmlrpc.Response(body).Unmarshal(&raw); err != nil {
+		tb.Fatal(err)
+	}
+	systems := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		system := make(map[string]interface{})
+		for key, value := range raw[0].(map[string]interface{}) {
+			system[key] = value
+		}
+		system["name"] = name
+		systems = append(systems, system)
+	}
+	return systems
+}
+
+// xmlrpcValue encodes value as the content of an XML-RPC <value> element.
+func xmlrpcValue(tb testing.TB, value interface{}) string {
+	call, err := xmlrpc.EncodeMethodCall("value", value)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	// The encoder writes nil as "<value/>", which isn't decoded correctly by xmlrpc.Response and never sent by Cobbler.
+	encoded := strings.ReplaceAll(string(call), "<value/>", "<value><string></string></value>")
+	start := strings.Index(encoded, "<param><value>") + len("<param><value>")
+	return encoded[start:strings.LastIndex(encoded, "</value></param>")]
+}
+
+func benchmarkClient(b *testing.B, systems int) Client {
+	body := systemsResponse(b, systems)
+	c := NewClient(rawHTTPClient(body), config)
+	c.SetToken("securetoken99")
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	return c
+}
+
+// BenchmarkGetSystemsBuffered measures the previous implementation of GetSystems, which reads the complete body and
+// unmarshals the complete result before the systems are converted.
+func BenchmarkGetSystemsBuffered(b *testing.B) {
+	c := benchmarkClient(b, 1000)
+	for i := 0; i < b.N; i++ {
+		result, err := c.Call("get_systems", "", c.Token())
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err = c.systems().convertList("get_systems", result); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetSystems(b *testing.B) {
+	c := benchmarkClient(b, 1000)
+	for i := 0; i < b.N; i++ {
+		if _, err := c.GetSystems(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEachSystem(b *testing.B) {
+	c := benchmarkClient(b, 1000)
+	for i := 0; i < b.N; i++ {
+		if err := c.EachSystem(func(*System) error { return nil }); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
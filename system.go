@@ -18,8 +18,6 @@ package cobblerclient
 
 import (
 	"fmt"
-	"log"
-	"reflect"
 	"time"
 
 	"github.com/fatih/structs"
@@ -69,8 +67,6 @@ type System struct {
 	VirtPath              string          `mapstructure:"virt_path"`
 	VirtRAM               Value[int]      `mapstructure:"virt_ram"`
 	VirtType              string          `mapstructure:"virt_type"`
-
-	Client
 }
 
 // Interface is an interface in a system.
@@ -176,123 +172,10 @@ func NewInterface() Interface {
 	}
 }
 
-func (c *Client) convertRawSystem(name string, xmlrpcResult interface{}) (*System, error) {
-	var system System
-
-	if xmlrpcResult == "~" {
-		return nil, fmt.Errorf("system %s not found", name)
-	}
-
-	decodeResult, err := decodeCobblerItem(xmlrpcResult, &system)
-	if err != nil {
-		return nil, err
-	}
-
-	s := decodeResult.(*System)
-	s.Client = *c
-
-	// Now clean the network interface struct
-	if s.Interfaces == nil {
-		s.Interfaces = make(map[string]Interface)
-	}
-	// Now clean the Value structs
-	err = sanitizeValueMapStruct(&s.KernelOptions)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&s.KernelOptionsPost)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&s.AutoinstallMeta)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&s.FetchableFiles)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&s.BootFiles)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&s.TemplateFiles)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueMapStruct(&s.MgmtParameters)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueSliceStruct(&s.Owners)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueSliceStruct(&s.MgmtClasses)
-	if err != nil {
-		return nil, err
-	}
-	err = sanitizeValueSliceStruct(&s.BootLoaders)
-	return s, err
-}
-
-func (c *Client) convertRawSystemsList(xmlrpcResult interface{}) ([]*System, error) {
-	var systems []*System
-
-	for _, s := range xmlrpcResult.([]interface{}) {
-		system, err := c.convertRawSystem("unkown", s)
-		if err != nil {
-			return nil, err
-		}
-		system.Meta = ItemMeta{
-			IsFlattened: false,
-			IsResolved:  false,
-		}
-		systems = append(systems, system)
-	}
-
-	return systems, nil
-}
-
-// GetSystems returns all systems in Cobbler.
-func (c *Client) GetSystems() ([]*System, error) {
-
-	result, err := c.Call("get_systems", "", c.Token)
-	if err != nil {
-		return nil, err
-	}
-
-	return c.convertRawSystemsList(result)
-}
-
-// GetSystem returns a single system obtained by its name.
-func (c *Client) GetSystem(name string, flattened, resolved bool) (*System, error) {
-	result, err := c.getConcreteItem("get_system", name, flattened, resolved)
-	if err != nil {
-		return nil, err
-	}
-
-	system, err := c.convertRawSystem(name, result)
-	if err != nil {
-		return nil, err
-	}
-	system.Meta = ItemMeta{
-		IsFlattened: flattened,
-		IsResolved:  resolved,
-	}
-	return system, nil
-}
-
-// CreateSystem creates a system.
-// It ensures that either a Profile or Image are set and then sets other default values.
-func (c *Client) CreateSystem(system System) (*System, error) {
-	// Check if a system with the same name already exists
-	if _, err := c.GetSystem(system.Name, false, false); err == nil {
-		return nil, fmt.Errorf("a system with the name %s already exists", system.Name)
-	}
-
+// prepareSystem ensures that either a profile or an image is set for a new system and then sets other default values.
+func prepareSystem(system *System) error {
 	if system.Profile == "" && system.Image == "" {
-		return nil, fmt.Errorf("a system must have a profile or image set")
+		return &ValidationError{What: "system", Message: "a system must have a profile or image set"}
 	}
 
 	// Set default values. I guess these aren't taken care of by Cobbler?
@@ -331,60 +214,103 @@ func (c *Client) CreateSystem(system System) (*System, error) {
 	if system.VirtType == "" {
 		system.VirtType = inherit
 	}
+	return nil
+}
 
-	// To create a system via the Cobbler API, first call new_system to obtain an ID
-	result, err := c.Call("new_system", c.Token)
-	if err != nil {
-		return nil, err
-	}
-	newID := result.(string)
+// systems returns the service for systems.
+func (c *Client) systems() *ItemService[System] {
+	s := NewItemService[System](c, "system")
+	s.prepare = prepareSystem
+	s.page = ""
+	return s
+}
 
-	// Set the value of all fields
-	item := reflect.ValueOf(&system).Elem()
-	if err := c.updateCobblerFields("system", item, newID); err != nil {
-		return nil, err
-	}
+// GetSystems returns all systems in Cobbler.
+func (c *Client) GetSystems() ([]*System, error) {
+	return c.systems().GetAll()
+}
 
-	// Save the final system
-	if err := c.SaveSystem(newID, "new"); err != nil {
-		return nil, err
-	}
+// EachSystem calls fn for every system in Cobbler. The systems are decoded one by one while the response is read, thus
+// only a single system is held in memory at a time. The iteration stops at the first error returned by fn, which is
+// returned unchanged.
+func (c *Client) EachSystem(fn func(*System) error) error {
+	return c.systems().Each(fn)
+}
 
-	// Return a clean copy of the system
-	return c.GetSystem(system.Name, false, false)
+// GetSystem returns a single system obtained by its name.
+func (c *Client) GetSystem(name string, flattened, resolved bool) (*System, error) {
+	return c.systems().Get(name, flattened, resolved)
+}
+
+// CreateSystem creates a system.
+// It ensures that either a Profile or Image are set and then sets other default values.
+func (c *Client) CreateSystem(system System) (*System, error) {
+	return c.systems().Create(system)
 }
 
 // UpdateSystem updates a single system.
 func (c *Client) UpdateSystem(system *System) error {
-	item := reflect.ValueOf(system).Elem()
-	id, err := c.GetItemHandle("system", system.Name)
-	if err != nil {
-		return err
-	}
-	return c.updateCobblerFields("system", item, id)
+	return c.systems().Update(system)
+}
+
+// EditSystem starts an edit session for the system with the given name. All modifications are saved at once.
+func (c *Client) EditSystem(name string) (*EditSession[System], error) {
+	return c.systems().Edit(name)
 }
 
 // SaveSystem saves all changes performed via XML-RPC to disk on the server side.
 func (c *Client) SaveSystem(objectId, editmode string) error {
-	_, err := c.Call("save_system", objectId, c.Token, editmode)
-	return err
+	return c.systems().Save(objectId, editmode)
 }
 
-// CopySystem duplicates a given system on the server with a new name.
+// CopySystem duplicates a system on the server with a new name.
 func (c *Client) CopySystem(objectId, newName string) error {
-	_, err := c.Call("copy_system", objectId, newName, c.Token)
-	return err
+	return c.systems().Copy(objectId, newName)
 }
 
-// DeleteSystem deletes a single System by its name.
+// RenameSystem renames a system with a given object id.
+func (c *Client) RenameSystem(objectId, newName string) error {
+	return c.systems().Rename(objectId, newName)
+}
+
+// DeleteSystem deletes a single system by its name.
 func (c *Client) DeleteSystem(name string) error {
-	return c.DeleteSystemRecursive(name, false)
+	return c.systems().Delete(name, false)
 }
 
-// DeleteSystemRecursive deletes a single System by its name with the option to do so recursively.
+// DeleteSystemRecursive deletes a single system by its name with the option to do so recursively.
 func (c *Client) DeleteSystemRecursive(name string, recursive bool) error {
-	_, err := c.Call("remove_system", name, c.Token, recursive)
-	return err
+	return c.systems().Delete(name, recursive)
+}
+
+// ListSystemNames returns a list of all system names currently available in Cobbler.
+func (c *Client) ListSystemNames() ([]string, error) {
+	return c.systems().ListNames()
+}
+
+// FindSystem searches for one or more systems by any of its attributes.
+func (c *Client) FindSystem(criteria map[string]interface{}) ([]*System, error) {
+	return c.systems().Find(criteria)
+}
+
+// FindSystemNames searches for one or more systems by any of its attributes.
+func (c *Client) FindSystemNames(criteria map[string]interface{}) ([]string, error) {
+	return c.systems().FindNames(criteria)
+}
+
+// GetSystemsSince returns all systems which were modified after the specified date.
+func (c *Client) GetSystemsSince(mtime time.Time) ([]*System, error) {
+	return c.systems().Since(mtime)
+}
+
+// GetSystemHandle gets the internal ID of a Cobbler item.
+func (c *Client) GetSystemHandle(name string) (string, error) {
+	return c.systems().Handle(name)
+}
+
+// GetSystemAsRendered returns the datastructure after it has passed through Cobblers inheritance structure.
+func (c *Client) GetSystemAsRendered(name string) (map[string]interface{}, error) {
+	return c.systems().AsRendered(name)
 }
 
 func makeInterfaceOptionsMap(name string, iface Interface) map[string]interface{} {
@@ -392,53 +318,56 @@ func makeInterfaceOptionsMap(name string, iface Interface) map[string]interface{
 	nic := make(map[string]interface{})
 	for key, value := range i {
 		attrName := fmt.Sprintf("%s-%s", key, name)
-		log.Printf("[DEBUG] Cobblerclient: setting interface attr %s to %s", attrName, value)
 		nic[attrName] = value
 	}
 	return nic
 }
 
 func (c *Client) ModifyInterface(systemID string, nic map[string]interface{}) error {
-	editUncasted, err := c.Call("modify_system", systemID, "modify_interface", nic, c.Token)
+	editUncasted, err := c.Call("modify_system", systemID, "modify_interface", nic, c.Token())
+	editSuccessful, err := returnBool("modify_system", editUncasted, err)
 	if err != nil {
 		return err
 	}
-	ok, editSuccessful := editUncasted.(bool)
-	if !ok {
-		return fmt.Errorf("editing interfaces of system %s failed due to an invalid return value of the server", systemID)
-	}
 	if !editSuccessful {
-		return fmt.Errorf("editing interface of system %s failed", systemID)
+		return &ValidationError{What: "system", Field: "interfaces", Message: fmt.Sprintf("editing interface of system %s failed", systemID)}
 	}
 	return nil
 }
 
-// CreateInterface creates network interfaces in Cobbler
-func (s *System) CreateInterface(name string, iface Interface) error {
+// CreateInterface creates or updates the network interface name of the given system in Cobbler and saves the system
+// right away. On success the interface is also stored in the Interfaces of the system. Use [Client.EditSystem] to save
+// several modifications at once.
+func (c *Client) CreateInterface(system *System, name string, iface Interface) error {
 	nic := makeInterfaceOptionsMap(name, iface)
 
-	systemID, err := s.Client.GetItemHandle("system", s.Name)
+	systemID, err := c.GetItemHandle("system", system.Name)
 	if err != nil {
 		return err
 	}
 
-	err = s.Client.ModifyInterface(systemID, nic)
+	err = c.ModifyInterface(systemID, nic)
 	if err != nil {
 		return err
 	}
 
 	// Save the final system
-	err = s.Client.SaveSystem(systemID, "bypass")
+	err = c.SaveSystem(systemID, EditModeBypass)
 	if err != nil {
 		return err
 	}
 
+	if system.Interfaces == nil {
+		system.Interfaces = make(Interfaces)
+	}
+	system.Interfaces[name] = iface
+	system.Meta.snapshot = system.Meta.snapshot.with(fieldUpdate{field: "modify_interface", value: nic, nic: name})
 	return nil
 }
 
-// ModifyNetworkInterface updates the attributes of an existing interface
-func (s *System) ModifyNetworkInterface(name string, iface Interface) error {
-	return s.CreateInterface(name, iface)
+// ModifyNetworkInterface updates the attributes of an existing interface of the given system.
+func (c *Client) ModifyNetworkInterface(system *System, name string, iface Interface) error {
+	return c.CreateInterface(system, name, iface)
 }
 
 // GetInterfaces returns all interfaces in a System.
@@ -470,45 +399,45 @@ func (s *System) GetInterface(name string) (Interface, error) {
 	if iface, ok := nics[name]; ok {
 		return iface, nil
 	} else {
-		return iface, fmt.Errorf("interface %s not found", name)
+		return iface, notFoundError("interface", name)
 	}
 }
 
 func (c *Client) DeleteNetworkInterface(systemID, name string) error {
-	editUncasted, err := c.Call("modify_system", systemID, "delete_interface", name, c.Token)
+	editUncasted, err := c.Call("modify_system", systemID, "delete_interface", name, c.Token())
+	editSuccessful, err := returnBool("modify_system", editUncasted, err)
 	if err != nil {
 		return err
 	}
-	ok, editSuccessful := editUncasted.(bool)
-	if !ok {
-		return fmt.Errorf("deleting interfaces of system %s failed due to an invalid return value of the server", systemID)
-	}
 	if !editSuccessful {
-		return fmt.Errorf("deleting interface of system %s failed", systemID)
+		return &ValidationError{What: "system", Field: "interfaces", Message: fmt.Sprintf("deleting interface of system %s failed", systemID)}
 	}
 	return nil
 }
 
-// DeleteInterface deletes a single interface in a System.
-func (s *System) DeleteInterface(name string) error {
-	if _, err := s.GetInterface(name); err != nil {
+// DeleteInterface deletes a single interface of the given system in Cobbler. On success the interface is also removed
+// from the Interfaces of the system.
+func (c *Client) DeleteInterface(system *System, name string) error {
+	if _, err := system.GetInterface(name); err != nil {
 		return err
 	}
 
-	systemID, err := s.Client.GetItemHandle("system", s.Name)
+	systemID, err := c.GetItemHandle("system", system.Name)
 	if err != nil {
 		return err
 	}
 
-	if err = s.Client.DeleteNetworkInterface(systemID, name); err != nil {
+	if err = c.DeleteNetworkInterface(systemID, name); err != nil {
 		return err
 	}
 
 	// Save the final system
-	if err = s.Client.SaveSystem(systemID, "bypass"); err != nil {
+	if err = c.SaveSystem(systemID, EditModeBypass); err != nil {
 		return err
 	}
 
+	delete(system.Interfaces, name)
+	system.Meta.snapshot = system.Meta.snapshot.without(interfaceKeyPrefix + name)
 	return nil
 }
 
@@ -520,71 +449,29 @@ func (c *Client) RenameNetworkInterface(systemName, oldName, newName string) err
 	args := make(map[string]string)
 	args["interface"] = oldName
 	args["rename_interface"] = newName
-	unparsedOk, err := c.Call("modify_system", systemID, "rename_interface", args, c.Token)
+	unparsedOk, err := c.Call("modify_system", systemID, "rename_interface", args, c.Token())
+	ok, err := returnBool("modify_system", unparsedOk, err)
 	if err != nil {
 		return err
 	}
-	conversionSuccess, ok := unparsedOk.(bool)
-	if !conversionSuccess {
-		return fmt.Errorf("failed to convert return value of interface rename")
-	}
 	if !ok {
 		return fmt.Errorf("failed to rename interface %s to %s", oldName, newName)
 	}
 	return nil
 }
 
-// RenameInterface renames an Interface.
-func (s *System) RenameInterface(name string, newName string) error {
-	err := s.Client.RenameNetworkInterface(s.Name, name, newName)
+// RenameInterface renames an interface of the given system in Cobbler. On success the interface is also renamed in the
+// Interfaces of the system.
+func (c *Client) RenameInterface(system *System, name string, newName string) error {
+	err := c.RenameNetworkInterface(system.Name, name, newName)
 	if err != nil {
 		return err
 	}
-	iface := s.Interfaces[name]
-	delete(s.Interfaces, name)
-	s.Interfaces[newName] = iface
-	return nil
-}
-
-// ListSystemNames returns a list of all system names currently available in Cobbler.
-func (c *Client) ListSystemNames() ([]string, error) {
-	return c.GetItemNames("system")
-}
-
-// FindSystem searches for one or more systems by any of its attributes.
-func (c *Client) FindSystem(criteria map[string]interface{}) ([]*System, error) {
-	result, err := c.Call("find_system", criteria, true, c.Token)
-	if err != nil {
-		return nil, err
+	if iface, ok := system.Interfaces[name]; ok {
+		delete(system.Interfaces, name)
+		system.Interfaces[newName] = iface
+		renamed := fieldUpdate{field: "modify_interface", value: makeInterfaceOptionsMap(newName, iface), nic: newName}
+		system.Meta.snapshot = system.Meta.snapshot.without(interfaceKeyPrefix + name).with(renamed)
 	}
-
-	return c.convertRawSystemsList(result)
-}
-
-// FindSystemNames searches for one or more systems by any of its attributes.
-func (c *Client) FindSystemNames(criteria map[string]interface{}) ([]string, error) {
-	resultUnmarshalled, err := c.Call("find_system", criteria, false, c.Token)
-	return returnStringSlice(resultUnmarshalled, err)
-}
-
-// GetSystemsSince returns all systems which were created after the specified date.
-func (c *Client) GetSystemsSince(mtime time.Time) ([]*System, error) {
-	result, err := c.Call("get_systems_since", float64(mtime.Unix()))
-	if err != nil {
-		return nil, err
-	}
-
-	return c.convertRawSystemsList(result)
-}
-
-// RenameSystem renames a System with a given object id.
-func (c *Client) RenameSystem(objectId, newName string) error {
-	_, err := c.Call("rename_system", objectId, newName, c.Token)
-	return err
-}
-
-// GetSystemHandle gets the internal ID of a Cobbler item.
-func (c *Client) GetSystemHandle(name string) (string, error) {
-	res, err := c.Call("get_system_handle", name, c.Token)
-	return returnString(res, err)
+	return nil
 }
@@ -17,8 +17,11 @@ limitations under the License.
 package cobblerclient
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/go-test/deep"
+	"strings"
 	"testing"
 	"time"
 )
@@ -46,7 +49,7 @@ func TestGetSystems(t *testing.T) {
 func TestGetSystem(t *testing.T) {
 	// Arrange
 	c := createStubHTTPClientSingle(t, "get-system")
-	c.CachedVersion = CobblerVersion{3, 3, 2}
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
 
 	// Act
 	system, err := c.GetSystem("test", false, false)
@@ -58,6 +61,36 @@ func TestGetSystem(t *testing.T) {
 	}
 }
 
+func TestGetSystemNotFound(t *testing.T) {
+	// Arrange
+	c := createStubHTTPClientSingle(t, "create-system-name-check")
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
+
+	// Act
+	_, err := c.GetSystem("mytestsystem", false, false)
+
+	// Assert
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSystemCreateWithoutProfile(t *testing.T) {
+	// Arrange
+	c := createStubHTTPClientSingle(t, "create-system-name-check")
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
+	sys := NewSystem()
+	sys.Name = "mytestsystem"
+
+	// Act
+	_, err := c.CreateSystem(sys)
+
+	// Assert
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation, got %v", err)
+	}
+}
+
 func TestSystemCreate(t *testing.T) {
 	// Arrange
 	c := createStubHTTPClient(t, []string{
@@ -112,7 +145,7 @@ func TestSystemCreate(t *testing.T) {
 		"new-system-save",
 		"new-system-get",
 	})
-	c.CachedVersion = CobblerVersion{3, 3, 2}
+	c.setCachedVersion(CobblerVersion{3, 3, 2})
 	sys := NewSystem()
 	sys.Name = "mytestsystem"
 	sys.Hostname = "blahhost"
@@ -214,6 +247,30 @@ func TestGetSystemHandle(t *testing.T) {
 	}
 }
 
+func TestSystemWithoutCredentials(t *testing.T) {
+	// Arrange
+	c := createStubHTTPClient(t, []string{
+		"extended-version",
+		"get-interfaces-get-system",
+	})
+	c.config.Password = "s3cr3t-password"
+	testsys, err := c.GetSystem("testsys", false, false)
+	FailOnError(t, err)
+
+	// Act
+	printed := fmt.Sprintf("%+v", testsys)
+	encoded, err := json.Marshal(testsys)
+
+	// Assert
+	FailOnError(t, err)
+	if strings.Contains(printed, c.config.Password) || strings.Contains(string(encoded), c.config.Password) {
+		t.Fatal("the system leaks the credentials of the client")
+	}
+	if strings.Contains(printed, "securetoken99") || strings.Contains(string(encoded), "securetoken99") {
+		t.Fatal("the system leaks the token of the client")
+	}
+}
+
 func TestCreateInterface(t *testing.T) {
 	// Arrange
 	c := createStubHTTPClient(t, []string{
@@ -228,10 +285,13 @@ func TestCreateInterface(t *testing.T) {
 	testinterface := NewInterface()
 
 	// Act
-	err = testsys.CreateInterface("eth0", testinterface)
+	err = c.CreateInterface(testsys, "eth0", testinterface)
 
 	// Assert
 	FailOnError(t, err)
+	if _, ok := testsys.Interfaces["eth0"]; !ok {
+		t.Fatal("the interface wasn't added to the system")
+	}
 }
 
 func TestModifyInterface(t *testing.T) {
@@ -306,10 +366,13 @@ func TestDeleteInterface(t *testing.T) {
 	FailOnError(t, err)
 
 	// Act
-	err = testsys.DeleteInterface("default")
+	err = c.DeleteInterface(testsys, "default")
 
 	// Assert
 	FailOnError(t, err)
+	if _, ok := testsys.Interfaces["default"]; ok {
+		t.Fatal("the interface wasn't removed from the system")
+	}
 }
 
 func TestRenameInterface(t *testing.T) {
@@ -324,7 +387,7 @@ func TestRenameInterface(t *testing.T) {
 	FailOnError(t, err)
 
 	// Act
-	err = testsys.RenameInterface("", "")
+	err = c.RenameInterface(testsys, "", "")
 
 	// Assert
 	FailOnError(t, err)
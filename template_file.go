@@ -27,7 +27,7 @@ type TemplateFile struct {
 // Requires 3 arguments: file, data and token
 // Returns true/false and error if creation failed.
 func (c *Client) CreateTemplateFile(f TemplateFile) error {
-	_, err := c.Call("write_autoinstall_template", f.Name, f.Body, c.Token) // TODO: check name
+	_, err := c.Call("write_autoinstall_template", f.Name, f.Body, c.Token()) // TODO: check name
 	return err
 }
 
@@ -36,15 +36,19 @@ func (c *Client) CreateTemplateFile(f TemplateFile) error {
 // Requires 2 arguments: short filename and token
 // Returns *TemplateFile and error if read failed.
 func (c *Client) GetTemplateFile(ksName string) (*TemplateFile, error) {
-	result, err := c.Call("read_autoinstall_template", ksName, c.Token) // TODO: check name
+	result, err := c.Call("read_autoinstall_template", ksName, c.Token()) // TODO: check name
 
+	if err != nil {
+		return nil, err
+	}
+	body, err := asString("read_autoinstall_template", result)
 	if err != nil {
 		return nil, err
 	}
 
 	ks := TemplateFile{
 		Name: ksName,
-		Body: result.(string),
+		Body: body,
 	}
 
 	return &ks, nil
@@ -55,6 +59,6 @@ func (c *Client) GetTemplateFile(ksName string) (*TemplateFile, error) {
 // Requires 2 arguments: short filename and token
 // Returns error if delete failed.
 func (c *Client) DeleteTemplateFile(name string) error {
-	_, err := c.Call("remove_autoinstall_template", name, c.Token) // TODO: check name
+	_, err := c.Call("remove_autoinstall_template", name, c.Token()) // TODO: check name
 	return err
 }
@@ -0,0 +1,75 @@
+package cobblerclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// NewClientFromConfig creates a [Client] with an [http.Client] whose transport is configured with the TLS and proxy
+// settings of c. Use [NewClient] to supply your own [HTTPClient] instead.
+func NewClientFromConfig(c ClientConfig) (Client, error) {
+	transport, err := newTransport(c)
+	if err != nil {
+		return Client{}, err
+	}
+	return NewClient(&http.Client{Transport: transport}, c), nil
+}
+
+// newTransport clones [http.DefaultTransport] and applies the TLS and proxy settings of c.
+func newTransport(c ClientConfig) (*http.Transport, error) {
+	tlsConfig, err := newTLSConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	if c.ProxyURL != "" {
+		proxyURL, err := url.Parse(c.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("cobblerclient: invalid proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	return transport, nil
+}
+
+// newTLSConfig builds the TLS configuration for the CA bundle, the client certificate and the verification settings
+// of c.
+func newTLSConfig(c ClientConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.InsecureSkipVerify, // #nosec G402 -- explicitly requested for lab servers
+	}
+
+	if c.CACertFile != "" {
+		pemCerts, err := os.ReadFile(c.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("cobblerclient: reading CA bundle: %w", err)
+		}
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(pemCerts) {
+			return nil, fmt.Errorf("cobblerclient: no certificates found in %s", c.CACertFile)
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	if (c.ClientCertFile == "") != (c.ClientKeyFile == "") {
+		return nil, errors.New("cobblerclient: client certificate and key must be set together")
+	}
+	if c.ClientCertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(c.ClientCertFile, c.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cobblerclient: loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,224 @@
+package cobblerclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const pingResponse = "<?xml version='1.0'?>\n<methodResponse><params><param><value><boolean>1</boolean></value>" +
+	"</param></params></methodResponse>"
+
+func pingHandler(w http.ResponseWriter, _ *http.Request) {
+	_, _ = w.Write([]byte(pingResponse))
+}
+
+// writeTestCertificate creates a self-signed certificate for 127.0.0.1 that is valid for servers and clients. It
+// returns the paths of the PEM encoded certificate and key.
+func writeTestCertificate(t *testing.T) (string, string, tls.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	FailOnError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "cobbler-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	FailOnError(t, err)
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	FailOnError(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer})
+	FailOnError(t, os.WriteFile(certFile, certPEM, 0o600))
+	FailOnError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	FailOnError(t, err)
+	return certFile, keyFile, certificate
+}
+
+// newTestTLSServer starts a server that answers every call with a successful ping.
+func newTestTLSServer(t *testing.T, certificate tls.Certificate, clientCAs *x509.CertPool) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(pingHandler))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{certificate}}
+	if clientCAs != nil {
+		server.TLS.ClientAuth = tls.RequireAndVerifyClientCert
+		server.TLS.ClientCAs = clientCAs
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestNewClientFromConfigCACert(t *testing.T) {
+	// Arrange
+	certFile, _, certificate := writeTestCertificate(t)
+	server := newTestTLSServer(t, certificate, nil)
+	untrusted, err := NewClientFromConfig(ClientConfig{URL: server.URL})
+	FailOnError(t, err)
+	trusted, err := NewClientFromConfig(ClientConfig{URL: server.URL, CACertFile: certFile})
+	FailOnError(t, err)
+
+	// Act
+	_, untrustedErr := untrusted.Ping()
+	ok, trustedErr := trusted.Ping()
+
+	// Assert
+	if untrustedErr == nil {
+		t.Errorf("expected the certificate of the server to be rejected without the CA bundle")
+	}
+	FailOnError(t, trustedErr)
+	if !ok {
+		t.Errorf("true expected; got false")
+	}
+}
+
+func TestNewClientFromConfigInsecureSkipVerify(t *testing.T) {
+	// Arrange
+	_, _, certificate := writeTestCertificate(t)
+	server := newTestTLSServer(t, certificate, nil)
+	c, err := NewClientFromConfig(ClientConfig{URL: server.URL, InsecureSkipVerify: true})
+	FailOnError(t, err)
+
+	// Act
+	_, err = c.Ping()
+
+	// Assert
+	FailOnError(t, err)
+}
+
+func TestNewClientFromConfigClientCertificate(t *testing.T) {
+	// Arrange
+	certFile, keyFile, certificate := writeTestCertificate(t)
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	FailOnError(t, err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(leaf)
+	server := newTestTLSServer(t, certificate, clientCAs)
+	withoutCert, err := NewClientFromConfig(ClientConfig{URL: server.URL, CACertFile: certFile})
+	FailOnError(t, err)
+	withCert, err := NewClientFromConfig(ClientConfig{
+		URL:            server.URL,
+		CACertFile:     certFile,
+		ClientCertFile: certFile,
+		ClientKeyFile:  keyFile,
+	})
+	FailOnError(t, err)
+
+	// Act
+	_, withoutCertErr := withoutCert.Ping()
+	_, withCertErr := withCert.Ping()
+
+	// Assert
+	if withoutCertErr == nil {
+		t.Errorf("expected the server to reject the client without a certificate")
+	}
+	FailOnError(t, withCertErr)
+}
+
+func TestNewClientFromConfigProxy(t *testing.T) {
+	// Arrange
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+		pingHandler(w, r)
+	}))
+	defer proxy.Close()
+	c, err := NewClientFromConfig(ClientConfig{URL: "http://cobbler.example.com/cobbler_api", ProxyURL: proxy.URL})
+	FailOnError(t, err)
+
+	// Act
+	_, err = c.Ping()
+
+	// Assert
+	FailOnError(t, err)
+	if proxiedHost != "cobbler.example.com" {
+		t.Errorf(`"cobbler.example.com" expected; got "%s"`, proxiedHost)
+	}
+}
+
+func TestNewClientFromConfigInvalid(t *testing.T) {
+	certFile, _, _ := writeTestCertificate(t)
+	invalidConfigs := map[string]ClientConfig{
+		"missing-ca-file":    {CACertFile: filepath.Join(t.TempDir(), "missing.pem")},
+		"missing-client-key": {ClientCertFile: certFile},
+		"invalid-proxy":      {ProxyURL: "://proxy"},
+	}
+	for name, cfg := range invalidConfigs {
+		t.Run(name, func(t *testing.T) {
+			// Arrange & Act
+			_, err := NewClientFromConfig(cfg)
+
+			// Assert
+			if err == nil {
+				t.Errorf("expected an error for an invalid configuration")
+			}
+		})
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	// Arrange
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+		pingHandler(w, r)
+	}))
+	defer server.Close()
+	c := NewClient(server.Client(), ClientConfig{URL: server.URL, UserAgent: "terraform-provider-cobbler/1.0"})
+
+	// Act
+	_, err := c.Ping()
+
+	// Assert
+	FailOnError(t, err)
+	if userAgent != "terraform-provider-cobbler/1.0" {
+		t.Errorf(`"terraform-provider-cobbler/1.0" expected; got "%s"`, userAgent)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	// Arrange
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	c := NewClient(server.Client(), ClientConfig{URL: server.URL, Timeout: 50 * time.Millisecond})
+
+	// Act
+	start := time.Now()
+	_, err := c.Ping()
+
+	// Assert
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to be exceeded, got %v", err)
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Errorf("the timeout didn't interrupt the call")
+	}
+}
@@ -4,52 +4,120 @@ import (
 	"errors"
 )
 
-func returnString(res interface{}, err error) (string, error) {
+// returnString converts the result of method to a string. An error of the call is returned unmodified.
+func returnString(method string, res interface{}, err error) (string, error) {
 	if err != nil {
 		return "", err
-	} else {
-		return res.(string), err
 	}
+	return asString(method, res)
 }
 
-func returnStringSlice(res interface{}, err error) ([]string, error) {
+// returnStringSlice converts the result of method to a slice of strings. An error of the call is returned unmodified.
+func returnStringSlice(method string, res interface{}, err error) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	return asStringSlice(method, res)
+}
 
-	resConverted, ok := res.([]interface{})
+// returnIntSlice converts the result of method to a slice of integers. An error of the call is returned unmodified.
+func returnIntSlice(method string, res interface{}, err error) ([]int, error) {
+	if err != nil {
+		return nil, err
+	}
+	return asIntSlice(method, res)
+}
+
+// returnBool converts the result of method to a bool. An error of the call is returned unmodified.
+func returnBool(method string, res interface{}, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	return asBool(method, res)
+}
 
+// asString returns value as string or a [DecodeError] for method if value has a different type.
+func asString(method string, value interface{}) (string, error) {
+	converted, ok := value.(string)
 	if !ok {
-		return nil, errors.New("result is not a slice")
+		return "", &DecodeError{Method: method, Expected: "string", Value: value}
 	}
+	return converted, nil
+}
 
-	return convertToStringSlice(resConverted)
+// asBool returns value as bool or a [DecodeError] for method if value has a different type.
+func asBool(method string, value interface{}) (bool, error) {
+	converted, ok := value.(bool)
+	if !ok {
+		return false, &DecodeError{Method: method, Expected: "boolean", Value: value}
+	}
+	return converted, nil
 }
 
-func returnIntSlice(res interface{}, err error) ([]int, error) {
-	var result []int
+// asInt returns value as int or a [DecodeError] for method if value isn't an integer.
+func asInt(method string, value interface{}) (int, error) {
+	converted, err := convertToInt(value)
+	if err != nil {
+		return -1, &DecodeError{Method: method, Expected: "integer", Value: value}
+	}
+	return converted, nil
+}
 
+// asFloat returns value as float64 or a [DecodeError] for method if value isn't a floating point number.
+func asFloat(method string, value interface{}) (float64, error) {
+	converted, err := convertToFloat(value)
 	if err != nil {
-		return nil, err
+		return -1, &DecodeError{Method: method, Expected: "double", Value: value}
 	}
+	return converted, nil
+}
 
-	for _, name := range res.([]interface{}) {
-		var parsedInt, err = convertToInt(name)
-		if err != nil {
-			return nil, err
-		}
-		result = append(result, parsedInt)
+// asSlice returns value as array or a [DecodeError] for method if value has a different type.
+func asSlice(method string, value interface{}) ([]interface{}, error) {
+	converted, ok := value.([]interface{})
+	if !ok {
+		return nil, &DecodeError{Method: method, Expected: "array", Value: value}
+	}
+	return converted, nil
+}
+
+// asMap returns value as struct or a [DecodeError] for method if value has a different type.
+func asMap(method string, value interface{}) (map[string]interface{}, error) {
+	converted, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, &DecodeError{Method: method, Expected: "struct", Value: value}
 	}
+	return converted, nil
+}
 
+// asStringSlice returns value as slice of strings or a [DecodeError] for method if value has a different shape.
+func asStringSlice(method string, value interface{}) ([]string, error) {
+	data, err := asSlice(method, value)
+	if err != nil {
+		return nil, err
+	}
+	result, err := convertToStringSlice(data)
+	if err != nil {
+		return nil, &DecodeError{Method: method, Expected: "array of strings", Value: value}
+	}
 	return result, nil
 }
 
-func returnBool(res interface{}, err error) (bool, error) {
+// asIntSlice returns value as slice of integers or a [DecodeError] for method if value has a different shape.
+func asIntSlice(method string, value interface{}) ([]int, error) {
+	data, err := asSlice(method, value)
 	if err != nil {
-		return false, err
-	} else {
-		return res.(bool), err
+		return nil, err
 	}
+	result := make([]int, 0, len(data))
+	for _, element := range data {
+		parsedInt, err := convertToInt(element)
+		if err != nil {
+			return nil, &DecodeError{Method: method, Expected: "array of integers", Value: value}
+		}
+		result = append(result, parsedInt)
+	}
+	return result, nil
 }
 
 func convertToStringSlice(data []interface{}) ([]string, error) {
@@ -101,6 +169,8 @@ func convertToFloat(float interface{}) (float64, error) {
 
 func convertToInt(integer interface{}) (int, error) {
 	switch integer.(type) {
+	case int:
+		return integer.(int), nil
 	case int8:
 		return int(integer.(int8)), nil
 	case int16:
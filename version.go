@@ -51,30 +51,34 @@ func (c *Client) Version() (float64, error) {
 	if err != nil {
 		return 0, err
 	}
-	return res.(float64), err
+	return asFloat("version", res)
 }
 
 // ExtendedVersion returns the version information of the server.
 func (c *Client) ExtendedVersion() (ExtendedVersion, error) {
 	extendedVersion := ExtendedVersion{}
-	data, err := c.Call("extended_version")
+	res, err := c.Call("extended_version")
 	if err != nil {
 		return extendedVersion, err
 	}
-	switch data.(type) {
-	case map[string]interface{}:
-		data := data.(map[string]interface{})
-		var versionTuple, err = returnIntSlice(data["version_tuple"], err)
-		if err != nil {
-			return extendedVersion, err
-		}
-		extendedVersion.Version = data["version"].(string)
-		extendedVersion.VersionTuple = versionTuple
-		extendedVersion.Builddate = data["builddate"].(string)
-		extendedVersion.Gitdate = data["gitdate"].(string)
-		extendedVersion.Gitstamp = data["gitstamp"].(string)
-	default:
+	data, err := asMap("extended_version", res)
+	if err != nil {
 		return extendedVersion, err
 	}
-	return extendedVersion, err
+	versionTuple, err := asIntSlice("extended_version", data["version_tuple"])
+	if err != nil {
+		return extendedVersion, err
+	}
+	extendedVersion.VersionTuple = versionTuple
+	for key, target := range map[string]*string{
+		"version":   &extendedVersion.Version,
+		"builddate": &extendedVersion.Builddate,
+		"gitdate":   &extendedVersion.Gitdate,
+		"gitstamp":  &extendedVersion.Gitstamp,
+	} {
+		if *target, err = asString("extended_version", data[key]); err != nil {
+			return ExtendedVersion{}, err
+		}
+	}
+	return extendedVersion, nil
 }